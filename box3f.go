@@ -0,0 +1,273 @@
+package vmath
+
+import (
+	"fmt"
+
+	"github.com/maja42/vmath/math32"
+)
+
+// Box3f represents a 3D, axis-aligned box.
+type Box3f struct {
+	Min Vec3f
+	Max Vec3f
+}
+
+// Box3fFromCorners creates a new box given two opposite corners.
+// If necessary, coordinates are swapped to create a normalized box.
+func Box3fFromCorners(c1, c2 Vec3f) Box3f {
+	for i := range c1 {
+		if c1[i] > c2[i] {
+			c1[i], c2[i] = c2[i], c1[i]
+		}
+	}
+	return Box3f{c1, c2}
+}
+
+// Box3fFromPosSize creates a new box with the given size and position.
+// Negative dimensions are inverted to create a normalized box.
+func Box3fFromPosSize(pos, size Vec3f) Box3f {
+	for i := range size {
+		if size[i] < 0 {
+			size[i] = -size[i]
+			pos[i] -= size[i]
+		}
+	}
+	return Box3f{
+		pos,
+		pos.Add(size),
+	}
+}
+
+// Box3fFromPoints creates the smallest box that contains all given points.
+// If no points are given, an empty box at the origin is returned.
+func Box3fFromPoints(points []Vec3f) Box3f {
+	if len(points) == 0 {
+		return Box3f{}
+	}
+	b := Box3f{points[0], points[0]}
+	for _, p := range points[1:] {
+		b = b.ExtendPoint(p)
+	}
+	return b
+}
+
+// Normalize ensures that the Min position is smaller than the Max position in every dimension.
+func (b Box3f) Normalize() Box3f {
+	for i := range b.Min {
+		if b.Min[i] > b.Max[i] {
+			b.Min[i], b.Max[i] = b.Max[i], b.Min[i]
+		}
+	}
+	return b
+}
+
+func (b Box3f) String() string {
+	return fmt.Sprintf("Box3f([%f x %f x %f]-[%f x %f x %f])",
+		b.Min[0], b.Min[1], b.Min[2],
+		b.Max[0], b.Max[1], b.Max[2])
+}
+
+// Box3i returns an integer representation of the box.
+// Decimals are truncated.
+func (b Box3f) Box3i() Box3i {
+	return Box3i{
+		b.Min.Vec3i(),
+		b.Max.Vec3i(),
+	}
+}
+
+// Round returns an integer representation of the box.
+// Decimals are rounded.
+func (b Box3f) Round() Box3i {
+	return Box3i{
+		b.Min.Round(),
+		b.Max.Round(),
+	}
+}
+
+// Size returns the box's dimensions.
+func (b Box3f) Size() Vec3f {
+	return b.Max.Sub(b.Min)
+}
+
+// Volume returns the box's volume.
+func (b Box3f) Volume() float32 {
+	size := b.Max.Sub(b.Min)
+	return size[0] * size[1] * size[2]
+}
+
+// SurfaceArea returns the box's surface area.
+func (b Box3f) SurfaceArea() float32 {
+	size := b.Max.Sub(b.Min)
+	return 2 * (size[0]*size[1] + size[1]*size[2] + size[2]*size[0])
+}
+
+// Center returns the box's center position.
+func (b Box3f) Center() Vec3f {
+	return b.Min.Add(b.Max).MulScalar(0.5)
+}
+
+// HalfExtents returns half of the box's dimensions.
+func (b Box3f) HalfExtents() Vec3f {
+	return b.Max.Sub(b.Min).MulScalar(0.5)
+}
+
+// Corners returns all eight corners of the box.
+// The first four corners are on the bottom (smaller Z), ordered counter-clockwise starting at Min.
+// The last four corners are on the top, in the same order.
+func (b Box3f) Corners() [8]Vec3f {
+	return [8]Vec3f{
+		{b.Min[0], b.Min[1], b.Min[2]},
+		{b.Max[0], b.Min[1], b.Min[2]},
+		{b.Max[0], b.Max[1], b.Min[2]},
+		{b.Min[0], b.Max[1], b.Min[2]},
+		{b.Min[0], b.Min[1], b.Max[2]},
+		{b.Max[0], b.Min[1], b.Max[2]},
+		{b.Max[0], b.Max[1], b.Max[2]},
+		{b.Min[0], b.Max[1], b.Max[2]},
+	}
+}
+
+// Add moves the box with the given vector by adding it to the min- and max- components.
+func (b Box3f) Add(v Vec3f) Box3f {
+	return Box3f{
+		Min: b.Min.Add(v),
+		Max: b.Max.Add(v),
+	}
+}
+
+// Sub moves the box with the given vector by subtracting it to the min- and max- components.
+func (b Box3f) Sub(v Vec3f) Box3f {
+	return Box3f{
+		Min: b.Min.Sub(v),
+		Max: b.Max.Sub(v),
+	}
+}
+
+// Grow enlarges the box by the given amount in every direction.
+// Negative values shrink the box.
+func (b Box3f) Grow(amount float32) Box3f {
+	return Box3f{
+		Min: b.Min.SubScalar(amount),
+		Max: b.Max.AddScalar(amount),
+	}
+}
+
+// Intersects checks if this box intersects another box.
+// Touching boxes where floats are exactly equal are also considered to intersect.
+func (b Box3f) Intersects(other Box3f) bool {
+	return b.Min[0] <= other.Max[0] &&
+		b.Max[0] >= other.Min[0] &&
+		b.Min[1] <= other.Max[1] &&
+		b.Max[1] >= other.Min[1] &&
+		b.Min[2] <= other.Max[2] &&
+		b.Max[2] >= other.Min[2]
+}
+
+// ContainsPoint checks if a given point resides within the box.
+// If the point is on a face, it is also considered to be contained within the box.
+func (b Box3f) ContainsPoint(point Vec3f) bool {
+	return point[0] >= b.Min[0] && point[0] <= b.Max[0] &&
+		point[1] >= b.Min[1] && point[1] <= b.Max[1] &&
+		point[2] >= b.Min[2] && point[2] <= b.Max[2]
+}
+
+// ContainsBox3f checks if this box completely contains another box.
+func (b Box3f) ContainsBox3f(other Box3f) bool {
+	return b.Min[0] <= other.Min[0] &&
+		b.Max[0] >= other.Max[0] &&
+		b.Min[1] <= other.Min[1] &&
+		b.Max[1] >= other.Max[1] &&
+		b.Min[2] <= other.Min[2] &&
+		b.Max[2] >= other.Max[2]
+}
+
+// Merge returns a box that contains both smaller boxes.
+func (b Box3f) Merge(other Box3f) Box3f {
+	min := Vec3f{
+		math32.Min(b.Min[0], other.Min[0]),
+		math32.Min(b.Min[1], other.Min[1]),
+		math32.Min(b.Min[2], other.Min[2]),
+	}
+	max := Vec3f{
+		math32.Max(b.Max[0], other.Max[0]),
+		math32.Max(b.Max[1], other.Max[1]),
+		math32.Max(b.Max[2], other.Max[2]),
+	}
+	return Box3f{min, max}
+}
+
+// ExtendPoint returns a box that contains both, the original box and the given point.
+func (b Box3f) ExtendPoint(point Vec3f) Box3f {
+	for i, val := range point {
+		if val < b.Min[i] {
+			b.Min[i] = val
+		}
+		if val > b.Max[i] {
+			b.Max[i] = val
+		}
+	}
+	return b
+}
+
+// ClosestPoint returns the point within the box that is closest to the given point.
+// If the point is contained within the box, it is returned unchanged.
+func (b Box3f) ClosestPoint(point Vec3f) Vec3f {
+	for i, val := range point {
+		point[i] = Clampf(val, b.Min[i], b.Max[i])
+	}
+	return point
+}
+
+// SquarePointDistance returns the squared distance between the box and a point.
+// If the point is contained within the box, 0 is returned.
+// Otherwise, the squared distance between the point and the nearest face, edge or corner is returned.
+func (b Box3f) SquarePointDistance(pos Vec3f) float32 {
+	// Source: "Nearest Neighbor Queries" by N. Roussopoulos, S. Kelley and F. Vincent, ACM SIGMOD, pages 71-79, 1995.
+	sum := float32(0.0)
+	for dim, val := range pos {
+		if val < b.Min[dim] {
+			d := val - b.Min[dim]
+			sum += d * d
+		} else if val > b.Max[dim] {
+			d := val - b.Max[dim]
+			sum += d * d
+		}
+	}
+	return sum
+}
+
+// PointDistance returns the distance between the box and a point.
+// If the point is contained within the box, 0 is returned.
+// Otherwise, the distance between the point and the nearest face, edge or corner is returned.
+func (b Box3f) PointDistance(pos Vec3f) float32 {
+	return math32.Sqrt(b.SquarePointDistance(pos))
+}
+
+// IntersectRay calculates the first intersection of a ray with the box.
+// See Ray3f.IntersectBox for details.
+func (b Box3f) IntersectRay(ray Ray3f) (RayHit, bool) {
+	return ray.IntersectBox(b.Min, b.Max)
+}
+
+// Transform applies an affine transformation matrix to the box.
+// Returns the smallest axis-aligned box that contains the transformed box.
+func (b Box3f) Transform(m Mat4f) Box3f {
+	// Source: "Transforming Axis-Aligned Bounding Boxes" by James Arvo, Graphics Gems, 1990.
+	res := Box3f{m.Translation(), m.Translation()}
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			cell := m[col*4+row]
+			e := cell * b.Min[col]
+			f := cell * b.Max[col]
+			if e < f {
+				res.Min[row] += e
+				res.Max[row] += f
+			} else {
+				res.Min[row] += f
+				res.Max[row] += e
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,223 @@
+package vmath
+
+import (
+	"math"
+	"testing"
+
+	"github.com/maja42/vmath/math32"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBox3fFromCorners(t *testing.T) {
+	expected := Box3f{
+		Min: Vec3f{-3, 4, -1},
+		Max: Vec3f{10, 7, 2},
+	}
+
+	assert.Equal(t, expected, Box3fFromCorners(Vec3f{-3, 4, -1}, Vec3f{10, 7, 2}))
+	assert.Equal(t, expected, Box3fFromCorners(Vec3f{10, 7, 2}, Vec3f{-3, 4, -1}))
+	assert.Equal(t, expected, Box3fFromCorners(Vec3f{10, 4, -1}, Vec3f{-3, 7, 2}))
+	assert.Equal(t, expected, Box3fFromCorners(Vec3f{-3, 7, 2}, Vec3f{10, 4, -1}))
+}
+
+func TestBox3fFromPosSize(t *testing.T) {
+	expected := Box3f{
+		Min: Vec3f{-3, 4, -1},
+		Max: Vec3f{10, 7, 2},
+	}
+
+	assert.Equal(t, expected, Box3fFromPosSize(Vec3f{-3, 4, -1}, Vec3f{13, 3, 3}))
+	assert.Equal(t, expected, Box3fFromPosSize(Vec3f{10, 7, 2}, Vec3f{-13, -3, -3}))
+	assert.Equal(t, expected, Box3fFromPosSize(Vec3f{10, 4, 2}, Vec3f{-13, 3, -3}))
+}
+
+func TestBox3fFromPoints(t *testing.T) {
+	assert.Equal(t, Box3f{}, Box3fFromPoints(nil))
+
+	b := Box3fFromPoints([]Vec3f{
+		{1, 2, 3},
+		{-1, 5, 0},
+		{4, -2, 1},
+	})
+	assert.Equal(t, Box3f{Vec3f{-1, -2, 0}, Vec3f{4, 5, 3}}, b)
+}
+
+func TestBox3f_Normalize(t *testing.T) {
+	expected := Box3f{
+		Min: Vec3f{-3, 4, -1},
+		Max: Vec3f{10, 7, 2},
+	}
+	assert.Equal(t, expected, Box3f{
+		Min: Vec3f{10, 7, 2},
+		Max: Vec3f{-3, 4, -1},
+	}.Normalize())
+	assert.Equal(t, expected, Box3f{
+		Min: Vec3f{-3, 7, -1},
+		Max: Vec3f{10, 4, 2},
+	}.Normalize())
+}
+
+func TestBox3f_String(t *testing.T) {
+	str := Box3f{
+		Min: Vec3f{-3, 4, -1},
+		Max: Vec3f{10, 7, 2},
+	}.String()
+	assert.Equal(t, `Box3f([-3.000000 x 4.000000 x -1.000000]-[10.000000 x 7.000000 x 2.000000])`, str)
+}
+
+func TestBox3f_Dimensions(t *testing.T) {
+	b := Box3f{
+		Min: Vec3f{-3, 4, -1},
+		Max: Vec3f{10, 7, 2},
+	}
+	assert.Equal(t, Vec3f{13, 3, 3}, b.Size())
+	assert.Equal(t, Vec3f{6.5, 1.5, 1.5}, b.HalfExtents())
+	assert.Equal(t, Vec3f{3.5, 5.5, 0.5}, b.Center())
+	assert.Equal(t, float32(13*3*3), b.Volume())
+	assert.Equal(t, float32(2*(13*3+3*3+3*13)), b.SurfaceArea())
+}
+
+func TestBox3f_Corners(t *testing.T) {
+	b := Box3f{
+		Min: Vec3f{0, 0, 0},
+		Max: Vec3f{1, 2, 3},
+	}
+	corners := b.Corners()
+	assert.Equal(t, b.Min, corners[0])
+	assert.Equal(t, b.Max, corners[6])
+	for _, c := range corners {
+		assert.True(t, b.ContainsPoint(c))
+	}
+	assert.Equal(t, b, Box3fFromPoints(corners[:]))
+}
+
+func TestBox3f_Intersects(t *testing.T) {
+	b := Box3f{
+		Min: Vec3f{0, 0, 0},
+		Max: Vec3f{10, 10, 10},
+	}
+	assert.True(t, b.Intersects(b))
+	assert.True(t, b.Intersects(Box3f{Vec3f{5, 5, 5}, Vec3f{6, 6, 6}}))
+	assert.True(t, b.Intersects(Box3f{Vec3f{-5, -5, -5}, Vec3f{20, 20, 20}}))
+	assert.True(t, b.Intersects(Box3f{Vec3f{9, 9, 9}, Vec3f{20, 20, 20}}))
+	assert.True(t, b.Intersects(Box3f{Vec3f{10, 10, 10}, Vec3f{20, 20, 20}})) // touching
+
+	assert.False(t, b.Intersects(Box3f{Vec3f{11, 0, 0}, Vec3f{20, 10, 10}}))
+	assert.False(t, b.Intersects(Box3f{Vec3f{0, -5, 0}, Vec3f{10, -1, 10}}))
+	assert.False(t, b.Intersects(Box3f{Vec3f{0, 0, 10.5}, Vec3f{10, 10, 20}}))
+}
+
+func TestBox3f_ContainsPoint(t *testing.T) {
+	b := Box3f{
+		Min: Vec3f{0, 0, 0},
+		Max: Vec3f{10, 10, 10},
+	}
+	assert.True(t, b.ContainsPoint(Vec3f{5, 5, 5}))
+	assert.True(t, b.ContainsPoint(Vec3f{0, 0, 0}))
+	assert.True(t, b.ContainsPoint(Vec3f{10, 10, 10}))
+	assert.True(t, b.ContainsPoint(Vec3f{0, 5, 10}))
+
+	assert.False(t, b.ContainsPoint(Vec3f{-1, 5, 5}))
+	assert.False(t, b.ContainsPoint(Vec3f{5, 11, 5}))
+	assert.False(t, b.ContainsPoint(Vec3f{5, 5, -0.1}))
+}
+
+func TestBox3f_ContainsBox3f(t *testing.T) {
+	b := Box3f{
+		Min: Vec3f{0, 0, 0},
+		Max: Vec3f{10, 10, 10},
+	}
+	assert.True(t, b.ContainsBox3f(b))
+	assert.True(t, b.ContainsBox3f(Box3f{Vec3f{1, 2, 3}, Vec3f{4, 5, 6}}))
+	assert.False(t, b.ContainsBox3f(Box3f{Vec3f{1, 2, 3}, Vec3f{4, 5, 11}}))
+	assert.False(t, b.ContainsBox3f(Box3f{Vec3f{-1, -1, -1}, Vec3f{11, 11, 11}}))
+}
+
+func TestBox3f_Merge(t *testing.T) {
+	a := Box3f{Vec3f{0, 0, 0}, Vec3f{1, 1, 1}}
+	b := Box3f{Vec3f{-2, 0.5, 3}, Vec3f{0, 4, 5}}
+	expected := Box3f{Vec3f{-2, 0, 0}, Vec3f{1, 4, 5}}
+	assert.Equal(t, expected, a.Merge(b))
+	assert.Equal(t, expected, b.Merge(a))
+}
+
+func TestBox3f_ClosestPoint(t *testing.T) {
+	b := Box3f{
+		Min: Vec3f{0, 0, 0},
+		Max: Vec3f{10, 10, 10},
+	}
+	assert.Equal(t, Vec3f{5, 5, 5}, b.ClosestPoint(Vec3f{5, 5, 5}))
+	assert.Equal(t, Vec3f{0, 5, 10}, b.ClosestPoint(Vec3f{-3, 5, 12}))
+	assert.Equal(t, Vec3f{10, 0, 10}, b.ClosestPoint(Vec3f{13, -1, 12}))
+}
+
+func TestBox3f_SquarePointDistance(t *testing.T) {
+	b := Box3f{
+		Min: Vec3f{0, 0, 0},
+		Max: Vec3f{10, 10, 10},
+	}
+	// within the box
+	assert.Equal(t, float32(0), b.SquarePointDistance(Vec3f{0, 0, 0}))
+	assert.Equal(t, float32(0), b.SquarePointDistance(Vec3f{5, 5, 5}))
+	assert.Equal(t, float32(0), b.SquarePointDistance(Vec3f{10, 10, 10}))
+
+	// in front of a face
+	assert.Equal(t, float32(25), b.SquarePointDistance(Vec3f{-5, 3, 3}))
+	assert.Equal(t, float32(16), b.SquarePointDistance(Vec3f{3, 14, 3}))
+	assert.Equal(t, float32(4), b.SquarePointDistance(Vec3f{3, 3, 12}))
+	// next to an edge
+	assert.Equal(t, float32(50), b.SquarePointDistance(Vec3f{-5, -5, 5}))
+	assert.Equal(t, float32(13), b.SquarePointDistance(Vec3f{5, 12, -3}))
+	// next to a corner
+	assert.Equal(t, float32(1+4+9), b.SquarePointDistance(Vec3f{11, -2, 13}))
+}
+
+func TestBox3f_PointDistance(t *testing.T) {
+	b := Box3f{
+		Min: Vec3f{0, 0, 0},
+		Max: Vec3f{10, 10, 10},
+	}
+	AssertFloat(t, 0, b.PointDistance(Vec3f{5, 5, 5}))
+	AssertFloat(t, 3, b.PointDistance(Vec3f{-3, 5, 5}))
+	AssertFloat(t, math32.Sqrt(14), b.PointDistance(Vec3f{11, -2, 13}))
+}
+
+func TestBox3f_Transform(t *testing.T) {
+	b := Box3f{
+		Min: Vec3f{-1, -2, -3},
+		Max: Vec3f{1, 2, 3},
+	}
+
+	// translation + scaling
+	m := Mat4fFromTranslation(Vec3f{10, 0, 0}).Mul(Mat4fFromScaling(Vec3f{2, -1, 1}))
+	tb := b.Transform(m)
+	AssertVec3f(t, Vec3f{8, -2, -3}, tb.Min)
+	AssertVec3f(t, Vec3f{12, 2, 3}, tb.Max)
+
+	// 90° rotation around Z swaps the X and Y extents
+	tb = b.Transform(Mat4fFromZRotation(math.Pi / 2))
+	AssertVec3f(t, Vec3f{-2, -1, -3}, tb.Min)
+	AssertVec3f(t, Vec3f{2, 1, 3}, tb.Max)
+
+	// the result is the tight box around all transformed corners
+	m = Mat4fFromRotationTranslation(QuatFromAxisAngle(Vec3f{1, 2, 3}, 0.7), Vec3f{1, -4, 2})
+	var corners []Vec3f
+	for _, c := range b.Corners() {
+		corners = append(corners, m.MulVec(c.Vec4f(1)).XYZ())
+	}
+	expected := Box3fFromPoints(corners)
+	tb = b.Transform(m)
+	AssertVec3f(t, expected.Min, tb.Min)
+	AssertVec3f(t, expected.Max, tb.Max)
+}
+
+func TestBox3f_IntersectRay(t *testing.T) {
+	b := Box3f{
+		Min: Vec3f{-1, -1, -1},
+		Max: Vec3f{1, 1, 1},
+	}
+	hit, ok := b.IntersectRay(Ray3f{Vec3f{0, 0, 5}, Vec3f{0, 0, -1}})
+	assert.True(t, ok)
+	AssertFloat(t, 4, hit.Distance)
+	AssertVec3f(t, Vec3f{0, 0, 1}, hit.Normal)
+}
@@ -0,0 +1,209 @@
+package vmath
+
+import (
+	"fmt"
+
+	"github.com/maja42/vmath/math32"
+	"github.com/maja42/vmath/mathi"
+)
+
+// Box3i represents a 3D, axis-aligned box.
+type Box3i struct {
+	Min Vec3i
+	Max Vec3i
+}
+
+// Box3iFromCorners creates a new box given two opposite corners.
+// If necessary, coordinates are swapped to create a normalized box.
+func Box3iFromCorners(c1, c2 Vec3i) Box3i {
+	for i := range c1 {
+		if c1[i] > c2[i] {
+			c1[i], c2[i] = c2[i], c1[i]
+		}
+	}
+	return Box3i{c1, c2}
+}
+
+// Box3iFromPosSize creates a new box with the given size and position.
+// Negative dimensions are inverted to create a normalized box.
+func Box3iFromPosSize(pos, size Vec3i) Box3i {
+	for i := range size {
+		if size[i] < 0 {
+			size[i] = -size[i]
+			pos[i] -= size[i]
+		}
+	}
+	return Box3i{
+		pos,
+		pos.Add(size),
+	}
+}
+
+// Normalize ensures that the Min position is smaller than the Max position in every dimension.
+func (b Box3i) Normalize() Box3i {
+	for i := range b.Min {
+		if b.Min[i] > b.Max[i] {
+			b.Min[i], b.Max[i] = b.Max[i], b.Min[i]
+		}
+	}
+	return b
+}
+
+func (b Box3i) String() string {
+	return fmt.Sprintf("Box3i([%d x %d x %d]-[%d x %d x %d])",
+		b.Min[0], b.Min[1], b.Min[2],
+		b.Max[0], b.Max[1], b.Max[2])
+}
+
+// Box3f returns a float representation of the box.
+func (b Box3i) Box3f() Box3f {
+	return Box3f{
+		b.Min.Vec3f(),
+		b.Max.Vec3f(),
+	}
+}
+
+// Size returns the box's dimensions.
+func (b Box3i) Size() Vec3i {
+	return b.Max.Sub(b.Min)
+}
+
+// Volume returns the box's volume.
+// This is also the number of cells within the box.
+func (b Box3i) Volume() int {
+	size := b.Max.Sub(b.Min)
+	return size[0] * size[1] * size[2]
+}
+
+// Add moves the box with the given vector by adding it to the min- and max- components.
+func (b Box3i) Add(v Vec3i) Box3i {
+	return Box3i{
+		Min: b.Min.Add(v),
+		Max: b.Max.Add(v),
+	}
+}
+
+// Sub moves the box with the given vector by subtracting it to the min- and max- components.
+func (b Box3i) Sub(v Vec3i) Box3i {
+	return Box3i{
+		Min: b.Min.Sub(v),
+		Max: b.Max.Sub(v),
+	}
+}
+
+// Overlaps checks if this box overlaps another box.
+// Touching boxes are not considered to overlap.
+func (b Box3i) Overlaps(other Box3i) bool {
+	return b.Min[0] < other.Max[0] &&
+		b.Max[0] > other.Min[0] &&
+		b.Min[1] < other.Max[1] &&
+		b.Max[1] > other.Min[1] &&
+		b.Min[2] < other.Max[2] &&
+		b.Max[2] > other.Min[2]
+}
+
+// OverlapsOrTouches checks if this box overlaps or touches another box.
+func (b Box3i) OverlapsOrTouches(other Box3i) bool {
+	return b.Min[0] <= other.Max[0] &&
+		b.Max[0] >= other.Min[0] &&
+		b.Min[1] <= other.Max[1] &&
+		b.Max[1] >= other.Min[1] &&
+		b.Min[2] <= other.Max[2] &&
+		b.Max[2] >= other.Min[2]
+}
+
+// ContainsPoint checks if a given point resides within the box.
+// If the point is on a face, it is also considered to be contained within the box.
+func (b Box3i) ContainsPoint(point Vec3i) bool {
+	return point[0] >= b.Min[0] && point[0] <= b.Max[0] &&
+		point[1] >= b.Min[1] && point[1] <= b.Max[1] &&
+		point[2] >= b.Min[2] && point[2] <= b.Max[2]
+}
+
+// ContainsCell checks if the cell with the given position resides within the box.
+// In contrast to ContainsPoint, cells on the max-faces are not part of the box.
+func (b Box3i) ContainsCell(cell Vec3i) bool {
+	return cell[0] >= b.Min[0] && cell[0] < b.Max[0] &&
+		cell[1] >= b.Min[1] && cell[1] < b.Max[1] &&
+		cell[2] >= b.Min[2] && cell[2] < b.Max[2]
+}
+
+// ContainsBox3i checks if this box completely contains another box.
+func (b Box3i) ContainsBox3i(other Box3i) bool {
+	return b.Min[0] <= other.Min[0] &&
+		b.Max[0] >= other.Max[0] &&
+		b.Min[1] <= other.Min[1] &&
+		b.Max[1] >= other.Max[1] &&
+		b.Min[2] <= other.Min[2] &&
+		b.Max[2] >= other.Max[2]
+}
+
+// Merge returns a box that contains both smaller boxes.
+func (b Box3i) Merge(other Box3i) Box3i {
+	min := Vec3i{
+		mathi.Min(b.Min[0], other.Min[0]),
+		mathi.Min(b.Min[1], other.Min[1]),
+		mathi.Min(b.Min[2], other.Min[2]),
+	}
+	max := Vec3i{
+		mathi.Max(b.Max[0], other.Max[0]),
+		mathi.Max(b.Max[1], other.Max[1]),
+		mathi.Max(b.Max[2], other.Max[2]),
+	}
+	return Box3i{min, max}
+}
+
+// SquarePointDistance returns the squared distance between the box and a point.
+// If the point is contained within the box, 0 is returned.
+// Otherwise, the squared distance between the point and the nearest face, edge or corner is returned.
+func (b Box3i) SquarePointDistance(pos Vec3i) int {
+	// Source: "Nearest Neighbor Queries" by N. Roussopoulos, S. Kelley and F. Vincent, ACM SIGMOD, pages 71-79, 1995.
+	sum := 0
+	for dim, val := range pos {
+		if val < b.Min[dim] {
+			d := val - b.Min[dim]
+			sum += d * d
+		} else if val > b.Max[dim] {
+			d := val - b.Max[dim]
+			sum += d * d
+		}
+	}
+	return sum
+}
+
+// PointDistance returns the distance between the box and a point.
+// If the point is contained within the box, 0 is returned.
+// Otherwise, the distance between the point and the nearest face, edge or corner is returned.
+func (b Box3i) PointDistance(pos Vec3i) float32 {
+	return math32.Sqrt(float32(b.SquarePointDistance(pos)))
+}
+
+// ForEachCell calls fn for every cell within the box, in X-Y-Z order.
+// The box's max-faces are exclusive, so the number of visited cells equals the box's volume.
+// Iteration stops early if fn returns false.
+func (b Box3i) ForEachCell(fn func(cell Vec3i) bool) {
+	var cell Vec3i
+	for cell[2] = b.Min[2]; cell[2] < b.Max[2]; cell[2]++ {
+		for cell[1] = b.Min[1]; cell[1] < b.Max[1]; cell[1]++ {
+			for cell[0] = b.Min[0]; cell[0] < b.Max[0]; cell[0]++ {
+				if !fn(cell) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Cells returns all cells within the box, in the same order as ForEachCell.
+func (b Box3i) Cells() []Vec3i {
+	volume := b.Volume()
+	if volume <= 0 {
+		return nil
+	}
+	cells := make([]Vec3i, 0, volume)
+	b.ForEachCell(func(cell Vec3i) bool {
+		cells = append(cells, cell)
+		return true
+	})
+	return cells
+}
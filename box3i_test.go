@@ -0,0 +1,183 @@
+package vmath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBox3iFromCorners(t *testing.T) {
+	expected := Box3i{
+		Min: Vec3i{-3, 4, -1},
+		Max: Vec3i{10, 7, 2},
+	}
+
+	assert.Equal(t, expected, Box3iFromCorners(Vec3i{-3, 4, -1}, Vec3i{10, 7, 2}))
+	assert.Equal(t, expected, Box3iFromCorners(Vec3i{10, 7, 2}, Vec3i{-3, 4, -1}))
+	assert.Equal(t, expected, Box3iFromCorners(Vec3i{10, 4, -1}, Vec3i{-3, 7, 2}))
+}
+
+func TestBox3iFromPosSize(t *testing.T) {
+	expected := Box3i{
+		Min: Vec3i{-3, 4, -1},
+		Max: Vec3i{10, 7, 2},
+	}
+
+	assert.Equal(t, expected, Box3iFromPosSize(Vec3i{-3, 4, -1}, Vec3i{13, 3, 3}))
+	assert.Equal(t, expected, Box3iFromPosSize(Vec3i{10, 7, 2}, Vec3i{-13, -3, -3}))
+	assert.Equal(t, expected, Box3iFromPosSize(Vec3i{10, 4, 2}, Vec3i{-13, 3, -3}))
+}
+
+func TestBox3i_Normalize(t *testing.T) {
+	expected := Box3i{
+		Min: Vec3i{-3, 4, -1},
+		Max: Vec3i{10, 7, 2},
+	}
+	assert.Equal(t, expected, Box3i{
+		Min: Vec3i{10, 7, 2},
+		Max: Vec3i{-3, 4, -1},
+	}.Normalize())
+	assert.Equal(t, expected, Box3i{
+		Min: Vec3i{-3, 7, -1},
+		Max: Vec3i{10, 4, 2},
+	}.Normalize())
+}
+
+func TestBox3i_String(t *testing.T) {
+	str := Box3i{
+		Min: Vec3i{-3, 4, -1},
+		Max: Vec3i{10, 7, 2},
+	}.String()
+	assert.Equal(t, `Box3i([-3 x 4 x -1]-[10 x 7 x 2])`, str)
+}
+
+func TestBox3i_Box3f(t *testing.T) {
+	bi := Box3i{
+		Min: Vec3i{-3, 4, -1},
+		Max: Vec3i{10, -7, 2},
+	}
+	bf := Box3f{
+		Min: Vec3f{-3, 4, -1},
+		Max: Vec3f{10, -7, 2},
+	}
+	assert.Equal(t, bf, bi.Box3f())
+}
+
+func TestBox3f_Box3i(t *testing.T) {
+	bf := Box3f{
+		Min: Vec3f{-3.7, 4.5, 0.2},
+		Max: Vec3f{10.2, -7.5, 1.9},
+	}
+	assert.Equal(t, Box3i{Vec3i{-3, 4, 0}, Vec3i{10, -7, 1}}, bf.Box3i())
+	assert.Equal(t, Box3i{Vec3i{-4, 5, 0}, Vec3i{10, -8, 2}}, bf.Round())
+}
+
+func TestBox3i_Dimensions(t *testing.T) {
+	b := Box3i{
+		Min: Vec3i{-3, 4, -1},
+		Max: Vec3i{10, 7, 2},
+	}
+	assert.Equal(t, Vec3i{13, 3, 3}, b.Size())
+	assert.Equal(t, 13*3*3, b.Volume())
+}
+
+func TestBox3i_Overlaps(t *testing.T) {
+	b := Box3i{
+		Min: Vec3i{0, 0, 0},
+		Max: Vec3i{10, 10, 10},
+	}
+	assert.True(t, b.Overlaps(b))
+	assert.True(t, b.Overlaps(Box3i{Vec3i{5, 5, 5}, Vec3i{6, 6, 6}}))
+	assert.True(t, b.Overlaps(Box3i{Vec3i{9, 9, 9}, Vec3i{20, 20, 20}}))
+
+	// touching
+	assert.False(t, b.Overlaps(Box3i{Vec3i{10, 0, 0}, Vec3i{20, 10, 10}}))
+	assert.False(t, b.Overlaps(Box3i{Vec3i{0, 0, -5}, Vec3i{10, 10, 0}}))
+	assert.True(t, b.OverlapsOrTouches(Box3i{Vec3i{10, 0, 0}, Vec3i{20, 10, 10}}))
+	assert.True(t, b.OverlapsOrTouches(Box3i{Vec3i{0, 0, -5}, Vec3i{10, 10, 0}}))
+
+	// disjoint
+	assert.False(t, b.Overlaps(Box3i{Vec3i{11, 0, 0}, Vec3i{20, 10, 10}}))
+	assert.False(t, b.OverlapsOrTouches(Box3i{Vec3i{11, 0, 0}, Vec3i{20, 10, 10}}))
+	assert.False(t, b.OverlapsOrTouches(Box3i{Vec3i{0, 0, 11}, Vec3i{10, 10, 20}}))
+}
+
+func TestBox3i_ContainsPoint(t *testing.T) {
+	b := Box3i{
+		Min: Vec3i{0, 0, 0},
+		Max: Vec3i{10, 10, 10},
+	}
+	assert.True(t, b.ContainsPoint(Vec3i{5, 5, 5}))
+	assert.True(t, b.ContainsPoint(Vec3i{0, 0, 0}))
+	assert.True(t, b.ContainsPoint(Vec3i{10, 10, 10}))
+	assert.False(t, b.ContainsPoint(Vec3i{-1, 5, 5}))
+	assert.False(t, b.ContainsPoint(Vec3i{5, 5, 11}))
+
+	assert.True(t, b.ContainsCell(Vec3i{0, 0, 0}))
+	assert.True(t, b.ContainsCell(Vec3i{9, 9, 9}))
+	assert.False(t, b.ContainsCell(Vec3i{10, 5, 5}))
+	assert.False(t, b.ContainsCell(Vec3i{5, 5, -1}))
+}
+
+func TestBox3i_ContainsBox3i(t *testing.T) {
+	b := Box3i{
+		Min: Vec3i{0, 0, 0},
+		Max: Vec3i{10, 10, 10},
+	}
+	assert.True(t, b.ContainsBox3i(b))
+	assert.True(t, b.ContainsBox3i(Box3i{Vec3i{1, 2, 3}, Vec3i{4, 5, 6}}))
+	assert.False(t, b.ContainsBox3i(Box3i{Vec3i{1, 2, 3}, Vec3i{4, 5, 11}}))
+	assert.False(t, b.ContainsBox3i(Box3i{Vec3i{-1, 2, 3}, Vec3i{4, 5, 6}}))
+}
+
+func TestBox3i_Merge(t *testing.T) {
+	a := Box3i{Vec3i{0, 0, 0}, Vec3i{1, 1, 1}}
+	b := Box3i{Vec3i{-2, 1, 3}, Vec3i{0, 4, 5}}
+	expected := Box3i{Vec3i{-2, 0, 0}, Vec3i{1, 4, 5}}
+	assert.Equal(t, expected, a.Merge(b))
+	assert.Equal(t, expected, b.Merge(a))
+}
+
+func TestBox3i_SquarePointDistance(t *testing.T) {
+	b := Box3i{
+		Min: Vec3i{0, 0, 0},
+		Max: Vec3i{10, 10, 10},
+	}
+	assert.Equal(t, 0, b.SquarePointDistance(Vec3i{5, 5, 5}))
+	assert.Equal(t, 0, b.SquarePointDistance(Vec3i{10, 0, 10}))
+	assert.Equal(t, 25, b.SquarePointDistance(Vec3i{-5, 3, 3}))
+	assert.Equal(t, 13, b.SquarePointDistance(Vec3i{5, 12, -3}))
+	assert.Equal(t, 1+4+9, b.SquarePointDistance(Vec3i{11, -2, 13}))
+
+	AssertFloat(t, 5, b.PointDistance(Vec3i{-5, 3, 3}))
+}
+
+func TestBox3i_ForEachCell(t *testing.T) {
+	b := Box3i{
+		Min: Vec3i{-1, 0, 2},
+		Max: Vec3i{1, 2, 4},
+	}
+
+	var cells []Vec3i
+	b.ForEachCell(func(cell Vec3i) bool {
+		cells = append(cells, cell)
+		return true
+	})
+	assert.Equal(t, []Vec3i{
+		{-1, 0, 2}, {0, 0, 2}, {-1, 1, 2}, {0, 1, 2},
+		{-1, 0, 3}, {0, 0, 3}, {-1, 1, 3}, {0, 1, 3},
+	}, cells)
+	assert.Equal(t, cells, b.Cells())
+	assert.Len(t, cells, b.Volume())
+
+	// early exit
+	count := 0
+	b.ForEachCell(func(cell Vec3i) bool {
+		count++
+		return count < 3
+	})
+	assert.Equal(t, 3, count)
+
+	// empty
+	assert.Nil(t, Box3i{Vec3i{0, 0, 0}, Vec3i{5, 0, 5}}.Cells())
+}
@@ -0,0 +1,662 @@
+package vmath
+
+import (
+	"github.com/maja42/vmath/math32"
+	"github.com/maja42/vmath/mathi"
+)
+
+// BVH is a bounding volume hierarchy, which accelerates spatial queries over items with axis-aligned bounding boxes.
+// Items are identified by integer IDs. Each leaf node contains a single item.
+//
+// The tree can be built at once with BuildBVH, which results in a high tree quality,
+// or incrementally by inserting and removing items, which is suited for dynamic scenes.
+// Queries can run concurrently, but not while the tree is modified.
+type BVH struct {
+	nodes  []bvhNode
+	root   int
+	free   int         // first node of the free list
+	leaves map[int]int // leaf node of each item
+}
+
+// bvhNode is a node within the BVH. Unused nodes form a linked list via the parent index.
+type bvhNode struct {
+	box         Box3f
+	parent      int
+	left, right int // children, or bvhNull for leaves
+	height      int // 0 for leaves
+	id          int // item ID of leaves
+}
+
+const bvhNull = -1
+
+// bvhBins is the number of bins per axis that are evaluated when building the tree.
+const bvhBins = 12
+
+// NewBVH creates an empty bounding volume hierarchy.
+func NewBVH() *BVH {
+	return &BVH{
+		root:   bvhNull,
+		free:   bvhNull,
+		leaves: make(map[int]int),
+	}
+}
+
+// BuildBVH creates a bounding volume hierarchy over the given boxes.
+// The item IDs are the boxes' indices.
+// The tree is built top-down with the binned surface area heuristic (SAH), which results in a good tree quality.
+func BuildBVH(boxes []Box3f) *BVH {
+	// Source: "On fast Construction of SAH-based Bounding Volume Hierarchies" by Ingo Wald, 2007.
+	b := NewBVH()
+	if len(boxes) == 0 {
+		return b
+	}
+	b.nodes = make([]bvhNode, 0, 2*len(boxes)-1)
+	items := make([]bvhBuildItem, len(boxes))
+	for i, box := range boxes {
+		items[i] = bvhBuildItem{box, box.Center(), i}
+	}
+	b.root = b.build(items, bvhNull)
+	return b
+}
+
+// bvhBuildItem is an item during the construction of the tree.
+type bvhBuildItem struct {
+	box      Box3f
+	centroid Vec3f
+	id       int
+}
+
+// build creates the subtree containing the given items and returns its root node.
+func (b *BVH) build(items []bvhBuildItem, parent int) int {
+	node := b.allocate()
+	n := &b.nodes[node]
+	n.parent = parent
+	if len(items) == 1 {
+		n.box = items[0].box
+		n.id = items[0].id
+		b.leaves[n.id] = node
+		return node
+	}
+
+	bounds := items[0].box
+	centroids := Box3f{items[0].centroid, items[0].centroid}
+	for _, item := range items[1:] {
+		bounds = bounds.Merge(item.box)
+		centroids = centroids.ExtendPoint(item.centroid)
+	}
+
+	mid := bvhSplit(items, centroids)
+	left := b.build(items[:mid], node)
+	right := b.build(items[mid:], node)
+
+	n = &b.nodes[node] // the node slice might have been reallocated
+	n.box = bounds
+	n.left, n.right = left, right
+	n.height = 1 + mathi.Max(b.nodes[left].height, b.nodes[right].height)
+	return node
+}
+
+// bvhSplit partitions the items along the split with the lowest surface area heuristic,
+// and returns the index of the first item on the right side.
+func bvhSplit(items []bvhBuildItem, centroids Box3f) int {
+	bestAxis, bestSplit := -1, 0
+	bestCost := math32.Infinity
+	size := centroids.Size()
+	for axis := 0; axis < 3; axis++ {
+		if size[axis] <= 0 {
+			continue
+		}
+		var bins [bvhBins]bvhBin
+		scale := bvhBins / size[axis]
+		for _, item := range items {
+			i := bvhBinIndex(item.centroid[axis], centroids.Min[axis], scale)
+			bins[i] = bins[i].merge(item.box, 1)
+		}
+
+		// sweep from the right to calculate the costs of all right sides, then from the left
+		var rightCost [bvhBins]float32
+		var acc bvhBin
+		for i := bvhBins - 1; i > 0; i-- {
+			acc = acc.merge(bins[i].box, bins[i].count)
+			rightCost[i] = acc.box.SurfaceArea() * float32(acc.count)
+		}
+		acc = bvhBin{}
+		for i := 0; i < bvhBins-1; i++ {
+			acc = acc.merge(bins[i].box, bins[i].count)
+			if acc.count == 0 || acc.count == len(items) {
+				continue
+			}
+			if cost := acc.box.SurfaceArea()*float32(acc.count) + rightCost[i+1]; cost < bestCost {
+				bestAxis, bestSplit, bestCost = axis, i+1, cost
+			}
+		}
+	}
+	if bestAxis < 0 { // all centroids are equal
+		return len(items) / 2
+	}
+
+	// partition in-place
+	scale := bvhBins / size[bestAxis]
+	mid := 0
+	for i, item := range items {
+		if bvhBinIndex(item.centroid[bestAxis], centroids.Min[bestAxis], scale) < bestSplit {
+			items[i], items[mid] = items[mid], items[i]
+			mid++
+		}
+	}
+	return mid
+}
+
+// bvhBinIndex returns the bin index of a centroid coordinate.
+func bvhBinIndex(val, min, scale float32) int {
+	i := int((val - min) * scale)
+	if i >= bvhBins {
+		i = bvhBins - 1
+	}
+	return i
+}
+
+// bvhBin accumulates the items whose centroids fall into the same interval.
+type bvhBin struct {
+	box   Box3f
+	count int
+}
+
+// merge adds items with the given bounding box to the bin.
+func (b bvhBin) merge(box Box3f, count int) bvhBin {
+	if count == 0 {
+		return b
+	}
+	if b.count == 0 {
+		b.box = box
+	} else {
+		b.box = b.box.Merge(box)
+	}
+	b.count += count
+	return b
+}
+
+// Len returns the number of items within the tree.
+func (b *BVH) Len() int {
+	return len(b.leaves)
+}
+
+// Bounds returns the box that contains all items.
+// Returns an empty box if the tree is empty.
+func (b *BVH) Bounds() Box3f {
+	if b.root == bvhNull {
+		return Box3f{}
+	}
+	return b.nodes[b.root].box
+}
+
+// Height returns the number of levels below the root node, which is 0 if the tree contains a single item.
+// Returns -1 if the tree is empty.
+func (b *BVH) Height() int {
+	if b.root == bvhNull {
+		return -1
+	}
+	return b.nodes[b.root].height
+}
+
+// Box returns the box of the item with the given ID.
+// Returns false if the item is not within the tree.
+func (b *BVH) Box(id int) (Box3f, bool) {
+	leaf, ok := b.leaves[id]
+	if !ok {
+		return Box3f{}, false
+	}
+	return b.nodes[leaf].box, true
+}
+
+// Insert adds an item to the tree. If an item with the same ID already exists, it is updated.
+func (b *BVH) Insert(id int, box Box3f) {
+	if b.Update(id, box) {
+		return
+	}
+	leaf := b.allocate()
+	b.nodes[leaf] = bvhNode{
+		box:    box,
+		parent: bvhNull,
+		left:   bvhNull,
+		right:  bvhNull,
+		id:     id,
+	}
+	b.leaves[id] = leaf
+	b.insertLeaf(leaf)
+}
+
+// Remove removes the item with the given ID.
+// Returns false if the item is not within the tree.
+func (b *BVH) Remove(id int) bool {
+	leaf, ok := b.leaves[id]
+	if !ok {
+		return false
+	}
+	delete(b.leaves, id)
+	b.removeLeaf(leaf)
+	b.release(leaf)
+	return true
+}
+
+// Update changes the box of an item, and restructures the tree accordingly.
+// Returns false if the item is not within the tree.
+func (b *BVH) Update(id int, box Box3f) bool {
+	leaf, ok := b.leaves[id]
+	if !ok {
+		return false
+	}
+	b.removeLeaf(leaf)
+	b.nodes[leaf].box = box
+	b.insertLeaf(leaf)
+	return true
+}
+
+// SetBox changes the box of an item without updating the tree.
+// This is cheaper than Update if many items move at once; Refit must be called afterwards.
+// The tree's structure is kept, so its quality degrades if items move far.
+// Returns false if the item is not within the tree.
+func (b *BVH) SetBox(id int, box Box3f) bool {
+	leaf, ok := b.leaves[id]
+	if ok {
+		b.nodes[leaf].box = box
+	}
+	return ok
+}
+
+// Refit recalculates the boxes of all inner nodes, after items changed with SetBox.
+func (b *BVH) Refit() {
+	if b.root != bvhNull {
+		b.refit(b.root)
+	}
+}
+
+func (b *BVH) refit(node int) Box3f {
+	n := &b.nodes[node]
+	if n.left != bvhNull {
+		n.box = b.refit(n.left).Merge(b.refit(n.right))
+	}
+	return n.box
+}
+
+// allocate returns an unused node.
+func (b *BVH) allocate() int {
+	if b.free == bvhNull {
+		b.nodes = append(b.nodes, bvhNode{parent: bvhNull, left: bvhNull, right: bvhNull})
+		return len(b.nodes) - 1
+	}
+	node := b.free
+	b.free = b.nodes[node].parent
+	b.nodes[node] = bvhNode{parent: bvhNull, left: bvhNull, right: bvhNull}
+	return node
+}
+
+// release adds the node to the free list.
+func (b *BVH) release(node int) {
+	b.nodes[node] = bvhNode{parent: b.free, left: bvhNull, right: bvhNull}
+	b.free = node
+}
+
+// insertLeaf inserts the leaf node into the tree.
+func (b *BVH) insertLeaf(leaf int) {
+	// Source: "Box2D" by Erin Catto, b2DynamicTree::InsertLeaf
+	if b.root == bvhNull {
+		b.root = leaf
+		b.nodes[leaf].parent = bvhNull
+		return
+	}
+
+	// find the best sibling, by descending into the child with the lower increase in surface area
+	box := b.nodes[leaf].box
+	index := b.root
+	for b.nodes[index].left != bvhNull {
+		n := &b.nodes[index]
+		area := n.box.SurfaceArea()
+		combinedArea := n.box.Merge(box).SurfaceArea()
+
+		// cost of creating a new parent for this node and the new leaf
+		cost := 2 * combinedArea
+		// minimum cost of pushing the leaf further down the tree
+		inheritanceCost := 2 * (combinedArea - area)
+
+		childCost := func(child int) float32 {
+			c := &b.nodes[child]
+			if c.left == bvhNull {
+				return c.box.Merge(box).SurfaceArea() + inheritanceCost
+			}
+			return c.box.Merge(box).SurfaceArea() - c.box.SurfaceArea() + inheritanceCost
+		}
+		cost1 := childCost(n.left)
+		cost2 := childCost(n.right)
+		if cost < cost1 && cost < cost2 {
+			break
+		}
+		if cost1 < cost2 {
+			index = n.left
+		} else {
+			index = n.right
+		}
+	}
+
+	sibling := index
+	oldParent := b.nodes[sibling].parent
+	newParent := b.allocate()
+	b.nodes[newParent] = bvhNode{
+		box:    box.Merge(b.nodes[sibling].box),
+		parent: oldParent,
+		left:   sibling,
+		right:  leaf,
+		height: b.nodes[sibling].height + 1,
+	}
+	b.nodes[sibling].parent = newParent
+	b.nodes[leaf].parent = newParent
+	b.replaceChild(oldParent, sibling, newParent)
+
+	b.fixUpwards(b.nodes[leaf].parent)
+}
+
+// removeLeaf removes the leaf node from the tree, without releasing it.
+func (b *BVH) removeLeaf(leaf int) {
+	// Source: "Box2D" by Erin Catto, b2DynamicTree::RemoveLeaf
+	if leaf == b.root {
+		b.root = bvhNull
+		return
+	}
+	parent := b.nodes[leaf].parent
+	grandParent := b.nodes[parent].parent
+	sibling := b.nodes[parent].left
+	if sibling == leaf {
+		sibling = b.nodes[parent].right
+	}
+
+	b.replaceChild(grandParent, parent, sibling)
+	b.nodes[sibling].parent = grandParent
+	b.release(parent)
+	b.nodes[leaf].parent = bvhNull
+	b.fixUpwards(grandParent)
+}
+
+// replaceChild replaces a child of the given parent node. If the parent is bvhNull, the root is replaced.
+func (b *BVH) replaceChild(parent, oldChild, newChild int) {
+	if parent == bvhNull {
+		b.root = newChild
+		return
+	}
+	p := &b.nodes[parent]
+	if p.left == oldChild {
+		p.left = newChild
+	} else {
+		p.right = newChild
+	}
+}
+
+// fixUpwards rebalances the tree and recalculates boxes and heights, from the given node up to the root.
+func (b *BVH) fixUpwards(node int) {
+	for node != bvhNull {
+		node = b.balance(node)
+		n := &b.nodes[node]
+		l, r := &b.nodes[n.left], &b.nodes[n.right]
+		n.height = 1 + mathi.Max(l.height, r.height)
+		n.box = l.box.Merge(r.box)
+		node = n.parent
+	}
+}
+
+// balance performs a tree rotation if the subtrees of node a are imbalanced.
+// Returns the new root of the subtree.
+func (b *BVH) balance(a int) int {
+	// Source: "Box2D" by Erin Catto, b2DynamicTree::Balance
+	na := &b.nodes[a]
+	if na.left == bvhNull || na.height < 2 {
+		return a
+	}
+	l, r := na.left, na.right
+	diff := b.nodes[r].height - b.nodes[l].height
+	if diff > 1 {
+		return b.rotate(a, r, false)
+	}
+	if diff < -1 {
+		return b.rotate(a, l, true)
+	}
+	return a
+}
+
+// rotate promotes the child c of node a, which becomes the subtree's new root.
+// The taller grandchild stays below c, the other one replaces c within a.
+func (b *BVH) rotate(a, c int, isLeft bool) int {
+	na, nc := &b.nodes[a], &b.nodes[c]
+	f, g := nc.left, nc.right
+	if b.nodes[f].height < b.nodes[g].height {
+		f, g = g, f // f is the taller grandchild
+	}
+
+	// c replaces a
+	nc.parent = na.parent
+	b.replaceChild(na.parent, a, c)
+	na.parent = c
+	nc.left, nc.right = a, f
+
+	// g replaces c within a
+	if isLeft {
+		na.left = g
+	} else {
+		na.right = g
+	}
+	b.nodes[g].parent = a
+
+	l, r := &b.nodes[na.left], &b.nodes[na.right]
+	na.box = l.box.Merge(r.box)
+	na.height = 1 + mathi.Max(l.height, r.height)
+	nf := &b.nodes[f]
+	nc.box = na.box.Merge(nf.box)
+	nc.height = 1 + mathi.Max(na.height, nf.height)
+	return c
+}
+
+// bvhStackSize is the initial capacity of traversal stacks, which avoids allocations for reasonably balanced trees.
+const bvhStackSize = 64
+
+// QueryBox appends the IDs of all items whose boxes intersect the given box to dst, and returns the result.
+func (b *BVH) QueryBox(box Box3f, dst []int) []int {
+	return b.query(dst, func(node Box3f) Containment {
+		if !node.Intersects(box) {
+			return Outside
+		}
+		if box.ContainsBox3f(node) {
+			return Inside
+		}
+		return Intersecting
+	})
+}
+
+// QuerySphere appends the IDs of all items whose boxes intersect the given sphere to dst, and returns the result.
+func (b *BVH) QuerySphere(sphere Sphere, dst []int) []int {
+	return b.query(dst, func(node Box3f) Containment {
+		if !sphere.IntersectsBox3f(node) {
+			return Outside
+		}
+		return Intersecting
+	})
+}
+
+// QueryFrustum appends the IDs of all items whose boxes are inside or intersect the given frustum to dst,
+// and returns the result. Like ViewFrustum.IntersectsBox, items close to the frustum might be included as well.
+func (b *BVH) QueryFrustum(frustum ViewFrustum, dst []int) []int {
+	return b.query(dst, frustum.IntersectsBox)
+}
+
+// query appends all items of nodes that are not outside according to the test function.
+// Nodes that are inside are added without testing their children.
+func (b *BVH) query(dst []int, test func(Box3f) Containment) []int {
+	if b.root == bvhNull {
+		return dst
+	}
+	var buf [bvhStackSize]int
+	stack := append(buf[:0], b.root)
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		n := &b.nodes[node]
+		switch test(n.box) {
+		case Outside:
+			continue
+		case Inside:
+			dst = b.appendLeaves(dst, node)
+			continue
+		}
+		if n.left == bvhNull {
+			dst = append(dst, n.id)
+		} else {
+			stack = append(stack, n.left, n.right)
+		}
+	}
+	return dst
+}
+
+// appendLeaves appends the IDs of all items within the subtree.
+func (b *BVH) appendLeaves(dst []int, node int) []int {
+	n := &b.nodes[node]
+	if n.left == bvhNull {
+		return append(dst, n.id)
+	}
+	dst = b.appendLeaves(dst, n.left)
+	return b.appendLeaves(dst, n.right)
+}
+
+// RayCast finds the item that is hit first by the ray, within the ray parameter range [0, maxDist].
+// The hit function tests the ray against an item and returns the ray parameter of the hit.
+// It is only called for items whose boxes are hit by the ray.
+// If it is nil, the boxes themselves are intersected with Box3f.IntersectRay.
+// Returns the item's ID and the hit's ray parameter, or false if no item is hit.
+func (b *BVH) RayCast(ray Ray3f, maxDist float32, hit func(id int) (float32, bool)) (int, float32, bool) {
+	return b.rayCast(ray, maxDist, hit, false)
+}
+
+// RayCastAny finds any item that is hit by the ray, within the ray parameter range [0, maxDist].
+// This is faster than RayCast and suited for occlusion tests.
+// See RayCast for details.
+func (b *BVH) RayCastAny(ray Ray3f, maxDist float32, hit func(id int) (float32, bool)) (int, float32, bool) {
+	return b.rayCast(ray, maxDist, hit, true)
+}
+
+func (b *BVH) rayCast(ray Ray3f, maxDist float32, hit func(id int) (float32, bool), any bool) (int, float32, bool) {
+	if b.root == bvhNull {
+		return 0, 0, false
+	}
+	var invDir Vec3f
+	for i, d := range ray.Dir {
+		invDir[i] = 1 / d
+	}
+	bestID, found := 0, false
+
+	var buf [bvhStackSize]int
+	stack := append(buf[:0], b.root)
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		n := &b.nodes[node]
+		if _, ok := rayBoxEntry(ray, invDir, n.box, maxDist); !ok {
+			continue
+		}
+		if n.left != bvhNull {
+			// visit the closer child first
+			l, r := n.left, n.right
+			if ray.Dir.Dot(b.nodes[r].box.Center().Sub(b.nodes[l].box.Center())) < 0 {
+				l, r = r, l
+			}
+			stack = append(stack, r, l)
+			continue
+		}
+
+		dist, ok := rayHitDistance(ray, n.box, n.id, hit)
+		if !ok || dist > maxDist {
+			continue
+		}
+		bestID, maxDist, found = n.id, dist, true
+		if any {
+			break
+		}
+	}
+	if !found {
+		return 0, 0, false
+	}
+	return bestID, maxDist, true
+}
+
+// rayHitDistance tests the ray against an item, using the hit function or the item's box.
+func rayHitDistance(ray Ray3f, box Box3f, id int, hit func(id int) (float32, bool)) (float32, bool) {
+	if hit != nil {
+		dist, ok := hit(id)
+		return dist, ok && dist >= 0
+	}
+	h, ok := box.IntersectRay(ray)
+	return h.Distance, ok
+}
+
+// rayBoxEntry returns the ray parameter where the ray enters the box, or 0 if the ray starts within it.
+// Returns false if the box is not hit within the range [0, maxDist].
+func rayBoxEntry(ray Ray3f, invDir Vec3f, box Box3f, maxDist float32) (float32, bool) {
+	// Source: "Real-Time Collision Detection" by Christer Ericson, chapter 5.3.3 (slab method)
+	tMin, tMax := float32(0), maxDist
+	for i := 0; i < 3; i++ {
+		if ray.Dir[i] == 0 {
+			if ray.Origin[i] < box.Min[i] || ray.Origin[i] > box.Max[i] {
+				return 0, false
+			}
+			continue
+		}
+		t1 := (box.Min[i] - ray.Origin[i]) * invDir[i]
+		t2 := (box.Max[i] - ray.Origin[i]) * invDir[i]
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		tMin = math32.Max(tMin, t1)
+		tMax = math32.Min(tMax, t2)
+		if tMin > tMax {
+			return 0, false
+		}
+	}
+	return tMin, true
+}
+
+// Nearest finds the item closest to the given point.
+// The sqDist function returns the squared distance between an item and the point.
+// It is only called for items whose boxes might be closer than the closest item found so far.
+// If it is nil, the squared distances of the boxes are used instead.
+// Returns the item's ID and squared distance, or false if the tree is empty.
+func (b *BVH) Nearest(point Vec3f, sqDist func(id int) float32) (int, float32, bool) {
+	if b.root == bvhNull {
+		return 0, 0, false
+	}
+	bestID, bestDist := 0, math32.Infinity
+
+	var buf [bvhStackSize]int
+	stack := append(buf[:0], b.root)
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		n := &b.nodes[node]
+		boxDist := n.box.SquarePointDistance(point)
+		if boxDist >= bestDist {
+			continue
+		}
+		if n.left != bvhNull {
+			// visit the closer child first
+			l, r := n.left, n.right
+			if b.nodes[l].box.SquarePointDistance(point) > b.nodes[r].box.SquarePointDistance(point) {
+				l, r = r, l
+			}
+			stack = append(stack, r, l)
+			continue
+		}
+
+		dist := boxDist
+		if sqDist != nil {
+			dist = sqDist(n.id)
+		}
+		if dist < bestDist {
+			bestID, bestDist = n.id, dist
+		}
+	}
+	return bestID, bestDist, true
+}
@@ -0,0 +1,270 @@
+package vmath
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/maja42/vmath/math32"
+	"github.com/maja42/vmath/mathi"
+	"github.com/stretchr/testify/assert"
+)
+
+func randomBoxes3f(count int, seed int64) []Box3f {
+	rnd := rand.New(rand.NewSource(seed))
+	boxes := make([]Box3f, count)
+	for i, p := range randomPoints3f(count, seed) {
+		size := Vec3f{rnd.Float32() * 2, rnd.Float32() * 2, rnd.Float32() * 2}
+		boxes[i] = Box3f{p, p.Add(size)}
+	}
+	return boxes
+}
+
+// assertValidBVH checks the tree's structure and that it contains exactly the given items.
+func assertValidBVH(t *testing.T, b *BVH, items map[int]Box3f) {
+	assert.Equal(t, len(items), b.Len())
+	if len(items) == 0 {
+		assert.Equal(t, bvhNull, b.root)
+		return
+	}
+	assert.Equal(t, bvhNull, b.nodes[b.root].parent)
+
+	var check func(node int) int
+	check = func(node int) int {
+		n := b.nodes[node]
+		if n.left == bvhNull {
+			assert.Equal(t, items[n.id], n.box)
+			assert.Equal(t, node, b.leaves[n.id])
+			assert.Equal(t, 0, n.height)
+			return 1
+		}
+		l, r := b.nodes[n.left], b.nodes[n.right]
+		assert.Equal(t, node, l.parent)
+		assert.Equal(t, node, r.parent)
+		assert.Equal(t, l.box.Merge(r.box), n.box)
+		assert.Equal(t, 1+mathi.Max(l.height, r.height), n.height)
+		return check(n.left) + check(n.right)
+	}
+	assert.Equal(t, len(items), check(b.root))
+}
+
+func TestBuildBVH(t *testing.T) {
+	boxes := randomBoxes3f(500, 1)
+	b := BuildBVH(boxes)
+	items := make(map[int]Box3f)
+	for i, box := range boxes {
+		items[i] = box
+	}
+	assertValidBVH(t, b, items)
+	assert.True(t, b.Height() < 30)
+
+	assertValidBVH(t, BuildBVH(nil), nil)
+	assert.Equal(t, -1, BuildBVH(nil).Height())
+
+	// identical boxes can't be split by SAH
+	same := make([]Box3f, 10)
+	for i := range same {
+		same[i] = Box3f{Vec3f{1, 2, 3}, Vec3f{2, 3, 4}}
+	}
+	b = BuildBVH(same)
+	assert.Equal(t, 10, b.Len())
+	assert.Equal(t, same[0], b.Bounds())
+}
+
+func TestBVH_InsertRemove(t *testing.T) {
+	boxes := randomBoxes3f(300, 2)
+	b := NewBVH()
+	items := make(map[int]Box3f)
+	for i, box := range boxes {
+		b.Insert(i, box)
+		items[i] = box
+	}
+	assertValidBVH(t, b, items)
+	assert.True(t, b.Height() < 20)
+
+	for i := 0; i < len(boxes); i += 2 {
+		assert.True(t, b.Remove(i))
+		delete(items, i)
+	}
+	assert.False(t, b.Remove(0))
+	assertValidBVH(t, b, items)
+
+	// update existing items and re-use freed nodes
+	moved := randomBoxes3f(300, 3)
+	for i := range moved {
+		if i%2 == 1 {
+			assert.True(t, b.Update(i, moved[i]))
+		} else {
+			b.Insert(i, moved[i])
+		}
+		items[i] = moved[i]
+	}
+	assert.False(t, b.Update(-1, moved[0]))
+	assertValidBVH(t, b, items)
+	assert.True(t, len(b.nodes) < 2*len(boxes))
+
+	box, ok := b.Box(5)
+	assert.True(t, ok)
+	assert.Equal(t, moved[5], box)
+	_, ok = b.Box(-1)
+	assert.False(t, ok)
+
+	for i := range moved {
+		b.Remove(i)
+	}
+	assertValidBVH(t, b, nil)
+}
+
+func TestBVH_Refit(t *testing.T) {
+	boxes := randomBoxes3f(100, 4)
+	b := BuildBVH(boxes)
+	items := make(map[int]Box3f)
+	for i, box := range boxes {
+		box = box.Add(Vec3f{float32(i % 3), 1, -2})
+		assert.True(t, b.SetBox(i, box))
+		items[i] = box
+	}
+	assert.False(t, b.SetBox(-1, Box3f{}))
+	b.Refit()
+	assertValidBVH(t, b, items)
+}
+
+func TestBVH_QueryBox(t *testing.T) {
+	boxes := randomBoxes3f(500, 5)
+	b := BuildBVH(boxes)
+	for _, query := range randomBoxes3f(50, 6) {
+		query = query.Grow(2)
+		var expected []int
+		for i, box := range boxes {
+			if box.Intersects(query) {
+				expected = append(expected, i)
+			}
+		}
+		actual := b.QueryBox(query, nil)
+		sort.Ints(actual)
+		assert.Equal(t, expected, actual)
+	}
+	assert.Len(t, b.QueryBox(Box3f{Vec3f{-100, -100, -100}, Vec3f{100, 100, 100}}, nil), len(boxes))
+	assert.Empty(t, NewBVH().QueryBox(Box3f{}, nil))
+}
+
+func TestBVH_QuerySphere(t *testing.T) {
+	boxes := randomBoxes3f(500, 7)
+	b := BuildBVH(boxes)
+	for _, center := range randomPoints3f(50, 8) {
+		sphere := Sphere{center, 3}
+		var expected []int
+		for i, box := range boxes {
+			if sphere.IntersectsBox3f(box) {
+				expected = append(expected, i)
+			}
+		}
+		actual := b.QuerySphere(sphere, nil)
+		sort.Ints(actual)
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestBVH_QueryFrustum(t *testing.T) {
+	boxes := randomBoxes3f(500, 9)
+	b := BuildBVH(boxes)
+	f := ViewFrustumFromMatrix(Frustum(-1, 1, -1, 1, 1, 10))
+	var expected []int
+	for i, box := range boxes {
+		if f.IntersectsBox(box) != Outside {
+			expected = append(expected, i)
+		}
+	}
+	actual := b.QueryFrustum(f, nil)
+	sort.Ints(actual)
+	assert.Equal(t, expected, actual)
+}
+
+func TestBVH_RayCast(t *testing.T) {
+	boxes := randomBoxes3f(500, 10)
+	b := BuildBVH(boxes)
+	origins := randomPoints3f(50, 11)
+	targets := randomPoints3f(50, 12)
+	for i := range origins {
+		ray := Ray3f{origins[i].MulScalar(2), targets[i].Sub(origins[i].MulScalar(2)).Normalize()}
+
+		expectedID, expectedDist, expectedOk := 0, float32(100), false
+		for id, box := range boxes {
+			if hit, ok := box.IntersectRay(ray); ok && hit.Distance < expectedDist {
+				expectedID, expectedDist, expectedOk = id, hit.Distance, true
+			}
+		}
+		// ray casts against the items' boxes
+		id, dist, ok := b.RayCast(ray, 100, nil)
+		assert.Equal(t, expectedOk, ok)
+		if ok {
+			assert.Equal(t, expectedID, id)
+			AssertFloat(t, expectedDist, dist)
+		}
+
+		_, _, ok = b.RayCastAny(ray, 100, nil)
+		assert.Equal(t, expectedOk, ok)
+	}
+
+	// starting within a box reports the exit position, like Box3f.IntersectRay
+	inside := Ray3f{boxes[0].Center(), Vec3f{0, 0, 1}}
+	id, dist, ok := b.RayCast(inside, 100, nil)
+	assert.True(t, ok)
+	h, _ := boxes[id].IntersectRay(inside)
+	AssertFloat(t, h.Distance, dist)
+
+	// custom hit test against spheres within the boxes
+	target := boxes[0].Center()
+	ray := Ray3f{Vec3f{-20, target[1], target[2]}, Vec3f{1, 0, 0}}
+	hit := func(id int) (float32, bool) {
+		h, ok := Sphere{boxes[id].Center(), 0.2}.IntersectRay(ray)
+		return h.Distance, ok
+	}
+	expectedID, expectedDist := 0, float32(100)
+	for id := range boxes {
+		if dist, ok := hit(id); ok && dist < expectedDist {
+			expectedID, expectedDist = id, dist
+		}
+	}
+	id, dist, ok = b.RayCast(ray, 100, hit)
+	assert.True(t, ok)
+	assert.Equal(t, expectedID, id)
+	AssertFloat(t, expectedDist, dist)
+
+	_, _, ok = b.RayCast(ray, expectedDist-0.1, hit)
+	assert.False(t, ok)
+	_, _, ok = NewBVH().RayCast(ray, 100, nil)
+	assert.False(t, ok)
+}
+
+func TestBVH_Nearest(t *testing.T) {
+	boxes := randomBoxes3f(500, 13)
+	b := BuildBVH(boxes)
+	centerDist := func(point Vec3f) func(int) float32 {
+		return func(id int) float32 {
+			return boxes[id].Center().SquareDistance(point)
+		}
+	}
+	for _, point := range randomPoints3f(50, 14) {
+		point = point.MulScalar(1.5)
+		expectedDist, expectedCenterDist := float32(1e9), float32(1e9)
+		expectedID := 0
+		for id, box := range boxes {
+			expectedDist = math32.Min(expectedDist, box.SquarePointDistance(point))
+			if d := centerDist(point)(id); d < expectedCenterDist {
+				expectedID, expectedCenterDist = id, d
+			}
+		}
+
+		_, dist, ok := b.Nearest(point, nil)
+		assert.True(t, ok)
+		AssertFloat(t, expectedDist, dist)
+
+		id, dist, ok := b.Nearest(point, centerDist(point))
+		assert.True(t, ok)
+		assert.Equal(t, expectedID, id)
+		AssertFloat(t, expectedCenterDist, dist)
+	}
+	_, _, ok := NewBVH().Nearest(Vec3f{}, nil)
+	assert.False(t, ok)
+}
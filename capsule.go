@@ -0,0 +1,63 @@
+package vmath
+
+import (
+	"fmt"
+	"math"
+)
+
+// Capsule represents a capsule in 3D space.
+// The capsule consists of all points that are within a given radius around the line segment A-B.
+type Capsule struct {
+	A, B   Vec3f
+	Radius float32
+}
+
+func (c Capsule) String() string {
+	return fmt.Sprintf("Capsule([%f x %f x %f]-[%f x %f x %f], %f)",
+		c.A[0], c.A[1], c.A[2],
+		c.B[0], c.B[1], c.B[2],
+		c.Radius)
+}
+
+// Segment returns the capsule's inner line segment.
+func (c Capsule) Segment() Segment3f {
+	return Segment3f{c.A, c.B}
+}
+
+// Box3f returns the smallest axis-aligned box that contains the capsule.
+func (c Capsule) Box3f() Box3f {
+	return Box3fFromCorners(c.A, c.B).Grow(c.Radius)
+}
+
+// Volume returns the capsule's volume.
+func (c Capsule) Volume() float32 {
+	r := c.Radius
+	return math.Pi*r*r*c.A.Distance(c.B) + 4.0/3.0*math.Pi*r*r*r
+}
+
+// ContainsPoint checks if a given point resides within the capsule.
+// Points on the surface are also considered to be contained.
+func (c Capsule) ContainsPoint(point Vec3f) bool {
+	return c.Segment().SquarePointDistance(point) <= c.Radius*c.Radius
+}
+
+// IntersectsCapsule checks if this capsule intersects another capsule.
+// Touching capsules are also considered to intersect.
+func (c Capsule) IntersectsCapsule(other Capsule) bool {
+	// Source: "Real-Time Collision Detection" by Christer Ericson, chapter 4.5.1
+	r := c.Radius + other.Radius
+	return c.Segment().SquareDistance(other.Segment()) <= r*r
+}
+
+// IntersectsSphere checks if the capsule intersects a sphere.
+// Touching volumes are also considered to intersect.
+func (c Capsule) IntersectsSphere(sphere Sphere) bool {
+	r := c.Radius + sphere.Radius
+	return c.Segment().SquarePointDistance(sphere.Center) <= r*r
+}
+
+// IntersectsBox3f checks if the capsule intersects an axis-aligned box.
+// Touching volumes are also considered to intersect.
+func (c Capsule) IntersectsBox3f(box Box3f) bool {
+	return c.Segment().SquareBox3fDistance(box) <= c.Radius*c.Radius
+}
@@ -0,0 +1,61 @@
+package vmath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapsule_String(t *testing.T) {
+	c := Capsule{Vec3f{1, 2, 3}, Vec3f{4, 5, 6}, 7}
+	assert.Equal(t, "Capsule([1.000000 x 2.000000 x 3.000000]-[4.000000 x 5.000000 x 6.000000], 7.000000)", c.String())
+}
+
+func TestCapsule_Box3f(t *testing.T) {
+	c := Capsule{Vec3f{4, 2, 3}, Vec3f{1, 5, 3}, 1}
+	assert.Equal(t, Box3f{Vec3f{0, 1, 2}, Vec3f{5, 6, 4}}, c.Box3f())
+}
+
+func TestCapsule_Volume(t *testing.T) {
+	c := Capsule{Vec3f{0, 0, 0}, Vec3f{0, 3, 0}, 2}
+	AssertFloat(t, pi*4*3+4.0/3.0*pi*8, c.Volume())
+}
+
+func TestCapsule_ContainsPoint(t *testing.T) {
+	c := Capsule{Vec3f{0, 0, 0}, Vec3f{0, 3, 0}, 1}
+	assert.True(t, c.ContainsPoint(Vec3f{0, 0, 0}))
+	assert.True(t, c.ContainsPoint(Vec3f{1, 2, 0}))
+	assert.True(t, c.ContainsPoint(Vec3f{0, 4, 0}))
+	assert.False(t, c.ContainsPoint(Vec3f{0, 4.1, 0}))
+	assert.False(t, c.ContainsPoint(Vec3f{0.8, -0.8, 0}))
+}
+
+func TestCapsule_IntersectsCapsule(t *testing.T) {
+	c := Capsule{Vec3f{0, 0, 0}, Vec3f{0, 3, 0}, 1}
+
+	assert.True(t, c.IntersectsCapsule(Capsule{Vec3f{-5, 2, 1.5}, Vec3f{5, 2, 1.5}, 0.6}))
+	assert.True(t, c.IntersectsCapsule(Capsule{Vec3f{-5, 2, 1.5}, Vec3f{5, 2, 1.5}, 0.5})) // touching
+	assert.False(t, c.IntersectsCapsule(Capsule{Vec3f{-5, 2, 1.5}, Vec3f{5, 2, 1.5}, 0.4}))
+	assert.False(t, c.IntersectsCapsule(Capsule{Vec3f{0, 5, 0}, Vec3f{0, 9, 0}, 0.5}))
+}
+
+func TestCapsule_IntersectsSphere(t *testing.T) {
+	c := Capsule{Vec3f{0, 0, 0}, Vec3f{0, 3, 0}, 1}
+
+	assert.True(t, c.IntersectsSphere(Sphere{Vec3f{2, 1, 0}, 1.5}))
+	assert.True(t, c.IntersectsSphere(Sphere{Vec3f{0, 5, 0}, 1}))
+	assert.False(t, c.IntersectsSphere(Sphere{Vec3f{0, 5, 0}, 0.9}))
+	assert.False(t, c.IntersectsSphere(Sphere{Vec3f{2, 1, 0}, 0.9}))
+}
+
+func TestCapsule_IntersectsBox3f(t *testing.T) {
+	box := Box3f{Vec3f{0, 0, 0}, Vec3f{2, 2, 2}}
+
+	assert.True(t, Capsule{Vec3f{1, 1, 1}, Vec3f{1, 1, 1}, 0.1}.IntersectsBox3f(box))
+	assert.True(t, Capsule{Vec3f{-1, 3, 1}, Vec3f{3, 3, 1}, 1.1}.IntersectsBox3f(box))
+	assert.False(t, Capsule{Vec3f{-1, 3, 1}, Vec3f{3, 3, 1}, 0.9}.IntersectsBox3f(box))
+
+	// near an edge, but outside of it
+	assert.True(t, Capsule{Vec3f{3, 3, -5}, Vec3f{3, 3, 5}, 1.5}.IntersectsBox3f(box))
+	assert.False(t, Capsule{Vec3f{3, 3, -5}, Vec3f{3, 3, 5}, 1.4}.IntersectsBox3f(box))
+}
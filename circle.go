@@ -0,0 +1,244 @@
+package vmath
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/maja42/vmath/math32"
+)
+
+// Circle represents a circle on the 2D plane.
+type Circle struct {
+	Center Vec2f
+	Radius float32
+}
+
+func (c Circle) String() string {
+	return fmt.Sprintf("Circle([%f x %f], %f)", c.Center[0], c.Center[1], c.Radius)
+}
+
+// Rectf returns the smallest axis-aligned rectangle that contains the circle.
+func (c Circle) Rectf() Rectf {
+	return Rectf{
+		Min: c.Center.SubScalar(c.Radius),
+		Max: c.Center.AddScalar(c.Radius),
+	}
+}
+
+// Area returns the circle's area.
+func (c Circle) Area() float32 {
+	return math.Pi * c.Radius * c.Radius
+}
+
+// ContainsPoint checks if a given point resides within the circle.
+// Points on the circle's edge are also considered to be contained.
+func (c Circle) ContainsPoint(point Vec2f) bool {
+	return c.Center.SquareDistance(point) <= c.Radius*c.Radius
+}
+
+// ContainsCircle checks if this circle completely contains another circle.
+func (c Circle) ContainsCircle(other Circle) bool {
+	if other.Radius > c.Radius {
+		return false
+	}
+	d := c.Radius - other.Radius
+	return c.Center.SquareDistance(other.Center) <= d*d
+}
+
+// Intersects checks if this circle intersects another circle.
+// Touching circles are also considered to intersect.
+func (c Circle) Intersects(other Circle) bool {
+	r := c.Radius + other.Radius
+	return c.Center.SquareDistance(other.Center) <= r*r
+}
+
+// IntersectsRectf checks if the circle intersects a rectangle.
+// Touching shapes are also considered to intersect.
+func (c Circle) IntersectsRectf(rect Rectf) bool {
+	return rect.SquarePointDistance(c.Center) <= c.Radius*c.Radius
+}
+
+// Merge returns the smallest circle that contains both circles.
+func (c Circle) Merge(other Circle) Circle {
+	if c.ContainsCircle(other) {
+		return c
+	}
+	if other.ContainsCircle(c) {
+		return other
+	}
+	dist := c.Center.Distance(other.Center)
+	radius := (dist + c.Radius + other.Radius) / 2
+	center := c.Center
+	if dist > 0 {
+		center = center.Add(other.Center.Sub(c.Center).MulScalar((radius - c.Radius) / dist))
+	}
+	return Circle{center, radius}
+}
+
+// ExtendPoint returns the smallest circle that contains both, the original circle and the given point.
+// The circle's center is moved towards the point if necessary.
+func (c Circle) ExtendPoint(point Vec2f) Circle {
+	return c.Merge(Circle{point, 0})
+}
+
+// BoundingCircle returns a circle that contains all given points.
+// The circle is calculated with Ritter's algorithm, which is fast,
+// but usually results in circles that are bigger than the minimal bounding circle.
+// If no points are given, an empty circle at the origin is returned.
+func BoundingCircle(points []Vec2f) Circle {
+	// Source: "An Efficient Bounding Sphere" by Jack Ritter, Graphics Gems, 1990.
+	if len(points) == 0 {
+		return Circle{}
+	}
+	// find two points that are far away from each other
+	farthest := func(from Vec2f) Vec2f {
+		best, bestDist := from, float32(-1)
+		for _, p := range points {
+			if d := from.SquareDistance(p); d > bestDist {
+				best, bestDist = p, d
+			}
+		}
+		return best
+	}
+	a := farthest(points[0])
+	b := farthest(a)
+	c := Circle{
+		Center: a.Lerp(b, 0.5),
+		Radius: a.Distance(b) / 2,
+	}
+	// grow the circle to include all points
+	for _, p := range points {
+		if !c.ContainsPoint(p) {
+			c = c.ExtendPoint(p)
+		}
+	}
+	return c.enclose(points)
+}
+
+// MinimumBoundingCircle returns the smallest circle that contains all given points.
+// The circle is calculated with Welzl's algorithm in expected linear time.
+// If no points are given, an empty circle at the origin is returned.
+func MinimumBoundingCircle(points []Vec2f) Circle {
+	// Source: "Smallest enclosing disks (balls and ellipsoids)" by Emo Welzl, 1991.
+	// Uses the iterative formulation, which avoids deep recursion.
+	if len(points) == 0 {
+		return Circle{}
+	}
+
+	pts := make([]vec2d, len(points))
+	for i, p := range points {
+		pts[i] = vec2d{float64(p[0]), float64(p[1])}
+	}
+	// random order results in expected linear runtime; use a fixed seed for reproducible results
+	rnd := rand.New(rand.NewSource(1))
+	rnd.Shuffle(len(pts), func(i, j int) {
+		pts[i], pts[j] = pts[j], pts[i]
+	})
+
+	c := circled{pts[0], 0}
+	for i := 1; i < len(pts); i++ {
+		if c.contains(pts[i]) {
+			continue
+		}
+		c = circled{pts[i], 0}
+		for j := 0; j < i; j++ {
+			if c.contains(pts[j]) {
+				continue
+			}
+			c = circleFrom2(pts[i], pts[j])
+			for k := 0; k < j; k++ {
+				if c.contains(pts[k]) {
+					continue
+				}
+				c = circleFrom3(pts[i], pts[j], pts[k])
+			}
+		}
+	}
+	return Circle{
+		Center: Vec2f{float32(c.center[0]), float32(c.center[1])},
+		Radius: float32(math.Sqrt(c.sqRadius)),
+	}.enclose(points)
+}
+
+// enclose grows the circle's radius until ContainsPoint succeeds for all given points.
+// This compensates rounding errors, so that bounding circles are conservative.
+func (c Circle) enclose(points []Vec2f) Circle {
+	for _, p := range points {
+		c.Radius = math32.Max(c.Radius, c.Center.Distance(p))
+		for !c.ContainsPoint(p) {
+			c.Radius = math.Nextafter32(c.Radius, math32.Infinity)
+		}
+	}
+	return c
+}
+
+// vec2d is a float64 vector for intermediate calculations that require higher precision.
+type vec2d [2]float64
+
+func (v vec2d) add(o vec2d) vec2d {
+	return vec2d{v[0] + o[0], v[1] + o[1]}
+}
+
+func (v vec2d) sub(o vec2d) vec2d {
+	return vec2d{v[0] - o[0], v[1] - o[1]}
+}
+
+func (v vec2d) mul(s float64) vec2d {
+	return vec2d{v[0] * s, v[1] * s}
+}
+
+func (v vec2d) dot(o vec2d) float64 {
+	return v[0]*o[0] + v[1]*o[1]
+}
+
+func (v vec2d) cross(o vec2d) float64 {
+	return v[0]*o[1] - v[1]*o[0]
+}
+
+func (v vec2d) sqDist(o vec2d) float64 {
+	d := v.sub(o)
+	return d.dot(d)
+}
+
+// circled is a float64 circle for the minimum bounding circle calculation.
+type circled struct {
+	center   vec2d
+	sqRadius float64
+}
+
+func (c circled) contains(p vec2d) bool {
+	// allow for small rounding errors; otherwise points on the boundary can cause unnecessary recalculations
+	return c.center.sqDist(p) <= c.sqRadius*(1+1e-9)+1e-12
+}
+
+// circleFrom2 returns the smallest circle with both points on its edge.
+func circleFrom2(a, b vec2d) circled {
+	center := a.add(b).mul(0.5)
+	return circled{center, center.sqDist(a)}
+}
+
+// circleFrom3 returns the circle with all three points on its edge.
+func circleFrom3(a, b, c vec2d) circled {
+	// Source: https://en.wikipedia.org/wiki/Circumscribed_circle#Cartesian_coordinates_2
+	ab := b.sub(a)
+	ac := c.sub(a)
+	d := 2 * ab.cross(ac)
+	if math.Abs(d) <= 1e-12*(ab.dot(ab)+ac.dot(ac)) { // collinear
+		res := circleFrom2(a, b)
+		if other := circleFrom2(a, c); other.sqRadius > res.sqRadius {
+			res = other
+		}
+		if other := circleFrom2(b, c); other.sqRadius > res.sqRadius {
+			res = other
+		}
+		return res
+	}
+	abSq := ab.dot(ab)
+	acSq := ac.dot(ac)
+	offset := vec2d{
+		(ac[1]*abSq - ab[1]*acSq) / d,
+		(ab[0]*acSq - ac[0]*abSq) / d,
+	}
+	return circled{a.add(offset), offset.dot(offset)}
+}
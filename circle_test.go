@@ -0,0 +1,143 @@
+package vmath
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/maja42/vmath/math32"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircle_String(t *testing.T) {
+	c := Circle{Vec2f{1, 2}, 4}
+	assert.Equal(t, "Circle([1.000000 x 2.000000], 4.000000)", c.String())
+}
+
+func TestCircle_Rectf(t *testing.T) {
+	c := Circle{Vec2f{1, 2}, 4}
+	assert.Equal(t, Rectf{Vec2f{-3, -2}, Vec2f{5, 6}}, c.Rectf())
+}
+
+func TestCircle_Area(t *testing.T) {
+	AssertFloat(t, 4*pi, Circle{Vec2f{1, 2}, 2}.Area())
+}
+
+func TestCircle_ContainsPoint(t *testing.T) {
+	c := Circle{Vec2f{1, 2}, 2}
+	assert.True(t, c.ContainsPoint(Vec2f{1, 2}))
+	assert.True(t, c.ContainsPoint(Vec2f{1, 4}))
+	assert.True(t, c.ContainsPoint(Vec2f{2, 3}))
+	assert.False(t, c.ContainsPoint(Vec2f{1, 4.1}))
+	assert.False(t, c.ContainsPoint(Vec2f{3, 4}))
+}
+
+func TestCircle_ContainsCircle(t *testing.T) {
+	c := Circle{Vec2f{0, 0}, 5}
+	assert.True(t, c.ContainsCircle(c))
+	assert.True(t, c.ContainsCircle(Circle{Vec2f{2, 0}, 3}))
+	assert.False(t, c.ContainsCircle(Circle{Vec2f{2.5, 0}, 3}))
+	assert.False(t, c.ContainsCircle(Circle{Vec2f{0, 0}, 6}))
+}
+
+func TestCircle_Intersects(t *testing.T) {
+	c := Circle{Vec2f{0, 0}, 2}
+	assert.True(t, c.Intersects(Circle{Vec2f{3, 0}, 1.5}))
+	assert.True(t, c.Intersects(Circle{Vec2f{0, 3}, 1}))
+	assert.False(t, c.Intersects(Circle{Vec2f{3, 0}, 0.5}))
+}
+
+func TestCircle_IntersectsRectf(t *testing.T) {
+	c := Circle{Vec2f{0, 0}, 2}
+	assert.True(t, c.IntersectsRectf(Rectf{Vec2f{-1, -1}, Vec2f{1, 1}}))
+	assert.True(t, c.IntersectsRectf(Rectf{Vec2f{1, 1}, Vec2f{5, 5}}))
+	assert.True(t, c.IntersectsRectf(Rectf{Vec2f{-10, -10}, Vec2f{10, 10}}))
+	assert.False(t, c.IntersectsRectf(Rectf{Vec2f{1.5, 1.5}, Vec2f{5, 5}}))
+	assert.False(t, c.IntersectsRectf(Rectf{Vec2f{-1, 2.5}, Vec2f{1, 5}}))
+}
+
+func TestCircle_Merge(t *testing.T) {
+	a := Circle{Vec2f{0, 0}, 1}
+	b := Circle{Vec2f{4, 0}, 1}
+	m := a.Merge(b)
+	AssertVec2f(t, Vec2f{2, 0}, m.Center)
+	AssertFloat(t, 3, m.Radius)
+
+	b = Circle{Vec2f{0, 0.5}, 3}
+	assert.Equal(t, b, a.Merge(b))
+	assert.Equal(t, b, b.Merge(a))
+
+	m = a.ExtendPoint(Vec2f{0, -3})
+	AssertVec2f(t, Vec2f{0, -1}, m.Center)
+	AssertFloat(t, 2, m.Radius)
+}
+
+func TestBoundingCircle(t *testing.T) {
+	assert.Equal(t, Circle{}, BoundingCircle(nil))
+
+	// the circle is conservative despite rounding errors
+	for seed := int64(0); seed < 50; seed++ {
+		points := randomPoints2f(100, seed)
+		for i := range points {
+			points[i] = points[i].Add(Vec2f{100, -30})
+		}
+		c := BoundingCircle(points)
+		for _, p := range points {
+			assert.True(t, c.ContainsPoint(p))
+		}
+	}
+}
+
+func TestMinimumBoundingCircle(t *testing.T) {
+	assert.Equal(t, Circle{}, MinimumBoundingCircle(nil))
+	assert.Equal(t, Circle{Vec2f{1, 2}, 0}, MinimumBoundingCircle([]Vec2f{{1, 2}}))
+
+	// square with inner points
+	c := MinimumBoundingCircle([]Vec2f{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {1, 1}, {0.5, 1.5}})
+	AssertVec2f(t, Vec2f{1, 1}, c.Center)
+	AssertFloat(t, math32.Sqrt(2), c.Radius)
+
+	// acute triangle: circumscribed circle
+	c = MinimumBoundingCircle([]Vec2f{{-1, 0}, {1, 0}, {0, 2}})
+	AssertVec2f(t, Vec2f{0, 0.75}, c.Center)
+	AssertFloat(t, 1.25, c.Radius)
+
+	// right triangle: the radius is not exactly representable
+	points := []Vec2f{{0, 0}, {1, 0}, {0, 1}}
+	c = MinimumBoundingCircle(points)
+	AssertVec2f(t, Vec2f{0.5, 0.5}, c.Center)
+	AssertFloat(t, math32.Sqrt(0.5), c.Radius)
+	for _, p := range points {
+		assert.True(t, c.ContainsPoint(p))
+	}
+
+	// obtuse triangle
+	c = MinimumBoundingCircle([]Vec2f{{-2, 0}, {2, 0}, {0, 0.5}})
+	AssertVec2f(t, Vec2f{0, 0}, c.Center)
+	AssertFloat(t, 2, c.Radius)
+
+	// collinear
+	c = MinimumBoundingCircle([]Vec2f{{0, 0}, {1, 1}, {3, 3}, {-1, -1}})
+	AssertVec2f(t, Vec2f{1, 1}, c.Center)
+	AssertFloat(t, 2*math32.Sqrt(2), c.Radius)
+
+	for seed := int64(0); seed < 50; seed++ {
+		points := randomPoints2f(100, seed)
+		for i := range points {
+			points[i] = points[i].Add(Vec2f{100, -30})
+		}
+		c := MinimumBoundingCircle(points)
+		for _, p := range points {
+			assert.True(t, c.ContainsPoint(p))
+		}
+		assert.True(t, c.Radius <= BoundingCircle(points).Radius*(1+eps))
+	}
+}
+
+func randomPoints2f(count int, seed int64) []Vec2f {
+	rnd := rand.New(rand.NewSource(seed))
+	points := make([]Vec2f, count)
+	for i := range points {
+		points[i] = Vec2f{rnd.Float32()*20 - 10, rnd.Float32()*20 - 10}
+	}
+	return points
+}
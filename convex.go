@@ -0,0 +1,191 @@
+package vmath
+
+// ConvexShape3f is a convex shape, described by its support function.
+// It can be used for generic collision detection with GJK and EPA.
+type ConvexShape3f interface {
+	// Support returns the shape's farthest point in the given direction.
+	// The direction does not need to be normalized.
+	Support(dir Vec3f) Vec3f
+}
+
+// ConvexShape2f is a convex shape, described by its support function.
+// It can be used for generic collision detection with GJK and EPA.
+type ConvexShape2f interface {
+	// Support returns the shape's farthest point in the given direction.
+	// The direction does not need to be normalized.
+	Support(dir Vec2f) Vec2f
+}
+
+// PointCloud3f is the convex hull of a set of points.
+// The point cloud must not be empty.
+type PointCloud3f []Vec3f
+
+// Support returns the point cloud's farthest point in the given direction.
+func (p PointCloud3f) Support(dir Vec3f) Vec3f {
+	best, bestDot := p[0], p[0].Dot(dir)
+	for _, point := range p[1:] {
+		if d := point.Dot(dir); d > bestDot {
+			best, bestDot = point, d
+		}
+	}
+	return best
+}
+
+// PointCloud2f is the convex hull of a set of points.
+// The point cloud must not be empty.
+type PointCloud2f []Vec2f
+
+// Support returns the point cloud's farthest point in the given direction.
+func (p PointCloud2f) Support(dir Vec2f) Vec2f {
+	best, bestDot := p[0], p[0].Dot(dir)
+	for _, point := range p[1:] {
+		if d := point.Dot(dir); d > bestDot {
+			best, bestDot = point, d
+		}
+	}
+	return best
+}
+
+// Support returns the sphere's farthest point in the given direction.
+func (s Sphere) Support(dir Vec3f) Vec3f {
+	return s.Center.Add(dir.Normalize().MulScalar(s.Radius))
+}
+
+// Support returns the box's farthest corner in the given direction.
+func (b Box3f) Support(dir Vec3f) Vec3f {
+	var res Vec3f
+	for i := range res {
+		if dir[i] < 0 {
+			res[i] = b.Min[i]
+		} else {
+			res[i] = b.Max[i]
+		}
+	}
+	return res
+}
+
+// Support returns the box's farthest corner in the given direction.
+func (o OBB3f) Support(dir Vec3f) Vec3f {
+	local := o.Rotation.Conjugate().RotateVec(dir)
+	for i, val := range local {
+		if val < 0 {
+			local[i] = -o.HalfExtents[i]
+		} else {
+			local[i] = o.HalfExtents[i]
+		}
+	}
+	return o.ToWorld(local)
+}
+
+// Support returns the capsule's farthest point in the given direction.
+func (c Capsule) Support(dir Vec3f) Vec3f {
+	return c.Segment().Support(dir).Add(dir.Normalize().MulScalar(c.Radius))
+}
+
+// Support returns the segment's endpoint that is farthest in the given direction.
+func (s Segment3f) Support(dir Vec3f) Vec3f {
+	if s.B.Dot(dir) > s.A.Dot(dir) {
+		return s.B
+	}
+	return s.A
+}
+
+// Support returns the triangle's corner that is farthest in the given direction.
+func (t Triangle3f) Support(dir Vec3f) Vec3f {
+	return PointCloud3f{t.A, t.B, t.C}.Support(dir)
+}
+
+// Support returns the circle's farthest point in the given direction.
+func (c Circle) Support(dir Vec2f) Vec2f {
+	return c.Center.Add(dir.Normalize().MulScalar(c.Radius))
+}
+
+// Support returns the rectangle's farthest corner in the given direction.
+func (r Rectf) Support(dir Vec2f) Vec2f {
+	var res Vec2f
+	for i := range res {
+		if dir[i] < 0 {
+			res[i] = r.Min[i]
+		} else {
+			res[i] = r.Max[i]
+		}
+	}
+	return res
+}
+
+// Support returns the rectangle's farthest corner in the given direction.
+func (o OBB2f) Support(dir Vec2f) Vec2f {
+	var local Vec2f
+	for i, axis := range o.Axes() {
+		if dir.Dot(axis) < 0 {
+			local[i] = -o.HalfExtents[i]
+		} else {
+			local[i] = o.HalfExtents[i]
+		}
+	}
+	return o.ToWorld(local)
+}
+
+// Support returns the segment's endpoint that is farthest in the given direction.
+func (s Segment2f) Support(dir Vec2f) Vec2f {
+	if s.B.Dot(dir) > s.A.Dot(dir) {
+		return s.B
+	}
+	return s.A
+}
+
+// MinkowskiSum3f is the Minkowski sum of two convex shapes, containing the sums of all points within A and B.
+// For example, the sum of a box and a sphere is a box with rounded edges and corners.
+type MinkowskiSum3f struct {
+	A, B ConvexShape3f
+}
+
+// Support returns the Minkowski sum's farthest point in the given direction.
+func (m MinkowskiSum3f) Support(dir Vec3f) Vec3f {
+	return m.A.Support(dir).Add(m.B.Support(dir))
+}
+
+// MinkowskiDifference3f is the Minkowski difference of two convex shapes, containing the differences
+// of all points within A and B. It contains the origin if, and only if, the shapes overlap.
+type MinkowskiDifference3f struct {
+	A, B ConvexShape3f
+}
+
+// Support returns the Minkowski difference's farthest point in the given direction.
+func (m MinkowskiDifference3f) Support(dir Vec3f) Vec3f {
+	return m.A.Support(dir).Sub(m.B.Support(dir.Negate()))
+}
+
+// MinkowskiSum2f is the Minkowski sum of two convex shapes, containing the sums of all points within A and B.
+// For example, the sum of a rectangle and a circle is a rectangle with rounded corners.
+type MinkowskiSum2f struct {
+	A, B ConvexShape2f
+}
+
+// Support returns the Minkowski sum's farthest point in the given direction.
+func (m MinkowskiSum2f) Support(dir Vec2f) Vec2f {
+	return m.A.Support(dir).Add(m.B.Support(dir))
+}
+
+// MinkowskiDifference2f is the Minkowski difference of two convex shapes, containing the differences
+// of all points within A and B. It contains the origin if, and only if, the shapes overlap.
+type MinkowskiDifference2f struct {
+	A, B ConvexShape2f
+}
+
+// Support returns the Minkowski difference's farthest point in the given direction.
+func (m MinkowskiDifference2f) Support(dir Vec2f) Vec2f {
+	return m.A.Support(dir).Sub(m.B.Support(dir.Negate()))
+}
+
+// MinkowskiSum returns the Minkowski sum of two polygons, which are treated as their convex hulls.
+// The result is in counter-clockwise order.
+func (p Polygon2f) MinkowskiSum(other Polygon2f) Polygon2f {
+	sums := make([]Vec2f, 0, len(p)*len(other))
+	for _, a := range p {
+		for _, b := range other {
+			sums = append(sums, a.Add(b))
+		}
+	}
+	return ConvexHull2f(sums)
+}
@@ -0,0 +1,120 @@
+package vmath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPointCloud3f_Support(t *testing.T) {
+	p := PointCloud3f{{0, 0, 0}, {1, 0, 0}, {0, 2, 0}, {0, 0, 3}}
+	assert.Equal(t, Vec3f{1, 0, 0}, p.Support(Vec3f{1, 0, 0}))
+	assert.Equal(t, Vec3f{0, 2, 0}, p.Support(Vec3f{1, 1, 0}))
+	assert.Equal(t, Vec3f{0, 0, 3}, p.Support(Vec3f{0, 0, 1}))
+	assert.Equal(t, Vec3f{0, 0, 0}, p.Support(Vec3f{-1, -1, -1}))
+}
+
+func TestPointCloud2f_Support(t *testing.T) {
+	p := PointCloud2f{{0, 0}, {2, 0}, {0, 3}}
+	assert.Equal(t, Vec2f{2, 0}, p.Support(Vec2f{1, 0}))
+	assert.Equal(t, Vec2f{0, 3}, p.Support(Vec2f{1, 1}))
+	assert.Equal(t, Vec2f{0, 0}, p.Support(Vec2f{-1, -1}))
+}
+
+func TestSphere_Support(t *testing.T) {
+	s := Sphere{Vec3f{1, 2, 3}, 2}
+	AssertVec3f(t, Vec3f{3, 2, 3}, s.Support(Vec3f{5, 0, 0}))
+	AssertVec3f(t, Vec3f{1, 2, 1}, s.Support(Vec3f{0, 0, -1}))
+}
+
+func TestBox3f_Support(t *testing.T) {
+	b := Box3f{Vec3f{-1, -2, -3}, Vec3f{1, 2, 3}}
+	assert.Equal(t, Vec3f{1, -2, 3}, b.Support(Vec3f{1, -1, 1}))
+	assert.Equal(t, Vec3f{-1, 2, -3}, b.Support(Vec3f{-1, 1, -1}))
+}
+
+func TestOBB3f_Support(t *testing.T) {
+	o := OBB3f{
+		Center:      Vec3f{1, 0, 0},
+		HalfExtents: Vec3f{2, 1, 1},
+		Rotation:    QuatFromAxisAngle(Vec3f{0, 0, 1}, pi/2),
+	}
+	AssertVec3f(t, Vec3f{2, 2, 1}, o.Support(Vec3f{1, 1, 1}))
+	AssertVec3f(t, Vec3f{0, -2, -1}, o.Support(Vec3f{-1, -1, -1}))
+}
+
+func TestCapsule_Support(t *testing.T) {
+	c := Capsule{Vec3f{0, 0, 0}, Vec3f{0, 4, 0}, 1}
+	AssertVec3f(t, Vec3f{0, 5, 0}, c.Support(Vec3f{0, 1, 0}))
+	AssertVec3f(t, Vec3f{0, -1, 0}, c.Support(Vec3f{0, -1, 0}))
+	AssertVec3f(t, Vec3f{0.70710678, 4.70710678, 0}, c.Support(Vec3f{1, 1, 0}))
+}
+
+func TestTriangle3f_Support(t *testing.T) {
+	tri := Triangle3f{Vec3f{0, 0, 0}, Vec3f{1, 0, 0}, Vec3f{0, 1, 0}}
+	assert.Equal(t, Vec3f{1, 0, 0}, tri.Support(Vec3f{1, 0, 0}))
+	assert.Equal(t, Vec3f{0, 1, 0}, tri.Support(Vec3f{-1, 1, 0}))
+	assert.Equal(t, Vec3f{0, 0, 0}, tri.Support(Vec3f{-1, -1, 0}))
+}
+
+func TestCircle_Support(t *testing.T) {
+	c := Circle{Vec2f{1, 2}, 3}
+	AssertVec2f(t, Vec2f{1, 5}, c.Support(Vec2f{0, 2}))
+	AssertVec2f(t, Vec2f{-2, 2}, c.Support(Vec2f{-1, 0}))
+}
+
+func TestRectf_Support(t *testing.T) {
+	r := Rectf{Vec2f{-1, -2}, Vec2f{3, 4}}
+	assert.Equal(t, Vec2f{3, -2}, r.Support(Vec2f{1, -1}))
+	assert.Equal(t, Vec2f{-1, 4}, r.Support(Vec2f{-1, 1}))
+}
+
+func TestMinkowskiSum3f_Support(t *testing.T) {
+	m := MinkowskiSum3f{
+		A: Box3f{Vec3f{-1, -1, -1}, Vec3f{1, 1, 1}},
+		B: Sphere{Vec3f{5, 0, 0}, 1},
+	}
+	AssertVec3f(t, Vec3f{7, 1, 1}, m.Support(Vec3f{1, 0, 0}))
+	AssertVec3f(t, Vec3f{6, 1, -2}, m.Support(Vec3f{0, 0, -1}))
+}
+
+func TestMinkowskiDifference3f_Support(t *testing.T) {
+	m := MinkowskiDifference3f{
+		A: Box3f{Vec3f{-1, -1, -1}, Vec3f{1, 1, 1}},
+		B: Box3f{Vec3f{2, 0, 0}, Vec3f{3, 1, 1}},
+	}
+	assert.Equal(t, Vec3f{-1, 1, 1}, m.Support(Vec3f{1, 1, 1}))
+	assert.Equal(t, Vec3f{-4, -2, -2}, m.Support(Vec3f{-1, -1, -1}))
+}
+
+func TestMinkowskiSum2f_Support(t *testing.T) {
+	m := MinkowskiSum2f{
+		A: Rectf{Vec2f{0, 0}, Vec2f{2, 1}},
+		B: Circle{Vec2f{0, 0}, 1},
+	}
+	AssertVec2f(t, Vec2f{3, 1}, m.Support(Vec2f{1, 0}))
+	AssertVec2f(t, Vec2f{2, 2}, m.Support(Vec2f{0, 1}))
+}
+
+func TestMinkowskiDifference2f_Support(t *testing.T) {
+	m := MinkowskiDifference2f{
+		A: Rectf{Vec2f{0, 0}, Vec2f{2, 1}},
+		B: Rectf{Vec2f{1, 1}, Vec2f{2, 3}},
+	}
+	assert.Equal(t, Vec2f{1, 0}, m.Support(Vec2f{1, 1}))
+	assert.Equal(t, Vec2f{-2, -3}, m.Support(Vec2f{-1, -1}))
+}
+
+func TestPolygon2f_MinkowskiSum(t *testing.T) {
+	square := Polygon2f{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+	triangle := Polygon2f{{0, 0}, {2, 0}, {0, 2}}
+	sum := square.MinkowskiSum(triangle)
+	assert.Equal(t, Polygon2f{{0, 0}, {3, 0}, {3, 1}, {1, 3}, {0, 3}}, sum)
+	AssertFloat(t, 7, sum.Area())
+}
+
+func TestOBB2f_Support(t *testing.T) {
+	o := OBB2f{Vec2f{1, 0}, Vec2f{2, 1}, pi / 2}
+	AssertVec2f(t, Vec2f{2, 2}, o.Support(Vec2f{1, 1}))
+	AssertVec2f(t, Vec2f{0, -2}, o.Support(Vec2f{-1, -1}))
+}
@@ -0,0 +1,200 @@
+package vmath
+
+import (
+	"sort"
+)
+
+// ConvexHull2f returns the convex hull of a set of points, using Andrew's monotone chain algorithm.
+// The hull's vertices are returned in counter-clockwise order, starting with the leftmost (bottom-most) point.
+// Points that lie on the hull's edges are dropped.
+// Duplicate points are ignored. If all points are collinear, the two outermost points are returned.
+func ConvexHull2f(points []Vec2f) []Vec2f {
+	// Source: "Another efficient algorithm for convex hulls in two dimensions" by A.M. Andrew, 1979.
+	pts := sortedUnique2f(points)
+	if len(pts) < 3 {
+		return pts
+	}
+	hull := make([]Vec2f, 0, 2*len(pts))
+	// lower hull
+	for _, p := range pts {
+		for len(hull) >= 2 && !isLeftTurn2f(hull[len(hull)-2], hull[len(hull)-1], p) {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	// upper hull
+	lower := len(hull) + 1
+	for i := len(pts) - 2; i >= 0; i-- {
+		p := pts[i]
+		for len(hull) >= lower && !isLeftTurn2f(hull[len(hull)-2], hull[len(hull)-1], p) {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	// the first point is repeated at the end
+	return hull[:len(hull)-1]
+}
+
+// ConvexHull2fCollinear returns the convex hull of a set of points, like ConvexHull2f.
+// In contrast to ConvexHull2f, points that lie on the hull's edges are kept.
+// Points that are nearly collinear with a hull edge (within a relative tolerance of Epsilon) are kept as well.
+// If all points are collinear, they are returned sorted from left to right (bottom to top).
+func ConvexHull2fCollinear(points []Vec2f) []Vec2f {
+	// The lower and upper chain are built separately, and only strict right turns are removed.
+	// Vertical runs of collinear points at both ends end up in exactly one of the chains.
+	pts := sortedUnique2f(points)
+	if len(pts) < 3 {
+		return pts
+	}
+	lower := make([]Vec2f, 0, len(pts))
+	for _, p := range pts {
+		for len(lower) >= 2 && isRightTurn2f(lower[len(lower)-2], lower[len(lower)-1], p) {
+			lower = lower[:len(lower)-1]
+		}
+		lower = append(lower, p)
+	}
+	upper := make([]Vec2f, 0, len(pts))
+	for i := len(pts) - 1; i >= 0; i-- {
+		p := pts[i]
+		for len(upper) >= 2 && isRightTurn2f(upper[len(upper)-2], upper[len(upper)-1], p) {
+			upper = upper[:len(upper)-1]
+		}
+		upper = append(upper, p)
+	}
+	if len(lower) == len(pts) && len(upper) == len(pts) { // all points are collinear
+		return pts
+	}
+	// both chains contain the leftmost and rightmost point
+	return append(lower[:len(lower)-1], upper[:len(upper)-1]...)
+}
+
+// ConvexHull2i returns the convex hull of a set of points, using Andrew's monotone chain algorithm.
+// The hull's vertices are returned in counter-clockwise order, starting with the leftmost (bottom-most) point.
+// Points that lie on the hull's edges are dropped.
+// Duplicate points are ignored. If all points are collinear, the two outermost points are returned.
+// All calculations are exact, as long as the coordinates are small enough to avoid integer overflows.
+func ConvexHull2i(points []Vec2i) []Vec2i {
+	// Source: "Another efficient algorithm for convex hulls in two dimensions" by A.M. Andrew, 1979.
+	pts := sortedUnique2i(points)
+	if len(pts) < 3 {
+		return pts
+	}
+	hull := make([]Vec2i, 0, 2*len(pts))
+	// lower hull
+	for _, p := range pts {
+		for len(hull) >= 2 && !isLeftTurn2i(hull[len(hull)-2], hull[len(hull)-1], p) {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	// upper hull
+	lower := len(hull) + 1
+	for i := len(pts) - 2; i >= 0; i-- {
+		p := pts[i]
+		for len(hull) >= lower && !isLeftTurn2i(hull[len(hull)-2], hull[len(hull)-1], p) {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	// the first point is repeated at the end
+	return hull[:len(hull)-1]
+}
+
+// ConvexHull2iCollinear returns the convex hull of a set of points, like ConvexHull2i.
+// In contrast to ConvexHull2i, points that lie on the hull's edges are kept.
+// If all points are collinear, they are returned sorted from left to right (bottom to top).
+func ConvexHull2iCollinear(points []Vec2i) []Vec2i {
+	// The lower and upper chain are built separately, and only strict right turns are removed.
+	// Vertical runs of collinear points at both ends end up in exactly one of the chains.
+	pts := sortedUnique2i(points)
+	if len(pts) < 3 {
+		return pts
+	}
+	lower := make([]Vec2i, 0, len(pts))
+	for _, p := range pts {
+		for len(lower) >= 2 && isRightTurn2i(lower[len(lower)-2], lower[len(lower)-1], p) {
+			lower = lower[:len(lower)-1]
+		}
+		lower = append(lower, p)
+	}
+	upper := make([]Vec2i, 0, len(pts))
+	for i := len(pts) - 1; i >= 0; i-- {
+		p := pts[i]
+		for len(upper) >= 2 && isRightTurn2i(upper[len(upper)-2], upper[len(upper)-1], p) {
+			upper = upper[:len(upper)-1]
+		}
+		upper = append(upper, p)
+	}
+	if len(lower) == len(pts) && len(upper) == len(pts) { // all points are collinear
+		return pts
+	}
+	// both chains contain the leftmost and rightmost point
+	return append(lower[:len(lower)-1], upper[:len(upper)-1]...)
+}
+
+// isLeftTurn2f returns true if the points a, b, c form a counter-clockwise turn.
+func isLeftTurn2f(a, b, c Vec2f) bool {
+	return b.Sub(a).MagCross(c.Sub(a)) > 0
+}
+
+// isLeftTurn2i returns true if the points a, b, c form a counter-clockwise turn.
+func isLeftTurn2i(a, b, c Vec2i) bool {
+	return b.Sub(a).MagCross(c.Sub(a)) > 0
+}
+
+// isRightTurn2f returns true if the points a, b, c form a clockwise turn.
+// Nearly collinear points, within a relative tolerance of Epsilon, do not form a turn.
+func isRightTurn2f(a, b, c Vec2f) bool {
+	ab, ac := b.Sub(a), c.Sub(a)
+	cross := ab.MagCross(ac)
+	return cross < 0 && cross*cross > Epsilon*Epsilon*ab.SquareLength()*ac.SquareLength()
+}
+
+// isRightTurn2i returns true if the points a, b, c form a clockwise turn.
+func isRightTurn2i(a, b, c Vec2i) bool {
+	return b.Sub(a).MagCross(c.Sub(a)) < 0
+}
+
+// sortedUnique2f returns a sorted copy of the points (by x, then y), without duplicates.
+func sortedUnique2f(points []Vec2f) []Vec2f {
+	pts := make([]Vec2f, len(points))
+	copy(pts, points)
+	sort.Slice(pts, func(i, j int) bool {
+		if pts[i][0] != pts[j][0] {
+			return pts[i][0] < pts[j][0]
+		}
+		return pts[i][1] < pts[j][1]
+	})
+	if len(pts) == 0 {
+		return pts
+	}
+	res := pts[:1]
+	for _, p := range pts[1:] {
+		if p != res[len(res)-1] {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// sortedUnique2i returns a sorted copy of the points (by x, then y), without duplicates.
+func sortedUnique2i(points []Vec2i) []Vec2i {
+	pts := make([]Vec2i, len(points))
+	copy(pts, points)
+	sort.Slice(pts, func(i, j int) bool {
+		if pts[i][0] != pts[j][0] {
+			return pts[i][0] < pts[j][0]
+		}
+		return pts[i][1] < pts[j][1]
+	})
+	if len(pts) == 0 {
+		return pts
+	}
+	res := pts[:1]
+	for _, p := range pts[1:] {
+		if p != res[len(res)-1] {
+			res = append(res, p)
+		}
+	}
+	return res
+}
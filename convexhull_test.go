@@ -0,0 +1,85 @@
+package vmath
+
+import (
+	"math"
+	"testing"
+
+	"github.com/maja42/vmath/math32"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvexHull2f(t *testing.T) {
+	points := []Vec2f{
+		{2, 2}, {0, 0}, {4, 0}, {1, 3}, {4, 4}, {0, 4}, {2, 0}, {3, 1}, {0, 4}, {4, 2},
+	}
+	orig := append([]Vec2f{}, points...)
+
+	hull := ConvexHull2f(points)
+	assert.Equal(t, []Vec2f{{0, 0}, {4, 0}, {4, 4}, {0, 4}}, hull)
+	assert.Equal(t, orig, points) // input is not modified
+	assert.True(t, Polygon2f(hull).IsCounterClockwise())
+
+	hull = ConvexHull2fCollinear(points)
+	assert.Equal(t, []Vec2f{{0, 0}, {2, 0}, {4, 0}, {4, 2}, {4, 4}, {0, 4}}, hull)
+
+	// all points within the hull
+	poly := Polygon2f(ConvexHull2f(randomPoints2f(100, 7)))
+	assert.True(t, poly.IsConvex())
+	for _, p := range randomPoints2f(100, 7) {
+		assert.True(t, poly.ContainsPoint(p))
+	}
+}
+
+func TestConvexHull2fCollinear(t *testing.T) {
+	// points on the edges are not exactly representable
+	a, b, c := Vec2f{0, 0}, Vec2f{1, 3}, Vec2f{2.5, 0.3}
+	points := []Vec2f{a, b, c}
+	for i := 1; i < 7; i++ {
+		points = append(points, a.Lerp(b, float32(i)/7), b.Lerp(c, float32(i)/7), c.Lerp(a, float32(i)/7))
+	}
+	hull := ConvexHull2fCollinear(points)
+	assert.Len(t, hull, len(points))
+	assert.Equal(t, a, hull[0])
+	assert.True(t, Polygon2f(hull).IsCounterClockwise())
+
+	// all points on a circle are kept
+	points = make([]Vec2f, 360)
+	for i := range points {
+		angle := float32(i) * math.Pi / 180
+		points[i] = Vec2f{math32.Cos(angle), math32.Sin(angle)}.MulScalar(10)
+	}
+	assert.Len(t, ConvexHull2fCollinear(points), len(points))
+}
+
+func TestConvexHull2f_Degenerate(t *testing.T) {
+	assert.Empty(t, ConvexHull2f(nil))
+	assert.Equal(t, []Vec2f{{1, 2}}, ConvexHull2f([]Vec2f{{1, 2}, {1, 2}}))
+	assert.Equal(t, []Vec2f{{1, 2}, {3, 4}}, ConvexHull2f([]Vec2f{{3, 4}, {1, 2}}))
+
+	// collinear
+	points := []Vec2f{{2, 2}, {0, 0}, {3, 3}, {1, 1}, {1, 1}}
+	assert.Equal(t, []Vec2f{{0, 0}, {3, 3}}, ConvexHull2f(points))
+	assert.Equal(t, []Vec2f{{0, 0}, {1, 1}, {2, 2}, {3, 3}}, ConvexHull2fCollinear(points))
+}
+
+func TestConvexHull2i(t *testing.T) {
+	points := []Vec2i{
+		{2, 2}, {0, 0}, {4, 0}, {1, 3}, {4, 4}, {0, 4}, {2, 0}, {3, 1}, {0, 4}, {4, 2}, {0, 2}, {0, 1},
+	}
+	assert.Equal(t, []Vec2i{{0, 0}, {4, 0}, {4, 4}, {0, 4}}, ConvexHull2i(points))
+	assert.Equal(t, []Vec2i{{0, 0}, {2, 0}, {4, 0}, {4, 2}, {4, 4}, {0, 4}, {0, 2}, {0, 1}}, ConvexHull2iCollinear(points))
+
+	// triangle with many points on its diagonal edge
+	points = []Vec2i{{0, 0}, {9, 0}, {0, 9}, {3, 6}, {6, 3}, {1, 8}, {2, 2}}
+	assert.Equal(t, []Vec2i{{0, 0}, {9, 0}, {0, 9}}, ConvexHull2i(points))
+	assert.Equal(t, []Vec2i{{0, 0}, {9, 0}, {6, 3}, {3, 6}, {1, 8}, {0, 9}}, ConvexHull2iCollinear(points))
+}
+
+func TestConvexHull2i_Degenerate(t *testing.T) {
+	assert.Empty(t, ConvexHull2i(nil))
+	assert.Equal(t, []Vec2i{{1, 2}}, ConvexHull2i([]Vec2i{{1, 2}, {1, 2}}))
+
+	points := []Vec2i{{0, 5}, {0, 1}, {0, 3}}
+	assert.Equal(t, []Vec2i{{0, 1}, {0, 5}}, ConvexHull2i(points))
+	assert.Equal(t, []Vec2i{{0, 1}, {0, 3}, {0, 5}}, ConvexHull2iCollinear(points))
+}
@@ -0,0 +1,540 @@
+package vmath
+
+import (
+	"math"
+	"sort"
+)
+
+// Delaunay is a Delaunay triangulation of a 2D point set.
+type Delaunay struct {
+	// Points contains the triangulated points. Duplicate points are not part of any triangle.
+	Points []Vec2f
+	// Triangles contains three point indices per triangle, in counter-clockwise order.
+	// Edge j of triangle t goes from point Triangles[3t+j] to point Triangles[3t+(j+1)%3].
+	Triangles []uint32
+	// Neighbors contains the index of the adjacent triangle for each edge, or -1 for edges on the convex hull.
+	// The triangle adjacent to edge j of triangle t is Neighbors[3t+j].
+	Neighbors []int
+	// Constrained flags the triangle edges that are forced by constraints.
+	// Only set for constrained triangulations.
+	Constrained []bool
+}
+
+// ghostVertex is the point at infinity. Triangles with this vertex are adjacent to the convex hull.
+const ghostVertex = -1
+
+// DelaunayTriangulation computes the Delaunay triangulation of a set of points, using the Bowyer–Watson algorithm.
+// The triangulation maximizes the triangles' minimum angle; no point lies within the circumcircle of any triangle.
+// If all points are collinear, there are no triangles.
+func DelaunayTriangulation(points []Vec2f) Delaunay {
+	b := newDelaunayBuilder(points)
+	return b.result(nil)
+}
+
+// ConstrainedDelaunayTriangulation computes a constrained Delaunay triangulation of a set of points.
+// Each constraint is a pair of point indices that is forced to be connected by triangle edges,
+// for example the outline of a navigation mesh. Constraints passing through other points are split at these points.
+// The remaining edges are as close to a Delaunay triangulation as possible.
+// If constraints intersect each other, the later one takes precedence.
+func ConstrainedDelaunayTriangulation(points []Vec2f, constraints [][2]int) Delaunay {
+	b := newDelaunayBuilder(points)
+	constrained := make(map[[2]int]bool)
+	if len(b.tris) > 0 {
+		for _, c := range constraints {
+			b.insertConstraint(b.canonical[c[0]], b.canonical[c[1]], constrained)
+		}
+	}
+	return b.result(constrained)
+}
+
+// delaunayBuilder incrementally constructs a Delaunay triangulation.
+// The triangulation is closed by ghost triangles, which connect each convex hull edge with the ghost vertex.
+type delaunayBuilder struct {
+	points    []vec2d
+	canonical []int // index of the first occurrence of each point
+	tris      [][3]int
+	alive     []bool
+	edges     map[[2]int]int // directed edge -> triangle
+	vertexTri []int          // a triangle adjacent to each vertex
+	last      int            // recently created triangle, used as starting point for point location
+}
+
+func newDelaunayBuilder(points []Vec2f) *delaunayBuilder {
+	b := &delaunayBuilder{
+		points:    make([]vec2d, len(points)),
+		canonical: make([]int, len(points)),
+		edges:     make(map[[2]int]int),
+		vertexTri: make([]int, len(points)),
+	}
+	var unique []int
+	first := make(map[Vec2f]int, len(points))
+	for i, p := range points {
+		b.points[i] = vec2d{float64(p[0]), float64(p[1])}
+		b.vertexTri[i] = -1
+		if idx, ok := first[p]; ok {
+			b.canonical[i] = idx
+			continue
+		}
+		first[p] = i
+		b.canonical[i] = i
+		unique = append(unique, i)
+	}
+	b.sortSpatially(unique)
+
+	// find an initial, non-degenerate triangle
+	start := -1
+	for k := 2; k < len(unique) && start < 0; k++ {
+		if orient2d(b.points[unique[0]], b.points[unique[1]], b.points[unique[k]]) != 0 {
+			start = k
+		}
+	}
+	if start < 0 {
+		return b
+	}
+	i0, i1, i2 := unique[0], unique[1], unique[start]
+	if orient2d(b.points[i0], b.points[i1], b.points[i2]) < 0 {
+		i1, i2 = i2, i1
+	}
+	b.addTri(i0, i1, i2)
+	b.addTri(i1, i0, ghostVertex)
+	b.addTri(i2, i1, ghostVertex)
+	b.addTri(i0, i2, ghostVertex)
+
+	for k, idx := range unique[2:] {
+		if k+2 != start {
+			b.insert(idx)
+		}
+	}
+	return b
+}
+
+// sortSpatially sorts the points in horizontal bands, alternating between left-to-right and right-to-left.
+// Consecutive points are close to each other, which speeds up point location during insertion.
+func (b *delaunayBuilder) sortSpatially(indices []int) {
+	if len(indices) == 0 {
+		return
+	}
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, i := range indices {
+		minY = math.Min(minY, b.points[i][1])
+		maxY = math.Max(maxY, b.points[i][1])
+	}
+	bands := math.Sqrt(float64(len(indices))) / 2
+	band := func(i int) int {
+		if maxY == minY {
+			return 0
+		}
+		return int((b.points[i][1] - minY) / (maxY - minY) * bands)
+	}
+	sort.SliceStable(indices, func(x, y int) bool {
+		bx, by := band(indices[x]), band(indices[y])
+		if bx != by {
+			return bx < by
+		}
+		if bx%2 == 0 {
+			return b.points[indices[x]][0] < b.points[indices[y]][0]
+		}
+		return b.points[indices[x]][0] > b.points[indices[y]][0]
+	})
+}
+
+// addTri adds a counter-clockwise triangle and returns its index.
+func (b *delaunayBuilder) addTri(v0, v1, v2 int) int {
+	t := len(b.tris)
+	b.tris = append(b.tris, [3]int{v0, v1, v2})
+	b.alive = append(b.alive, true)
+	for j, v := range [3]int{v0, v1, v2} {
+		b.edges[[2]int{v, [3]int{v0, v1, v2}[(j+1)%3]}] = t
+		if v != ghostVertex {
+			b.vertexTri[v] = t
+		}
+	}
+	if v2 != ghostVertex && v1 != ghostVertex && v0 != ghostVertex {
+		b.last = t
+	}
+	return t
+}
+
+// removeTri removes a triangle.
+func (b *delaunayBuilder) removeTri(t int) {
+	b.alive[t] = false
+	tri := b.tris[t]
+	for j := range tri {
+		key := [2]int{tri[j], tri[(j+1)%3]}
+		if b.edges[key] == t {
+			delete(b.edges, key)
+		}
+	}
+}
+
+// neighbor returns the triangle adjacent to the edge u->v of another triangle.
+func (b *delaunayBuilder) neighbor(u, v int) int {
+	if t, ok := b.edges[[2]int{v, u}]; ok {
+		return t
+	}
+	return -1
+}
+
+// isGhost returns the position of the ghost vertex within the triangle, or -1 if it is a real triangle.
+func (b *delaunayBuilder) isGhost(t int) int {
+	for j, v := range b.tris[t] {
+		if v == ghostVertex {
+			return j
+		}
+	}
+	return -1
+}
+
+// conflicts returns true if the point lies within the triangle's circumcircle.
+// The circumcircle of ghost triangles is the open half-plane outside of their hull edge.
+func (b *delaunayBuilder) conflicts(t int, p vec2d) bool {
+	tri := b.tris[t]
+	if g := b.isGhost(t); g >= 0 {
+		u := b.points[tri[(g+1)%3]]
+		v := b.points[tri[(g+2)%3]]
+		o := orient2d(u, v, p)
+		if o != 0 {
+			return o > 0
+		}
+		// collinear with the hull edge: conflicts if it lies in between
+		d := v.sub(u)
+		proj := p.sub(u).dot(d)
+		return proj > 0 && proj < d.dot(d)
+	}
+	return inCircle(b.points[tri[0]], b.points[tri[1]], b.points[tri[2]], p) > 0
+}
+
+// locate returns a triangle whose circumcircle contains the point.
+func (b *delaunayBuilder) locate(p vec2d) int {
+	// visibility walk, starting at the recently created triangle
+	t := b.last
+walk:
+	for steps := 0; steps < len(b.tris); steps++ {
+		if !b.alive[t] {
+			break
+		}
+		if b.isGhost(t) >= 0 {
+			if b.conflicts(t, p) {
+				return t
+			}
+			break
+		}
+		tri := b.tris[t]
+		for j := range tri {
+			u, v := tri[j], tri[(j+1)%3]
+			if orient2d(b.points[u], b.points[v], p) < 0 {
+				t = b.neighbor(u, v)
+				continue walk
+			}
+		}
+		if b.conflicts(t, p) {
+			return t
+		}
+		break
+	}
+	// fallback for degenerate cases
+	for t := range b.tris {
+		if b.alive[t] && b.conflicts(t, p) {
+			return t
+		}
+	}
+	return -1
+}
+
+// insert adds a new point to the triangulation.
+func (b *delaunayBuilder) insert(idx int) {
+	// Source: "Computing the n-dimensional Delaunay tessellation with application to Voronoi polytopes" by A. Bowyer
+	// and "Computing Dirichlet tessellations" by D. F. Watson, 1981.
+	p := b.points[idx]
+	start := b.locate(p)
+	if start < 0 {
+		return
+	}
+
+	// find all triangles whose circumcircle contains the point (the cavity)
+	cavity := map[int]bool{start: true}
+	stack := []int{start}
+	var boundary [][2]int
+	for len(stack) > 0 {
+		t := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		tri := b.tris[t]
+		for j := range tri {
+			u, v := tri[j], tri[(j+1)%3]
+			n := b.neighbor(u, v)
+			if cavity[n] {
+				continue
+			}
+			if b.conflicts(n, p) {
+				cavity[n] = true
+				stack = append(stack, n)
+			} else {
+				boundary = append(boundary, [2]int{u, v})
+			}
+		}
+	}
+	for t := range cavity {
+		b.removeTri(t)
+	}
+	// connect the cavity's boundary with the new point
+	for _, e := range boundary {
+		if e[0] == ghostVertex {
+			b.addTri(idx, ghostVertex, e[1])
+		} else if e[1] == ghostVertex {
+			b.addTri(e[1], idx, e[0])
+		} else {
+			b.addTri(e[0], e[1], idx)
+		}
+	}
+}
+
+// insertConstraint forces an edge between the vertices a and c.
+func (b *delaunayBuilder) insertConstraint(a, c int, constrained map[[2]int]bool) {
+	// Source: "An improved incremental algorithm for constructing restricted Delaunay triangulations"
+	// by M. V. Anglada, 1997.
+	for a != c {
+		if _, ok := b.edges[[2]int{a, c}]; ok {
+			constrained[undirectedEdge(a, c)] = true
+			return
+		}
+		next, upper, lower, crossed := b.cavityAlong(a, c)
+		for _, t := range crossed {
+			b.removeTri(t)
+		}
+		if len(crossed) > 0 {
+			b.fillCavity(a, next, upper)
+			reversed := make([]int, len(lower))
+			for i, v := range lower {
+				reversed[len(lower)-1-i] = v
+			}
+			b.fillCavity(next, a, reversed)
+		}
+		constrained[undirectedEdge(a, next)] = true
+		a = next
+	}
+}
+
+// cavityAlong finds the triangles crossed by the segment from a to c.
+// If the segment passes through another vertex, the search stops there. This vertex is returned as next.
+// upper and lower contain the vertices of the crossed triangles to the left and right of the segment, in order.
+func (b *delaunayBuilder) cavityAlong(a, c int) (next int, upper, lower []int, crossed []int) {
+	pa, pc := b.points[a], b.points[c]
+	dir := pc.sub(pa)
+	between := func(v int) bool { // v lies on the segment, between a and c
+		if v == ghostVertex || orient2d(pa, pc, b.points[v]) != 0 {
+			return false
+		}
+		d := b.points[v].sub(pa).dot(dir)
+		return d > 0 && d < dir.dot(dir)
+	}
+
+	// rotate around a to find the first crossed triangle
+	t := b.vertexTri[a]
+	if t < 0 || !b.alive[t] {
+		for t = range b.tris {
+			if b.alive[t] && (b.tris[t][0] == a || b.tris[t][1] == a || b.tris[t][2] == a) {
+				break
+			}
+		}
+	}
+	var right, left int
+	for range b.tris {
+		tri := b.tris[t]
+		k := 0
+		for tri[k] != a {
+			k++
+		}
+		v1, v2 := tri[(k+1)%3], tri[(k+2)%3]
+		if between(v1) {
+			return v1, nil, nil, nil
+		}
+		if v1 != ghostVertex && v2 != ghostVertex &&
+			orient2d(pa, b.points[v1], pc) > 0 && orient2d(pa, b.points[v2], pc) < 0 {
+			right, left = v1, v2
+			break
+		}
+		t = b.neighbor(v2, a)
+	}
+
+	// walk through the triangles crossed by the segment
+	crossed = []int{t}
+	lower, upper = []int{right}, []int{left}
+	for {
+		t = b.neighbor(right, left)
+		crossed = append(crossed, t)
+		tri := b.tris[t]
+		k := 0
+		for tri[k] != left {
+			k++
+		}
+		w := tri[(k+2)%3]
+		if w == c || between(w) {
+			return w, upper, lower, crossed
+		}
+		if orient2d(pa, pc, b.points[w]) > 0 {
+			upper = append(upper, w)
+			left = w
+		} else {
+			lower = append(lower, w)
+			right = w
+		}
+	}
+}
+
+// fillCavity triangulates the polygon formed by the edge a->b and the vertices on its left side.
+// The vertices are ordered from a to b.
+func (b *delaunayBuilder) fillCavity(a, c int, verts []int) {
+	if len(verts) == 0 {
+		return
+	}
+	// choose the vertex whose circumcircle with a and c contains no other vertex
+	ci := 0
+	for i := 1; i < len(verts); i++ {
+		if inCircle(b.points[a], b.points[c], b.points[verts[ci]], b.points[verts[i]]) > 0 {
+			ci = i
+		}
+	}
+	v := verts[ci]
+	b.fillCavity(a, v, verts[:ci])
+	b.fillCavity(v, c, verts[ci+1:])
+	b.addTri(a, c, v)
+}
+
+// result returns the triangulation without ghost triangles.
+func (b *delaunayBuilder) result(constrained map[[2]int]bool) Delaunay {
+	res := Delaunay{
+		Points: make([]Vec2f, len(b.points)),
+	}
+	for i, p := range b.points {
+		res.Points[i] = Vec2f{float32(p[0]), float32(p[1])}
+	}
+
+	index := make([]int, len(b.tris))
+	count := 0
+	for t := range b.tris {
+		index[t] = -1
+		if b.alive[t] && b.isGhost(t) < 0 {
+			index[t] = count
+			count++
+		}
+	}
+	res.Triangles = make([]uint32, 0, 3*count)
+	res.Neighbors = make([]int, 0, 3*count)
+	if constrained != nil {
+		res.Constrained = make([]bool, 0, 3*count)
+	}
+	for t, tri := range b.tris {
+		if index[t] < 0 {
+			continue
+		}
+		for j, v := range tri {
+			u := tri[(j+1)%3]
+			res.Triangles = append(res.Triangles, uint32(v))
+			n := b.neighbor(v, u)
+			if n >= 0 {
+				n = index[n]
+			}
+			res.Neighbors = append(res.Neighbors, n)
+			if constrained != nil {
+				res.Constrained = append(res.Constrained, constrained[undirectedEdge(v, u)])
+			}
+		}
+	}
+	return res
+}
+
+// Voronoi returns the Voronoi diagram of the triangulated points, clipped to the given bounds.
+// The diagram contains a cell for each point, in counter-clockwise order.
+// Cells are nil for duplicate points and points outside of the bounds.
+// The cells are only valid for unconstrained triangulations.
+func (d Delaunay) Voronoi(bounds Rectf) []Polygon2f {
+	// The Voronoi cell of a point is the intersection of the half planes towards all its Delaunay neighbors.
+	neighbors := make([][]uint32, len(d.Points))
+	for i := 0; i < len(d.Triangles); i += 3 {
+		for j := 0; j < 3; j++ {
+			if d.Neighbors[i+j] > i/3 {
+				continue // inner edges are visited twice
+			}
+			a, b := d.Triangles[i+j], d.Triangles[i+(j+1)%3]
+			neighbors[a] = append(neighbors[a], b)
+			neighbors[b] = append(neighbors[b], a)
+		}
+	}
+	if len(d.Triangles) == 0 { // degenerate: all points are collinear
+		first := make(map[Vec2f]bool)
+		var unique []uint32
+		for i, p := range d.Points {
+			if !first[p] {
+				first[p] = true
+				unique = append(unique, uint32(i))
+			}
+		}
+		for _, i := range unique {
+			for _, j := range unique {
+				if i != j {
+					neighbors[i] = append(neighbors[i], j)
+				}
+			}
+		}
+		if len(unique) == 1 {
+			neighbors[unique[0]] = []uint32{}
+		}
+	}
+
+	cells := make([]Polygon2f, len(d.Points))
+	for i, p := range d.Points {
+		if neighbors[i] == nil || !bounds.ContainsPoint(p) {
+			continue
+		}
+		cell := Polygon2f{bounds.Min, {bounds.Max[0], bounds.Min[1]}, bounds.Max, {bounds.Min[0], bounds.Max[1]}}
+		for _, n := range neighbors[i] {
+			q := d.Points[n]
+			cell = clipHalfPlane(cell, p.Lerp(q, 0.5), q.Sub(p))
+		}
+		cells[i] = cell
+	}
+	return cells
+}
+
+// clipHalfPlane clips a polygon, keeping the half plane on the opposite side of the normal.
+func clipHalfPlane(poly Polygon2f, point, normal Vec2f) Polygon2f {
+	if len(poly) == 0 {
+		return poly
+	}
+	res := make(Polygon2f, 0, len(poly)+1)
+	prev := poly[len(poly)-1]
+	prevDist := prev.Sub(point).Dot(normal)
+	for _, cur := range poly {
+		curDist := cur.Sub(point).Dot(normal)
+		if (curDist <= 0) != (prevDist <= 0) {
+			res = append(res, prev.Lerp(cur, prevDist/(prevDist-curDist)))
+		}
+		if curDist <= 0 {
+			res = append(res, cur)
+		}
+		prev, prevDist = cur, curDist
+	}
+	return res
+}
+
+// orient2d returns a positive value if a, b, c are in counter-clockwise order,
+// a negative value if they are in clockwise order and zero if they are collinear.
+func orient2d(a, b, c vec2d) float64 {
+	return b.sub(a).cross(c.sub(a))
+}
+
+// inCircle returns a positive value if d lies within the circumcircle of the counter-clockwise triangle a, b, c.
+// Returns zero if d lies on the circle, and a negative value otherwise.
+func inCircle(a, b, c, d vec2d) float64 {
+	// Source: "Adaptive Precision Floating-Point Arithmetic and Fast Robust Geometric Predicates" by J. Shewchuk, 1997.
+	ad, bd, cd := a.sub(d), b.sub(d), c.sub(d)
+	return ad.dot(ad)*bd.cross(cd) + bd.dot(bd)*cd.cross(ad) + cd.dot(cd)*ad.cross(bd)
+}
+
+// undirectedEdge returns the key of an undirected edge between the vertices a and b.
+func undirectedEdge(a, b int) [2]int {
+	if a > b {
+		return [2]int{b, a}
+	}
+	return [2]int{a, b}
+}
@@ -0,0 +1,223 @@
+package vmath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// checkDelaunay verifies that the triangles are counter-clockwise, cover the convex hull and that all neighbors match.
+// If strict is true, no point may lie within the circumcircle of any triangle.
+func checkDelaunay(t *testing.T, d Delaunay, strict bool) {
+	t.Helper()
+	assert.Equal(t, 0, len(d.Triangles)%3)
+	assert.Len(t, d.Neighbors, len(d.Triangles))
+
+	var area float32
+	for i := 0; i < len(d.Triangles); i += 3 {
+		a, b, c := d.Points[d.Triangles[i]], d.Points[d.Triangles[i+1]], d.Points[d.Triangles[i+2]]
+		tri := Polygon2f{a, b, c}
+		assert.True(t, tri.SignedArea() > 0, "triangle %d is not counter-clockwise", i/3)
+		area += tri.Area()
+
+		for j := 0; j < 3; j++ {
+			n := d.Neighbors[i+j]
+			if n < 0 {
+				continue
+			}
+			// the neighbor contains the reversed edge
+			u, v := d.Triangles[i+j], d.Triangles[i+(j+1)%3]
+			found := false
+			for k := 0; k < 3; k++ {
+				if d.Triangles[3*n+k] == v && d.Triangles[3*n+(k+1)%3] == u {
+					found = d.Neighbors[3*n+k] == i/3
+				}
+			}
+			assert.True(t, found, "triangle %d has an invalid neighbor", i/3)
+		}
+
+		if strict {
+			circle := circumcircle(a, b, c)
+			for _, p := range d.Points {
+				assert.True(t, p.Distance(circle.Center) >= circle.Radius*(1-1e-4), "point %v lies within circumcircle", p)
+			}
+		}
+	}
+	hull := Polygon2f(ConvexHull2f(d.Points))
+	assert.InEpsilon(t, hull.Area(), area, 1e-4)
+}
+
+// circumcircle returns the circle passing through three points.
+func circumcircle(a, b, c Vec2f) Circle {
+	ab, ac := b.Sub(a), c.Sub(a)
+	d := 2 * ab.MagCross(ac)
+	center := Vec2f{
+		(ac[1]*ab.SquareLength() - ab[1]*ac.SquareLength()) / d,
+		(ab[0]*ac.SquareLength() - ac[0]*ab.SquareLength()) / d,
+	}
+	return Circle{center.Add(a), center.Length()}
+}
+
+func TestDelaunayTriangulation(t *testing.T) {
+	d := DelaunayTriangulation([]Vec2f{{0, 0}, {4, 0}, {2, 1}, {2, -1}})
+	assert.Len(t, d.Triangles, 2*3)
+	checkDelaunay(t, d, true)
+	// the short diagonal is used
+	assert.Contains(t, d.Triangles, uint32(2))
+	assert.Contains(t, d.Triangles, uint32(3))
+	assert.Contains(t, d.Neighbors, 0)
+	assert.Contains(t, d.Neighbors, 1)
+	assert.Nil(t, d.Constrained)
+
+	points := randomPoints2f(500, 3)
+	d = DelaunayTriangulation(points)
+	hull := ConvexHull2f(points)
+	assert.Len(t, d.Triangles, (2*len(points)-2-len(hull))*3)
+	checkDelaunay(t, d, true)
+}
+
+func TestDelaunayTriangulation_Degenerate(t *testing.T) {
+	// co-circular points
+	var grid []Vec2f
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 5; x++ {
+			grid = append(grid, Vec2f{float32(x), float32(y)})
+		}
+	}
+	d := DelaunayTriangulation(grid)
+	assert.Len(t, d.Triangles, 32*3)
+	checkDelaunay(t, d, true)
+
+	// collinear and duplicate points
+	d = DelaunayTriangulation([]Vec2f{{0, 0}, {1, 1}, {0, 0}, {2, 2}, {1, 1}, {2, 0}, {1, 1}})
+	assert.Len(t, d.Triangles, 2*3)
+	checkDelaunay(t, d, true)
+	for _, idx := range d.Triangles {
+		assert.NotContains(t, []uint32{2, 4, 6}, idx)
+	}
+
+	assert.Empty(t, DelaunayTriangulation([]Vec2f{{0, 0}, {1, 1}, {2, 2}, {3, 3}}).Triangles)
+	assert.Empty(t, DelaunayTriangulation([]Vec2f{{0, 0}, {0, 0}, {0, 0}}).Triangles)
+	assert.Empty(t, DelaunayTriangulation(nil).Triangles)
+}
+
+func TestConstrainedDelaunayTriangulation(t *testing.T) {
+	points := []Vec2f{{0, 0}, {4, 0}, {2, 1}, {2, -1}}
+	d := ConstrainedDelaunayTriangulation(points, [][2]int{{0, 1}})
+	assert.Len(t, d.Triangles, 2*3)
+	checkDelaunay(t, d, false)
+	assert.True(t, hasEdge(d, 0, 1))
+	assert.False(t, hasEdge(d, 2, 3))
+	constrained := 0
+	for _, c := range d.Constrained {
+		if c {
+			constrained++
+		}
+	}
+	assert.Equal(t, 2, constrained) // both half edges
+
+	// constraints through other points are split
+	points = []Vec2f{{0, 0}, {1, 0.1}, {2, 0}, {3, -0.1}, {4, 0}, {2, 0.5}, {2, -0.5}, {1, -1}, {3, 1}}
+	d = ConstrainedDelaunayTriangulation(points, [][2]int{{0, 4}, {5, 6}})
+	checkDelaunay(t, d, false)
+	assert.True(t, hasEdge(d, 0, 1))
+	assert.True(t, hasEdge(d, 1, 2))
+	assert.True(t, hasEdge(d, 2, 3))
+	assert.False(t, hasEdge(d, 5, 6)) // overridden by the second constraint
+	assert.True(t, hasEdge(d, 5, 2))
+	assert.True(t, hasEdge(d, 2, 6))
+}
+
+func TestConstrainedDelaunayTriangulation_Random(t *testing.T) {
+	// a star-shaped outline with random points inside
+	var outline []Vec2f
+	for i := 0; i < 24; i++ {
+		r := float32(10)
+		if i%2 == 1 {
+			r = 4
+		}
+		outline = append(outline, PolarToCartesian2D(r, float32(i)*2*pi/24))
+	}
+	points := append([]Vec2f{}, outline...)
+	for _, p := range randomPoints2f(300, 5) {
+		if Polygon2f(outline).ContainsPoint(p) {
+			points = append(points, p)
+		}
+	}
+	var constraints [][2]int
+	for i := range outline {
+		constraints = append(constraints, [2]int{i, (i + 1) % len(outline)})
+	}
+
+	d := ConstrainedDelaunayTriangulation(points, constraints)
+	checkDelaunay(t, d, false)
+	for _, c := range constraints {
+		assert.True(t, hasEdge(d, c[0], c[1]), "missing constraint %v", c)
+	}
+
+	// triangles within the outline
+	var area float32
+	for i := 0; i < len(d.Triangles); i += 3 {
+		tri := Polygon2f{d.Points[d.Triangles[i]], d.Points[d.Triangles[i+1]], d.Points[d.Triangles[i+2]]}
+		if Polygon2f(outline).ContainsPoint(tri.Centroid()) {
+			area += tri.Area()
+		}
+	}
+	assert.InEpsilon(t, Polygon2f(outline).Area(), area, 1e-4)
+}
+
+// hasEdge returns true if the triangulation contains an edge between the points a and b.
+func hasEdge(d Delaunay, a, b int) bool {
+	for i := 0; i < len(d.Triangles); i += 3 {
+		for j := 0; j < 3; j++ {
+			u, v := int(d.Triangles[i+j]), int(d.Triangles[i+(j+1)%3])
+			if (u == a && v == b) || (u == b && v == a) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestDelaunay_Voronoi(t *testing.T) {
+	bounds := Rectf{Vec2f{0, 0}, Vec2f{4, 4}}
+	points := []Vec2f{{1, 1}, {3, 1}, {3, 3}, {1, 3}}
+	cells := DelaunayTriangulation(points).Voronoi(bounds)
+	assert.Len(t, cells, 4)
+	for i, cell := range cells {
+		assert.True(t, cell.IsCounterClockwise())
+		AssertFloat(t, 4, cell.Area())
+		AssertVec2f(t, points[i], cell.Centroid())
+	}
+
+	// every position lies within the cell of its nearest point
+	points = randomPoints2f(100, 9)
+	bounds = Rectf{Vec2f{-10, -10}, Vec2f{10, 10}}
+	cells = DelaunayTriangulation(points).Voronoi(bounds)
+	var area float32
+	for _, cell := range cells {
+		area += cell.Area()
+	}
+	assert.InEpsilon(t, 400, area, 1e-4)
+	for _, p := range randomPoints2f(100, 10) {
+		nearest := 0
+		for i, q := range points {
+			if q.SquareDistance(p) < points[nearest].SquareDistance(p) {
+				nearest = i
+			}
+		}
+		assert.True(t, cells[nearest].ContainsPoint(p))
+	}
+}
+
+func TestDelaunay_Voronoi_Degenerate(t *testing.T) {
+	bounds := Rectf{Vec2f{0, 0}, Vec2f{4, 4}}
+	cells := DelaunayTriangulation([]Vec2f{{1, 2}, {3, 2}, {1, 2}, {5, 2}}).Voronoi(bounds)
+	assert.Equal(t, Polygon2f{{0, 0}, {2, 0}, {2, 4}, {0, 4}}, cells[0])
+	assert.Equal(t, Polygon2f{{2, 0}, {4, 0}, {4, 4}, {2, 4}}, cells[1])
+	assert.Nil(t, cells[2]) // duplicate
+	assert.Nil(t, cells[3]) // outside
+
+	cells = DelaunayTriangulation([]Vec2f{{1, 2}}).Voronoi(bounds)
+	assert.Equal(t, Polygon2f{{0, 0}, {4, 0}, {4, 4}, {0, 4}}, cells[0])
+}
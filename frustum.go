@@ -0,0 +1,129 @@
+package vmath
+
+// Containment describes the spatial relationship between a volume and a bounding volume.
+type Containment int
+
+const (
+	// Outside means that the volume is completely outside the bounding volume.
+	Outside Containment = iota
+	// Intersecting means that the volume is partially inside the bounding volume.
+	Intersecting
+	// Inside means that the volume is completely inside the bounding volume.
+	Inside
+)
+
+func (c Containment) String() string {
+	switch c {
+	case Outside:
+		return "Outside"
+	case Intersecting:
+		return "Intersecting"
+	case Inside:
+		return "Inside"
+	}
+	return "Containment(invalid)"
+}
+
+// ViewFrustum represents the visible volume of a camera, bounded by six planes.
+// The plane normals are normalized and point towards the inside of the frustum.
+// Planes are stored in the order: left, right, bottom, top, near, far.
+type ViewFrustum struct {
+	Planes [6]Plane
+}
+
+// Indices of the individual planes within ViewFrustum.Planes.
+const (
+	FrustumLeft = iota
+	FrustumRight
+	FrustumBottom
+	FrustumTop
+	FrustumNear
+	FrustumFar
+)
+
+// ViewFrustumFromMatrix extracts the frustum planes from a projection or view-projection matrix.
+// If a projection matrix is given, the planes are in view space.
+// If a combined view-projection matrix is given, the planes are in world space.
+// The matrix is expected to map into OpenGL's clip space, where -w <= x, y, z <= w.
+func ViewFrustumFromMatrix(m Mat4f) ViewFrustum {
+	// Source: "Fast Extraction of Viewing Frustum Planes from the World-View-Projection Matrix"
+	//          by G. Gribb and K. Hartmann, 2001.
+	row0, row1, row2, row3 := m.Rows()
+	return ViewFrustum{
+		Planes: [6]Plane{
+			PlaneFromVec4f(row3.Add(row0)).Normalize(),
+			PlaneFromVec4f(row3.Sub(row0)).Normalize(),
+			PlaneFromVec4f(row3.Add(row1)).Normalize(),
+			PlaneFromVec4f(row3.Sub(row1)).Normalize(),
+			PlaneFromVec4f(row3.Add(row2)).Normalize(),
+			PlaneFromVec4f(row3.Sub(row2)).Normalize(),
+		},
+	}
+}
+
+// ContainsPoint checks if a given point resides within the frustum.
+// Points on the frustum's boundary are also considered to be contained.
+func (f ViewFrustum) ContainsPoint(point Vec3f) bool {
+	for _, p := range f.Planes {
+		if p.SignedDistance(point) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IntersectsSphere checks if a sphere is inside, outside or intersecting the frustum.
+// Spheres that are close to a frustum corner might be reported as intersecting, even if they are outside.
+func (f ViewFrustum) IntersectsSphere(center Vec3f, radius float32) Containment {
+	res := Inside
+	for _, p := range f.Planes {
+		dist := p.SignedDistance(center)
+		if dist < -radius {
+			return Outside
+		}
+		if dist < radius {
+			res = Intersecting
+		}
+	}
+	return res
+}
+
+// IntersectsBox checks if an axis-aligned box is inside, outside or intersecting the frustum.
+// Boxes that are close to a frustum corner might be reported as intersecting, even if they are outside.
+func (f ViewFrustum) IntersectsBox(box Box3f) Containment {
+	// Source: "Optimized View Frustum Culling Algorithms for Bounding Boxes" by U. Assarsson and T. Möller, 2000.
+	// For each plane, only the box corner that is farthest along the normal (positive vertex)
+	// and the one that is farthest against it (negative vertex) need to be checked.
+	res := Inside
+	for _, p := range f.Planes {
+		pos, neg := box.Max, box.Min
+		for i, n := range p.Normal {
+			if n < 0 {
+				pos[i], neg[i] = box.Min[i], box.Max[i]
+			}
+		}
+		if p.SignedDistance(pos) < 0 {
+			return Outside
+		}
+		if p.SignedDistance(neg) < 0 {
+			res = Intersecting
+		}
+	}
+	return res
+}
+
+// Corners returns the eight corner positions of the frustum.
+// The first four corners are on the near plane, the last four on the far plane.
+// Both are ordered counter-clockwise, starting with the bottom left corner (when looking into the frustum).
+// If the frustum is degenerated (eg. due to an infinite far plane), the affected corners are zero.
+func (f ViewFrustum) Corners() [8]Vec3f {
+	var corners [8]Vec3f
+	for i, depth := range [2]int{FrustumNear, FrustumFar} {
+		p := f.Planes[depth]
+		corners[i*4+0], _ = IntersectPlanes(p, f.Planes[FrustumBottom], f.Planes[FrustumLeft])
+		corners[i*4+1], _ = IntersectPlanes(p, f.Planes[FrustumBottom], f.Planes[FrustumRight])
+		corners[i*4+2], _ = IntersectPlanes(p, f.Planes[FrustumTop], f.Planes[FrustumRight])
+		corners[i*4+3], _ = IntersectPlanes(p, f.Planes[FrustumTop], f.Planes[FrustumLeft])
+	}
+	return corners
+}
@@ -0,0 +1,98 @@
+package vmath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewFrustumFromMatrix(t *testing.T) {
+	f := ViewFrustumFromMatrix(Ortho(-2, 2, -1, 1, 1, 10))
+
+	// normals point inwards
+	AssertVec3f(t, Vec3f{1, 0, 0}, f.Planes[FrustumLeft].Normal)
+	AssertVec3f(t, Vec3f{-1, 0, 0}, f.Planes[FrustumRight].Normal)
+	AssertVec3f(t, Vec3f{0, 1, 0}, f.Planes[FrustumBottom].Normal)
+	AssertVec3f(t, Vec3f{0, -1, 0}, f.Planes[FrustumTop].Normal)
+	AssertVec3f(t, Vec3f{0, 0, -1}, f.Planes[FrustumNear].Normal)
+	AssertVec3f(t, Vec3f{0, 0, 1}, f.Planes[FrustumFar].Normal)
+
+	AssertFloat(t, 2, f.Planes[FrustumLeft].D)
+	AssertFloat(t, 2, f.Planes[FrustumRight].D)
+	AssertFloat(t, 1, f.Planes[FrustumBottom].D)
+	AssertFloat(t, 1, f.Planes[FrustumTop].D)
+	AssertFloat(t, -1, f.Planes[FrustumNear].D)
+	AssertFloat(t, 10, f.Planes[FrustumFar].D)
+}
+
+func TestViewFrustum_ContainsPoint(t *testing.T) {
+	f := ViewFrustumFromMatrix(Frustum(-1, 1, -1, 1, 1, 10))
+
+	assert.True(t, f.ContainsPoint(Vec3f{0, 0, -5}))
+	assert.True(t, f.ContainsPoint(Vec3f{4, -4, -5}))
+	assert.True(t, f.ContainsPoint(Vec3f{0, 0, -1.5}))
+
+	assert.False(t, f.ContainsPoint(Vec3f{0, 0, 5}))     // behind the camera
+	assert.False(t, f.ContainsPoint(Vec3f{0, 0, -0.5}))  // before the near plane
+	assert.False(t, f.ContainsPoint(Vec3f{0, 0, -10.5})) // behind the far plane
+	assert.False(t, f.ContainsPoint(Vec3f{6, 0, -5}))
+	assert.False(t, f.ContainsPoint(Vec3f{0, -6, -5}))
+}
+
+func TestViewFrustum_WorldSpace(t *testing.T) {
+	// camera at (10, 0, 0), looking towards the origin
+	view := LookAt(Vec3f{10, 0, 0}, Vec3f{0, 0, 0}, Vec3f{0, 1, 0})
+	proj := Perspective(ToRadians(90), 1, 1, 100)
+	f := ViewFrustumFromMatrix(proj.Mul(view))
+
+	assert.True(t, f.ContainsPoint(Vec3f{0, 0, 0}))
+	assert.True(t, f.ContainsPoint(Vec3f{0, 5, 5}))
+	assert.False(t, f.ContainsPoint(Vec3f{20, 0, 0}))
+	assert.False(t, f.ContainsPoint(Vec3f{0, 0, 15}))
+}
+
+func TestViewFrustum_IntersectsSphere(t *testing.T) {
+	f := ViewFrustumFromMatrix(Frustum(-1, 1, -1, 1, 1, 10))
+
+	assert.Equal(t, Inside, f.IntersectsSphere(Vec3f{0, 0, -5}, 1))
+	assert.Equal(t, Intersecting, f.IntersectsSphere(Vec3f{0, 0, -5}, 10))
+	assert.Equal(t, Intersecting, f.IntersectsSphere(Vec3f{0, 0, -11}, 2))
+	assert.Equal(t, Intersecting, f.IntersectsSphere(Vec3f{0, 0, 0}, 1.5))
+	assert.Equal(t, Outside, f.IntersectsSphere(Vec3f{0, 0, -12}, 1))
+	assert.Equal(t, Outside, f.IntersectsSphere(Vec3f{20, 0, -5}, 1))
+	assert.Equal(t, Outside, f.IntersectsSphere(Vec3f{0, 0, 5}, 1))
+}
+
+func TestViewFrustum_IntersectsBox(t *testing.T) {
+	f := ViewFrustumFromMatrix(Frustum(-1, 1, -1, 1, 1, 10))
+
+	assert.Equal(t, Inside, f.IntersectsBox(Box3f{Vec3f{-1, -1, -6}, Vec3f{1, 1, -4}}))
+	assert.Equal(t, Intersecting, f.IntersectsBox(Box3f{Vec3f{-1, -1, -12}, Vec3f{1, 1, -8}}))
+	assert.Equal(t, Intersecting, f.IntersectsBox(Box3f{Vec3f{-100, -100, -100}, Vec3f{100, 100, 100}}))
+	assert.Equal(t, Intersecting, f.IntersectsBox(Box3f{Vec3f{4, -1, -6}, Vec3f{8, 1, -4}}))
+	assert.Equal(t, Outside, f.IntersectsBox(Box3f{Vec3f{7, -1, -6}, Vec3f{8, 1, -4}}))
+	assert.Equal(t, Outside, f.IntersectsBox(Box3f{Vec3f{-1, -1, 1}, Vec3f{1, 1, 4}}))
+}
+
+func TestViewFrustum_Corners(t *testing.T) {
+	f := ViewFrustumFromMatrix(Frustum(-1, 2, -1, 1, 1, 10))
+	expected := [8]Vec3f{
+		{-1, -1, -1}, {2, -1, -1}, {2, 1, -1}, {-1, 1, -1},
+		{-10, -10, -10}, {20, -10, -10}, {20, 10, -10}, {-10, 10, -10},
+	}
+	corners := f.Corners()
+	for i := range expected {
+		AssertVec3f(t, expected[i], corners[i])
+	}
+
+	f = ViewFrustumFromMatrix(Ortho(-2, 2, -1, 1, 1, 10))
+	corners = f.Corners()
+	AssertVec3f(t, Vec3f{-2, -1, -1}, corners[0])
+	AssertVec3f(t, Vec3f{2, 1, -10}, corners[6])
+}
+
+func TestContainment_String(t *testing.T) {
+	assert.Equal(t, "Outside", Outside.String())
+	assert.Equal(t, "Intersecting", Intersecting.String())
+	assert.Equal(t, "Inside", Inside.String())
+}
@@ -0,0 +1,565 @@
+package vmath
+
+import (
+	"math"
+)
+
+// gjkMaxIterations limits the iterations of GJK and EPA, which might not converge due to rounding errors.
+const gjkMaxIterations = 128
+
+// gjkTolerance is the relative tolerance at which GJK and EPA are considered converged.
+const gjkTolerance = 1e-6
+
+// Penetration3f describes how deep two overlapping shapes penetrate each other.
+type Penetration3f struct {
+	// Normal is the normalized collision normal, pointing from shape A towards shape B.
+	// Moving B by Normal*Depth separates the shapes.
+	Normal Vec3f
+	// Depth is the penetration depth.
+	Depth float32
+	// PointA is the point of shape A that lies deepest within shape B, and vice versa.
+	PointA, PointB Vec3f
+}
+
+// Penetration2f describes how deep two overlapping shapes penetrate each other.
+type Penetration2f struct {
+	// Normal is the normalized collision normal, pointing from shape A towards shape B.
+	// Moving B by Normal*Depth separates the shapes.
+	Normal Vec2f
+	// Depth is the penetration depth.
+	Depth float32
+	// PointA is the point of shape A that lies deepest within shape B, and vice versa.
+	PointA, PointB Vec2f
+}
+
+// GJKIntersects3f checks if two convex shapes overlap, using the Gilbert–Johnson–Keerthi algorithm.
+// Shapes that touch each other might or might not be considered overlapping, due to rounding errors.
+func GJKIntersects3f(a, b ConvexShape3f) bool {
+	_, overlap := gjk(a, b, true)
+	return overlap
+}
+
+// GJKDistance3f returns the distance between two convex shapes and their closest points,
+// using the Gilbert–Johnson–Keerthi algorithm.
+// If the shapes overlap, the distance is zero and the closest points are undefined; see EPAPenetration3f.
+func GJKDistance3f(a, b ConvexShape3f) (dist float32, closestA, closestB Vec3f) {
+	s, overlap := gjk(a, b, false)
+	closestA, closestB = s.witnesses()
+	if overlap {
+		return 0, closestA, closestB
+	}
+	v := s.closest()
+	return float32(math.Sqrt(v.dot(v))), closestA, closestB
+}
+
+// EPAPenetration3f calculates the penetration depth and collision normal of two overlapping convex shapes,
+// using the Gilbert–Johnson–Keerthi and the Expanding Polytope Algorithm.
+// Curved shapes are approximated by a polytope with a limited number of vertices,
+// which can slightly reduce the accuracy for deep penetrations.
+// Returns false if the shapes do not overlap.
+func EPAPenetration3f(a, b ConvexShape3f) (Penetration3f, bool) {
+	s, overlap := gjk(a, b, true)
+	if !overlap {
+		return Penetration3f{}, false
+	}
+	return epa(a, b, s), true
+}
+
+// GJKIntersects2f checks if two convex shapes overlap, using the Gilbert–Johnson–Keerthi algorithm.
+// Shapes that touch each other might or might not be considered overlapping, due to rounding errors.
+func GJKIntersects2f(a, b ConvexShape2f) bool {
+	return GJKIntersects3f(planarShape{a}, planarShape{b})
+}
+
+// GJKDistance2f returns the distance between two convex shapes and their closest points,
+// using the Gilbert–Johnson–Keerthi algorithm.
+// If the shapes overlap, the distance is zero and the closest points are undefined; see EPAPenetration2f.
+func GJKDistance2f(a, b ConvexShape2f) (dist float32, closestA, closestB Vec2f) {
+	dist, pa, pb := GJKDistance3f(planarShape{a}, planarShape{b})
+	return dist, pa.XY(), pb.XY()
+}
+
+// EPAPenetration2f calculates the penetration depth and collision normal of two overlapping convex shapes,
+// using the Gilbert–Johnson–Keerthi and the Expanding Polytope Algorithm.
+// Returns false if the shapes do not overlap.
+func EPAPenetration2f(a, b ConvexShape2f) (Penetration2f, bool) {
+	pa, pb := planarShape{a}, planarShape{b}
+	s, overlap := gjk(pa, pb, true)
+	if !overlap {
+		return Penetration2f{}, false
+	}
+	return epa2D(pa, pb, s), true
+}
+
+// planarShape embeds a 2D shape into the XY plane, so that it can be used with the 3D algorithms.
+type planarShape struct {
+	shape ConvexShape2f
+}
+
+func (p planarShape) Support(dir Vec3f) Vec3f {
+	s := p.shape.Support(Vec2f{dir[0], dir[1]})
+	return Vec3f{s[0], s[1], 0}
+}
+
+// gjkVertex is a point of the Minkowski difference, together with the support points of both shapes.
+type gjkVertex struct {
+	w    vec3d
+	a, b Vec3f
+}
+
+// gjkSupport returns the Minkowski difference's farthest point in the given direction.
+func gjkSupport(a, b ConvexShape3f, dir vec3d) gjkVertex {
+	// normalize in double precision, as very short directions can't be represented as Vec3f
+	dir = dir.mul(1 / math.Sqrt(dir.dot(dir)))
+	d := Vec3f{float32(dir[0]), float32(dir[1]), float32(dir[2])}
+	pa, pb := a.Support(d), b.Support(d.Negate())
+	return gjkVertex{toVec3d(pa).sub(toVec3d(pb)), pa, pb}
+}
+
+// gjkSimplex is a simplex within the Minkowski difference, with the barycentric coordinates
+// of its point closest to the origin.
+type gjkSimplex struct {
+	verts   [4]gjkVertex
+	weights [4]float64
+	n       int
+}
+
+// gjk runs the Gilbert–Johnson–Keerthi algorithm on the Minkowski difference of two shapes.
+// Returns the simplex closest to the origin, and whether the shapes overlap.
+// If earlyExit is set, the algorithm stops as soon as the shapes are known to be separated.
+func gjk(a, b ConvexShape3f, earlyExit bool) (gjkSimplex, bool) {
+	// Source: "A Fast and Robust GJK Implementation for Collision Detection of Convex Objects"
+	// by Gino van den Bergen, 1999.
+	var s gjkSimplex
+	s.verts[0] = gjkSupport(a, b, vec3d{1, 0, 0})
+	s.weights[0] = 1
+	s.n = 1
+	v := s.verts[0].w
+
+	for i := 0; i < gjkMaxIterations; i++ {
+		vv := v.dot(v)
+		if vv <= gjkTolerance*gjkTolerance*s.maxSquareLength() {
+			return s, true // the origin lies on the simplex
+		}
+		p := gjkSupport(a, b, v.mul(-1))
+		vp := v.dot(p.w)
+		if earlyExit && vp > 0 {
+			return s, false // v is a separating axis
+		}
+		if vv-vp <= gjkTolerance*vv || s.contains(p.w) {
+			return s, false // no more progress; v is the closest point
+		}
+
+		prev := s
+		s.verts[s.n] = p
+		s.n++
+		if s.reduce() {
+			return s, true
+		}
+		v = s.closest()
+		if v.dot(v) >= vv { // rounding errors
+			return prev, false
+		}
+	}
+	return s, false
+}
+
+// maxSquareLength returns the squared length of the simplex' longest vertex.
+func (s *gjkSimplex) maxSquareLength() float64 {
+	var max float64
+	for i := 0; i < s.n; i++ {
+		max = math.Max(max, s.verts[i].w.dot(s.verts[i].w))
+	}
+	return max
+}
+
+// contains checks if the point is one of the simplex' vertices.
+func (s *gjkSimplex) contains(w vec3d) bool {
+	for i := 0; i < s.n; i++ {
+		if s.verts[i].w == w {
+			return true
+		}
+	}
+	return false
+}
+
+// closest returns the simplex' point closest to the origin.
+func (s *gjkSimplex) closest() vec3d {
+	var v vec3d
+	for i := 0; i < s.n; i++ {
+		v = v.add(s.verts[i].w.mul(s.weights[i]))
+	}
+	return v
+}
+
+// witnesses returns the points on both shapes that correspond to the simplex' point closest to the origin.
+func (s *gjkSimplex) witnesses() (Vec3f, Vec3f) {
+	var a, b vec3d
+	for i := 0; i < s.n; i++ {
+		a = a.add(toVec3d(s.verts[i].a).mul(s.weights[i]))
+		b = b.add(toVec3d(s.verts[i].b).mul(s.weights[i]))
+	}
+	return fromVec3d(a), fromVec3d(b)
+}
+
+// reduce calculates the simplex' point closest to the origin and removes all vertices that don't contribute to it.
+// Returns true if the origin lies within the simplex, which is a tetrahedron in that case.
+func (s *gjkSimplex) reduce() bool {
+	v := &s.verts
+	switch s.n {
+	case 1:
+		s.weights = [4]float64{1}
+	case 2:
+		w := segmentWeights(v[0].w, v[1].w)
+		s.weights = [4]float64{w[0], w[1]}
+	case 3:
+		w := triangleWeights(v[0].w, v[1].w, v[2].w)
+		s.weights = [4]float64{w[0], w[1], w[2]}
+	case 4:
+		var inside bool
+		s.weights, inside = tetrahedronWeights(v[0].w, v[1].w, v[2].w, v[3].w)
+		if inside {
+			return true
+		}
+	}
+
+	n := 0
+	for i := 0; i < s.n; i++ {
+		if s.weights[i] > 0 {
+			s.verts[n], s.weights[n] = s.verts[i], s.weights[i]
+			n++
+		}
+	}
+	s.n = n
+	return false
+}
+
+// segmentWeights returns the barycentric coordinates of the segment's point closest to the origin.
+func segmentWeights(a, b vec3d) [2]float64 {
+	ab := b.sub(a)
+	t := -a.dot(ab) / ab.dot(ab)
+	if t <= 0 || math.IsNaN(t) {
+		return [2]float64{1, 0}
+	}
+	if t >= 1 {
+		return [2]float64{0, 1}
+	}
+	return [2]float64{1 - t, t}
+}
+
+// triangleWeights returns the barycentric coordinates of the triangle's point closest to the origin.
+func triangleWeights(a, b, c vec3d) [3]float64 {
+	// Source: "Real-Time Collision Detection" by Christer Ericson, chapter 5.1.5
+	// Same as Triangle3f.ClosestPoint, for the origin.
+	ab := b.sub(a)
+	ac := c.sub(a)
+
+	d1 := -ab.dot(a)
+	d2 := -ac.dot(a)
+	if d1 <= 0 && d2 <= 0 {
+		return [3]float64{1, 0, 0}
+	}
+	d3 := -ab.dot(b)
+	d4 := -ac.dot(b)
+	if d3 >= 0 && d4 <= d3 {
+		return [3]float64{0, 1, 0}
+	}
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		v := d1 / (d1 - d3)
+		return [3]float64{1 - v, v, 0}
+	}
+	d5 := -ab.dot(c)
+	d6 := -ac.dot(c)
+	if d6 >= 0 && d5 <= d6 {
+		return [3]float64{0, 0, 1}
+	}
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		w := d2 / (d2 - d6)
+		return [3]float64{1 - w, 0, w}
+	}
+	va := d3*d6 - d5*d4
+	if va <= 0 && (d4-d3) >= 0 && (d5-d6) >= 0 {
+		w := (d4 - d3) / ((d4 - d3) + (d5 - d6))
+		return [3]float64{0, 1 - w, w}
+	}
+	denom := va + vb + vc
+	if denom <= 0 { // degenerate triangle; use the closest edge
+		best, bestDist := [3]float64{}, math.Inf(1)
+		for _, e := range [3][2]int{{0, 1}, {1, 2}, {2, 0}} {
+			p := [3]vec3d{a, b, c}
+			w := segmentWeights(p[e[0]], p[e[1]])
+			q := p[e[0]].mul(w[0]).add(p[e[1]].mul(w[1]))
+			if dist := q.dot(q); dist < bestDist {
+				best, bestDist = [3]float64{}, dist
+				best[e[0]], best[e[1]] = w[0], w[1]
+			}
+		}
+		return best
+	}
+	v := vb / denom
+	w := vc / denom
+	return [3]float64{1 - v - w, v, w}
+}
+
+// tetrahedronWeights returns the barycentric coordinates of the tetrahedron's point closest to the origin.
+// Returns true if the origin lies within the tetrahedron; the coordinates are undefined in that case.
+func tetrahedronWeights(a, b, c, d vec3d) ([4]float64, bool) {
+	// Source: "Real-Time Collision Detection" by Christer Ericson, chapter 5.1.6
+	p := [4]vec3d{a, b, c, d}
+	faces := [4][3]int{{1, 2, 3}, {0, 3, 2}, {0, 1, 3}, {0, 2, 1}} // face i lies opposite of vertex i
+
+	var best [4]float64
+	bestDist := math.Inf(1)
+	inside := true
+	for i, f := range faces {
+		fa, fb, fc := p[f[0]], p[f[1]], p[f[2]]
+		normal := fb.sub(fa).cross(fc.sub(fa))
+		// the origin is on the inside if it is on the same side as the opposite vertex
+		if normal.dot(p[i].sub(fa))*normal.dot(fa.mul(-1)) > 0 {
+			continue
+		}
+		inside = false
+		w := triangleWeights(fa, fb, fc)
+		q := fa.mul(w[0]).add(fb.mul(w[1])).add(fc.mul(w[2]))
+		if dist := q.dot(q); dist < bestDist {
+			best, bestDist = [4]float64{}, dist
+			best[f[0]], best[f[1]], best[f[2]] = w[0], w[1], w[2]
+		}
+	}
+	return best, inside
+}
+
+// epaFace is a triangle of the polytope in the Expanding Polytope Algorithm.
+type epaFace struct {
+	v      [3]int
+	normal vec3d   // normalized, pointing outwards
+	dist   float64 // distance between the origin and the face's plane
+}
+
+// epa runs the Expanding Polytope Algorithm on the simplex of two overlapping shapes.
+func epa(a, b ConvexShape3f, s gjkSimplex) Penetration3f {
+	// Source: "Proximity Queries and Penetration Depth Computation on 3D Game Objects"
+	// by Gino van den Bergen, 2001.
+	verts := append([]gjkVertex{}, s.verts[:s.n]...)
+	eps := gjkTolerance * math.Sqrt(s.maxSquareLength())
+	normal := vec3d{1, 0, 0} // collision normal if the Minkowski difference is flat
+
+	// expand the simplex to a tetrahedron
+	if len(verts) == 1 {
+		for _, dir := range [6]vec3d{{1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, -1, 0}, {0, 0, 1}, {0, 0, -1}} {
+			if p := gjkSupport(a, b, dir); p.w.sqDist(verts[0].w) > eps*eps {
+				verts = append(verts, p)
+				break
+			}
+		}
+	}
+	if len(verts) == 2 {
+		d := verts[1].w.sub(verts[0].w)
+		axis := vec3d{1, 0, 0}
+		if math.Abs(d[1]) < math.Abs(d[0]) && math.Abs(d[1]) <= math.Abs(d[2]) {
+			axis = vec3d{0, 1, 0}
+		} else if math.Abs(d[2]) < math.Abs(d[0]) {
+			axis = vec3d{0, 0, 1}
+		}
+		e1 := d.cross(axis)
+		e2 := d.cross(e1)
+		normal = e1.mul(1 / math.Sqrt(e1.dot(e1)))
+		for _, dir := range [4]vec3d{e1, e1.mul(-1), e2, e2.mul(-1)} {
+			p := gjkSupport(a, b, dir)
+			if off := p.w.sub(verts[0].w).cross(d); off.dot(off) > eps*eps*d.dot(d) {
+				verts = append(verts, p)
+				break
+			}
+		}
+	}
+	if len(verts) == 3 {
+		n := verts[1].w.sub(verts[0].w).cross(verts[2].w.sub(verts[0].w))
+		n = n.mul(1 / math.Sqrt(n.dot(n)))
+		normal = n
+		for _, dir := range [2]vec3d{n, n.mul(-1)} {
+			if p := gjkSupport(a, b, dir); math.Abs(n.dot(p.w.sub(verts[0].w))) > eps {
+				verts = append(verts, p)
+				break
+			}
+		}
+	}
+	if len(verts) < 4 { // the Minkowski difference is flat; the shapes only touch
+		pa, pb := s.witnesses()
+		return Penetration3f{Normal: fromVec3d(normal), PointA: pa, PointB: pb}
+	}
+
+	// the centroid stays within the polytope, and is used to orient the faces
+	center := verts[0].w.add(verts[1].w).add(verts[2].w).add(verts[3].w).mul(0.25)
+	makeFace := func(i, j, k int) epaFace {
+		a := verts[i].w
+		n := verts[j].w.sub(a).cross(verts[k].w.sub(a))
+		if n.dot(a.sub(center)) < 0 {
+			j, k = k, j
+			n = n.mul(-1)
+		}
+		length := math.Sqrt(n.dot(n))
+		if length == 0 { // degenerate face
+			return epaFace{[3]int{i, j, k}, n, math.Inf(1)}
+		}
+		n = n.mul(1 / length)
+		return epaFace{[3]int{i, j, k}, n, n.dot(a)}
+	}
+	faces := []epaFace{makeFace(0, 1, 2), makeFace(0, 3, 1), makeFace(0, 2, 3), makeFace(1, 3, 2)}
+	var horizon [][2]int
+
+	for iter := 0; ; iter++ {
+		closest := 0
+		for i, f := range faces {
+			if f.dist < faces[closest].dist {
+				closest = i
+			}
+		}
+		face := faces[closest]
+		p := gjkSupport(a, b, face.normal)
+		if iter == gjkMaxIterations || p.w.dot(face.normal)-face.dist <= eps {
+			break
+		}
+
+		// remove all faces that are visible from the new point, and collect the edges of the resulting hole
+		horizon = horizon[:0]
+		n := 0
+		for _, f := range faces {
+			if f.normal.dot(p.w.sub(verts[f.v[0]].w)) <= 0 {
+				faces[n] = f
+				n++
+				continue
+			}
+			for e := 0; e < 3; e++ {
+				edge := [2]int{f.v[e], f.v[(e+1)%3]}
+				// edges shared by two removed faces are traversed in opposite directions
+				shared := false
+				for h, other := range horizon {
+					if other[0] == edge[1] && other[1] == edge[0] {
+						horizon[h] = horizon[len(horizon)-1]
+						horizon = horizon[:len(horizon)-1]
+						shared = true
+						break
+					}
+				}
+				if !shared {
+					horizon = append(horizon, edge)
+				}
+			}
+		}
+		faces = faces[:n]
+		verts = append(verts, p)
+		for _, edge := range horizon {
+			faces = append(faces, makeFace(edge[0], edge[1], len(verts)-1))
+		}
+	}
+
+	closest := faces[0]
+	for _, f := range faces {
+		if f.dist < closest.dist {
+			closest = f
+		}
+	}
+	va, vb, vc := verts[closest.v[0]], verts[closest.v[1]], verts[closest.v[2]]
+	w := barycentricd(va.w, vb.w, vc.w, closest.normal.mul(closest.dist))
+	pa := toVec3d(va.a).mul(w[0]).add(toVec3d(vb.a).mul(w[1])).add(toVec3d(vc.a).mul(w[2]))
+	pb := toVec3d(va.b).mul(w[0]).add(toVec3d(vb.b).mul(w[1])).add(toVec3d(vc.b).mul(w[2]))
+	return Penetration3f{
+		Normal: fromVec3d(closest.normal),
+		Depth:  float32(math.Max(closest.dist, 0)),
+		PointA: fromVec3d(pa),
+		PointB: fromVec3d(pb),
+	}
+}
+
+// epa2D runs the Expanding Polytope Algorithm on the simplex of two overlapping shapes within the XY plane.
+func epa2D(a, b planarShape, s gjkSimplex) Penetration2f {
+	poly := append([]gjkVertex{}, s.verts[:s.n]...)
+	eps := gjkTolerance * math.Sqrt(s.maxSquareLength())
+	normal := vec3d{1, 0, 0} // collision normal if the Minkowski difference is flat
+
+	// expand the simplex to a triangle
+	if len(poly) == 1 {
+		for _, dir := range [4]vec3d{{1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, -1, 0}} {
+			if p := gjkSupport(a, b, dir); p.w.sqDist(poly[0].w) > eps*eps {
+				poly = append(poly, p)
+				break
+			}
+		}
+	}
+	if len(poly) == 2 {
+		d := poly[1].w.sub(poly[0].w)
+		perp := vec3d{-d[1], d[0], 0}
+		normal = perp.mul(1 / math.Sqrt(perp.dot(perp)))
+		for _, dir := range [2]vec3d{perp, perp.mul(-1)} {
+			if p := gjkSupport(a, b, dir); math.Abs(normal.dot(p.w.sub(poly[0].w))) > eps {
+				poly = append(poly, p)
+				break
+			}
+		}
+	}
+	if len(poly) < 3 { // the Minkowski difference is flat; the shapes only touch
+		pa, pb := s.witnesses()
+		return Penetration2f{Normal: fromVec3d(normal).XY(), PointA: pa.XY(), PointB: pb.XY()}
+	}
+	if e1, e2 := poly[1].w.sub(poly[0].w), poly[2].w.sub(poly[0].w); e1[0]*e2[1]-e1[1]*e2[0] < 0 {
+		poly[1], poly[2] = poly[2], poly[1] // counter-clockwise order
+	}
+
+	var closest int
+	var dist float64
+	for iter := 0; ; iter++ {
+		closest, dist = -1, math.Inf(1)
+		for i := range poly {
+			e := poly[(i+1)%len(poly)].w.sub(poly[i].w)
+			n := vec3d{e[1], -e[0], 0} // outwards, on the right hand side
+			length := math.Sqrt(n.dot(n))
+			if length == 0 {
+				continue
+			}
+			n = n.mul(1 / length)
+			if d := n.dot(poly[i].w); d < dist {
+				closest, dist, normal = i, d, n
+			}
+		}
+		p := gjkSupport(a, b, normal)
+		if iter == gjkMaxIterations || p.w.dot(normal)-dist <= eps {
+			break
+		}
+		poly = append(poly, gjkVertex{})
+		copy(poly[closest+2:], poly[closest+1:])
+		poly[closest+1] = p
+	}
+
+	va, vb := poly[closest], poly[(closest+1)%len(poly)]
+	w := segmentWeights(va.w.sub(normal.mul(dist)), vb.w.sub(normal.mul(dist)))
+	pa := toVec3d(va.a).mul(w[0]).add(toVec3d(vb.a).mul(w[1]))
+	pb := toVec3d(va.b).mul(w[0]).add(toVec3d(vb.b).mul(w[1]))
+	return Penetration2f{
+		Normal: fromVec3d(normal).XY(),
+		Depth:  float32(math.Max(dist, 0)),
+		PointA: fromVec3d(pa).XY(),
+		PointB: fromVec3d(pb).XY(),
+	}
+}
+
+// barycentricd returns the barycentric coordinates of a point within the triangle's plane.
+func barycentricd(a, b, c, p vec3d) [3]float64 {
+	// Source: "Real-Time Collision Detection" by Christer Ericson, chapter 3.4
+	v0, v1, v2 := b.sub(a), c.sub(a), p.sub(a)
+	d00, d01, d11 := v0.dot(v0), v0.dot(v1), v1.dot(v1)
+	d20, d21 := v2.dot(v0), v2.dot(v1)
+	denom := d00*d11 - d01*d01
+	v := (d11*d20 - d01*d21) / denom
+	w := (d00*d21 - d01*d20) / denom
+	return [3]float64{1 - v - w, v, w}
+}
+
+func toVec3d(v Vec3f) vec3d {
+	return vec3d{float64(v[0]), float64(v[1]), float64(v[2])}
+}
+
+func fromVec3d(v vec3d) Vec3f {
+	return Vec3f{float32(v[0]), float32(v[1]), float32(v[2])}
+}
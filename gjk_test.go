@@ -0,0 +1,255 @@
+package vmath
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/maja42/vmath/math32"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGJKIntersects3f(t *testing.T) {
+	box := Box3f{Vec3f{-1, -1, -1}, Vec3f{1, 1, 1}}
+	assert.True(t, GJKIntersects3f(box, Sphere{Vec3f{1.5, 0, 0}, 1}))
+	assert.False(t, GJKIntersects3f(box, Sphere{Vec3f{2.5, 0, 0}, 1}))
+	assert.False(t, GJKIntersects3f(box, Sphere{Vec3f{1.8, 1.8, 0}, 1})) // close to the edge
+	assert.True(t, GJKIntersects3f(box, Sphere{Vec3f{1.6, 1.6, 0}, 1}))
+	assert.True(t, GJKIntersects3f(box, Sphere{Vec3f{0, 0, 0}, 0.1})) // fully contained
+
+	assert.True(t, GJKIntersects3f(box, Capsule{Vec3f{-5, 3, 0}, Vec3f{5, 3, 0}, 2.5}))
+	assert.False(t, GJKIntersects3f(box, Capsule{Vec3f{-5, 3, 0}, Vec3f{5, 3, 0}, 1.9}))
+
+	tetra := PointCloud3f{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	assert.True(t, GJKIntersects3f(tetra, Sphere{Vec3f{0.2, 0.2, 0.2}, 0.1}))
+	assert.False(t, GJKIntersects3f(tetra, Sphere{Vec3f{0.6, 0.6, 0.6}, 0.1}))
+}
+
+func TestGJKIntersects3f_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	randomVec := func() Vec3f {
+		return Vec3f{rnd.Float32()*10 - 5, rnd.Float32()*10 - 5, rnd.Float32()*10 - 5}
+	}
+	for i := 0; i < 1000; i++ {
+		b1 := Box3fFromCorners(randomVec(), randomVec())
+		b2 := Box3fFromCorners(randomVec(), randomVec())
+		assert.Equal(t, b1.Intersects(b2), GJKIntersects3f(b1, b2), "%v, %v", b1, b2)
+
+		s1 := Sphere{randomVec(), rnd.Float32() * 3}
+		s2 := Sphere{randomVec(), rnd.Float32() * 3}
+		assert.Equal(t, s1.Intersects(s2), GJKIntersects3f(s1, s2), "%v, %v", s1, s2)
+		assert.Equal(t, s1.IntersectsBox3f(b1), GJKIntersects3f(s1, b1), "%v, %v", s1, b1)
+
+		c := Capsule{randomVec(), randomVec(), rnd.Float32() * 3}
+		assert.Equal(t, c.IntersectsSphere(s1), GJKIntersects3f(c, s1), "%v, %v", c, s1)
+	}
+}
+
+func TestGJKDistance3f(t *testing.T) {
+	dist, pa, pb := GJKDistance3f(Sphere{Vec3f{0, 0, 0}, 1}, Sphere{Vec3f{5, 0, 0}, 2})
+	assert.InDelta(t, 2, dist, 1e-4)
+	AssertVec3f(t, Vec3f{1, 0, 0}, pa)
+	AssertVec3f(t, Vec3f{3, 0, 0}, pb)
+
+	box := Box3f{Vec3f{-1, -1, -1}, Vec3f{1, 1, 1}}
+	dist, pa, pb = GJKDistance3f(box, Box3f{Vec3f{3, 2, -5}, Vec3f{4, 3, 5}})
+	AssertFloat(t, Vec3f{2, 1, 0}.Length(), dist)
+	assert.InDelta(t, 1, pa[0], 1e-5)
+	assert.InDelta(t, 1, pa[1], 1e-5)
+	assert.InDelta(t, 3, pb[0], 1e-5)
+	assert.InDelta(t, 2, pb[1], 1e-5)
+	assert.InDelta(t, pa[2], pb[2], 1e-5)
+
+	// segment-like capsules
+	c1 := Capsule{Vec3f{0, 0, 0}, Vec3f{10, 0, 0}, 0.5}
+	c2 := Capsule{Vec3f{5, -5, 3}, Vec3f{5, 5, 3}, 0.5}
+	dist, pa, pb = GJKDistance3f(c1, c2)
+	assert.InDelta(t, 2, dist, 1e-4)
+	AssertVec3f(t, Vec3f{5, 0, 0.5}, pa)
+	AssertVec3f(t, Vec3f{5, 0, 2.5}, pb)
+
+	tetra := PointCloud3f{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	dist, pa, pb = GJKDistance3f(tetra, PointCloud3f{{1, 1, 1}, {2, 2, 2}})
+	AssertFloat(t, Vec3f{2.0 / 3, 2.0 / 3, 2.0 / 3}.Length(), dist)
+	AssertVec3f(t, Vec3f{1.0 / 3, 1.0 / 3, 1.0 / 3}, pa)
+	AssertVec3f(t, Vec3f{1, 1, 1}, pb)
+
+	dist, _, _ = GJKDistance3f(box, Sphere{Vec3f{1, 1, 1}, 1})
+	assert.Equal(t, float32(0), dist)
+}
+
+func TestGJKDistance3f_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	randomVec := func() Vec3f {
+		return Vec3f{rnd.Float32()*10 - 5, rnd.Float32()*10 - 5, rnd.Float32()*10 - 5}
+	}
+	for i := 0; i < 1000; i++ {
+		box := Box3fFromCorners(randomVec(), randomVec())
+		point := randomVec()
+		dist, pa, pb := GJKDistance3f(box, PointCloud3f{point})
+		assert.InDelta(t, box.PointDistance(point), dist, 1e-4)
+		if dist > 0 {
+			assert.InDelta(t, 0, box.ClosestPoint(point).Distance(pa), 1e-4)
+			assert.Equal(t, point, pb)
+		}
+
+		s1 := Segment3f{randomVec(), randomVec()}
+		s2 := Segment3f{randomVec(), randomVec()}
+		dist, _, _ = GJKDistance3f(s1, s2)
+		assert.InDelta(t, s1.Distance(s2), dist, 1e-4)
+	}
+}
+
+func TestEPAPenetration3f(t *testing.T) {
+	box := Box3f{Vec3f{-1, -1, -1}, Vec3f{1, 1, 1}}
+	pen, ok := EPAPenetration3f(box, Box3f{Vec3f{0.5, -0.5, -0.5}, Vec3f{3, 0.5, 0.5}})
+	assert.True(t, ok)
+	AssertVec3f(t, Vec3f{1, 0, 0}, pen.Normal)
+	AssertFloat(t, 0.5, pen.Depth)
+	AssertFloat(t, 1, pen.PointA[0])
+	AssertFloat(t, 0.5, pen.PointB[0])
+
+	pen, ok = EPAPenetration3f(Box3f{Vec3f{-2, -2, 0.5}, Vec3f{2, 2, 3}}, box)
+	assert.True(t, ok)
+	AssertVec3f(t, Vec3f{0, 0, -1}, pen.Normal)
+	AssertFloat(t, 0.5, pen.Depth)
+
+	pen, ok = EPAPenetration3f(Sphere{Vec3f{0, 0, 0}, 1}, Sphere{Vec3f{1.5, 0, 0}, 1})
+	assert.True(t, ok)
+	assert.InDelta(t, 1, pen.Normal[0], 1e-3)
+	assert.InDelta(t, 0.5, pen.Depth, 1e-3)
+	assert.InDelta(t, 1, pen.PointA[0], 1e-3)
+	assert.InDelta(t, 0.5, pen.PointB[0], 1e-3)
+
+	pen, ok = EPAPenetration3f(box, Capsule{Vec3f{-5, 1.5, 0}, Vec3f{5, 1.5, 0}, 1})
+	assert.True(t, ok)
+	AssertVec3f(t, Vec3f{0, 1, 0}, pen.Normal)
+	AssertFloat(t, 0.5, pen.Depth)
+
+	// moving the shape by the penetration resolves the collision
+	rot := QuatFromAxisAngle(Vec3f{1, 2, 3}.Normalize(), 0.7)
+	obb := OBB3f{Vec3f{0.5, 1, 1.2}, Vec3f{1, 0.5, 0.8}, rot}
+	pen, ok = EPAPenetration3f(box, obb)
+	assert.True(t, ok)
+	assert.True(t, pen.Depth > 0)
+	obb.Center = obb.Center.Add(pen.Normal.MulScalar(pen.Depth * 1.001))
+	assert.False(t, GJKIntersects3f(box, obb))
+	obb.Center = obb.Center.Sub(pen.Normal.MulScalar(pen.Depth * 0.01))
+	assert.True(t, GJKIntersects3f(box, obb))
+
+	_, ok = EPAPenetration3f(box, Sphere{Vec3f{3, 0, 0}, 1})
+	assert.False(t, ok)
+}
+
+func TestEPAPenetration3f_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	randomVec := func() Vec3f {
+		return Vec3f{rnd.Float32()*4 - 2, rnd.Float32()*4 - 2, rnd.Float32()*4 - 2}
+	}
+	for i := 0; i < 500; i++ {
+		a := Sphere{randomVec(), rnd.Float32()*2 + 0.1}
+		b := Sphere{randomVec(), rnd.Float32()*2 + 0.1}
+		pen, ok := EPAPenetration3f(a, b)
+		dist := a.Center.Distance(b.Center)
+		if !ok {
+			assert.True(t, dist > a.Radius+b.Radius-1e-4)
+			continue
+		}
+		// spheres are approximated by a polytope
+		assert.InEpsilon(t, a.Radius+b.Radius-dist, pen.Depth, 1e-3)
+
+		b1 := Box3fFromCorners(randomVec(), randomVec())
+		b2 := Box3fFromCorners(randomVec(), randomVec())
+		pen, ok = EPAPenetration3f(b1, b2)
+		assert.Equal(t, b1.Intersects(b2), ok)
+		if !ok {
+			continue
+		}
+		minDepth := math32.Min(b1.Max[0]-b2.Min[0], b2.Max[0]-b1.Min[0])
+		minDepth = math32.Min(minDepth, math32.Min(b1.Max[1]-b2.Min[1], b2.Max[1]-b1.Min[1]))
+		minDepth = math32.Min(minDepth, math32.Min(b1.Max[2]-b2.Min[2], b2.Max[2]-b1.Min[2]))
+		assert.InDelta(t, minDepth, pen.Depth, 1e-4)
+	}
+}
+
+func TestGJKIntersects2f(t *testing.T) {
+	rect := Rectf{Vec2f{-1, -1}, Vec2f{1, 1}}
+	assert.True(t, GJKIntersects2f(rect, Circle{Vec2f{1.5, 0}, 1}))
+	assert.False(t, GJKIntersects2f(rect, Circle{Vec2f{2.5, 0}, 1}))
+	assert.False(t, GJKIntersects2f(rect, Circle{Vec2f{1.8, 1.8}, 1}))
+	assert.True(t, GJKIntersects2f(rect, Circle{Vec2f{1.6, 1.6}, 1}))
+
+	triangle := PointCloud2f{{0, 0}, {4, 0}, {0, 4}}
+	assert.True(t, GJKIntersects2f(triangle, PointCloud2f{{1, 1}}))
+	assert.False(t, GJKIntersects2f(triangle, PointCloud2f{{3, 3}}))
+	assert.True(t, GJKIntersects2f(triangle, Segment2f{Vec2f{3, 3}, Vec2f{1, 0.5}}))
+}
+
+func TestGJKIntersects2f_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(4))
+	points := randomPoints2f(4000, 4)
+	for i := 0; i < 1000; i++ {
+		r1 := RectfFromCorners(points[4*i], points[4*i+1])
+		r2 := RectfFromCorners(points[4*i+2], points[4*i+3])
+		assert.Equal(t, r1.Intersects(r2), GJKIntersects2f(r1, r2), "%v, %v", r1, r2)
+
+		c := Circle{points[4*i], rnd.Float32() * 5}
+		assert.Equal(t, c.IntersectsRectf(r2), GJKIntersects2f(c, r2), "%v, %v", c, r2)
+	}
+}
+
+func TestGJKDistance2f(t *testing.T) {
+	rect := Rectf{Vec2f{-1, -1}, Vec2f{1, 1}}
+	dist, pa, pb := GJKDistance2f(rect, Circle{Vec2f{4, 5}, 2})
+	assert.InDelta(t, 3, dist, 1e-4)
+	AssertVec2f(t, Vec2f{1, 1}, pa)
+	assert.InDelta(t, 2.8, pb[0], 1e-3)
+	assert.InDelta(t, 3.4, pb[1], 1e-3)
+
+	dist, pa, pb = GJKDistance2f(PointCloud2f{{0, 0}, {4, 0}, {0, 4}}, PointCloud2f{{3, 3}, {5, 5}})
+	AssertFloat(t, Vec2f{1, 1}.Length(), dist)
+	AssertVec2f(t, Vec2f{2, 2}, pa)
+	AssertVec2f(t, Vec2f{3, 3}, pb)
+
+	dist, _, _ = GJKDistance2f(rect, Rectf{Vec2f{0, 0}, Vec2f{2, 2}})
+	assert.Equal(t, float32(0), dist)
+}
+
+func TestEPAPenetration2f(t *testing.T) {
+	rect := Rectf{Vec2f{-1, -1}, Vec2f{1, 1}}
+	pen, ok := EPAPenetration2f(rect, Rectf{Vec2f{-0.5, 0.7}, Vec2f{0.5, 3}})
+	assert.True(t, ok)
+	AssertVec2f(t, Vec2f{0, 1}, pen.Normal)
+	AssertFloat(t, 0.3, pen.Depth)
+	AssertFloat(t, 1, pen.PointA[1])
+	AssertFloat(t, 0.7, pen.PointB[1])
+
+	pen, ok = EPAPenetration2f(Circle{Vec2f{0, 0}, 1}, Circle{Vec2f{-1, -1}, 1})
+	assert.True(t, ok)
+	assert.InDelta(t, -0.70710678, pen.Normal[0], 1e-3)
+	assert.InDelta(t, -0.70710678, pen.Normal[1], 1e-3)
+	assert.InDelta(t, 2-math.Sqrt2, pen.Depth, 1e-3)
+
+	pen, ok = EPAPenetration2f(rect, Circle{Vec2f{0, 0}, 0.5})
+	assert.True(t, ok)
+	assert.InDelta(t, 1.5, pen.Depth, 1e-3)
+
+	_, ok = EPAPenetration2f(rect, Circle{Vec2f{3, 0}, 1})
+	assert.False(t, ok)
+}
+
+func TestEPAPenetration2f_Random(t *testing.T) {
+	points := randomPoints2f(2000, 5)
+	for i := 0; i < 500; i++ {
+		r1 := RectfFromCorners(points[4*i], points[4*i+1])
+		r2 := RectfFromCorners(points[4*i+2], points[4*i+3])
+		pen, ok := EPAPenetration2f(r1, r2)
+		assert.Equal(t, r1.Intersects(r2), ok)
+		if !ok {
+			continue
+		}
+		minDepth := math32.Min(r1.Max[0]-r2.Min[0], r2.Max[0]-r1.Min[0])
+		minDepth = math32.Min(minDepth, math32.Min(r1.Max[1]-r2.Min[1], r2.Max[1]-r1.Min[1]))
+		assert.InDelta(t, minDepth, pen.Depth, 1e-4)
+	}
+}
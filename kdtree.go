@@ -0,0 +1,467 @@
+package vmath
+
+import (
+	"github.com/maja42/vmath/math32"
+)
+
+// Metric is a distance metric for nearest-neighbour searches.
+type Metric int
+
+const (
+	// Euclidean is the straight-line distance between two points.
+	Euclidean Metric = iota
+	// Manhattan is the sum of the absolute coordinate differences of two points.
+	Manhattan
+	// Chebyshev is the largest absolute coordinate difference of two points.
+	Chebyshev
+)
+
+func (m Metric) String() string {
+	switch m {
+	case Euclidean:
+		return "Euclidean"
+	case Manhattan:
+		return "Manhattan"
+	case Chebyshev:
+		return "Chebyshev"
+	}
+	return "Metric(invalid)"
+}
+
+// Distance3f returns the distance between two points.
+func (m Metric) Distance3f(a, b Vec3f) float32 {
+	return m.fromKey(m.key3f(a, b))
+}
+
+// Distance2f returns the distance between two points.
+func (m Metric) Distance2f(a, b Vec2f) float32 {
+	return m.fromKey(m.key2f(a, b))
+}
+
+// key3f returns a value that is monotonic to the distance between two points, but cheaper to calculate.
+func (m Metric) key3f(a, b Vec3f) float32 {
+	switch m {
+	case Manhattan:
+		d := a.Sub(b).Abs()
+		return d[0] + d[1] + d[2]
+	case Chebyshev:
+		d := a.Sub(b).Abs()
+		return math32.Max(d[0], math32.Max(d[1], d[2]))
+	}
+	return a.SquareDistance(b)
+}
+
+// key2f returns a value that is monotonic to the distance between two points, but cheaper to calculate.
+func (m Metric) key2f(a, b Vec2f) float32 {
+	switch m {
+	case Manhattan:
+		d := a.Sub(b).Abs()
+		return d[0] + d[1]
+	case Chebyshev:
+		d := a.Sub(b).Abs()
+		return math32.Max(d[0], d[1])
+	}
+	return a.SquareDistance(b)
+}
+
+// toKey converts a distance into a key.
+// It is also the smallest key between two points whose coordinates differ by the given distance along one axis.
+func (m Metric) toKey(dist float32) float32 {
+	if m == Manhattan || m == Chebyshev {
+		return math32.Abs(dist)
+	}
+	return dist * dist
+}
+
+// fromKey converts a key into a distance.
+func (m Metric) fromKey(key float32) float32 {
+	if m == Manhattan || m == Chebyshev {
+		return key
+	}
+	return math32.Sqrt(key)
+}
+
+// KDTree3f is a static k-d tree, which accelerates nearest-neighbour searches within a set of 3D points.
+// Queries return the points' indices within the slice the tree was built from.
+// Queries can run concurrently.
+type KDTree3f struct {
+	metric Metric
+	// The tree is stored implicitly: the root is the median of the whole range,
+	// the left and right subtrees are the ranges below and above it.
+	points  []Vec3f
+	indices []int   // original index of each point
+	axes    []uint8 // split axis of each node
+}
+
+// NewKDTree3f builds a k-d tree from the given points in O(n log n) expected time.
+// The points are copied and can be modified afterwards.
+func NewKDTree3f(points []Vec3f, metric Metric) *KDTree3f {
+	t := &KDTree3f{
+		metric:  metric,
+		points:  make([]Vec3f, len(points)),
+		indices: make([]int, len(points)),
+		axes:    make([]uint8, len(points)),
+	}
+	copy(t.points, points)
+	for i := range t.indices {
+		t.indices[i] = i
+	}
+	t.build(0, len(points))
+	return t
+}
+
+// build creates the subtree for the given range of points.
+func (t *KDTree3f) build(lo, hi int) {
+	if hi-lo <= 0 {
+		return
+	}
+	// split along the axis with the largest extent
+	box := Box3f{t.points[lo], t.points[lo]}
+	for _, p := range t.points[lo+1 : hi] {
+		box = box.ExtendPoint(p)
+	}
+	size := box.Size()
+	axis := 0
+	if size[1] > size[axis] {
+		axis = 1
+	}
+	if size[2] > size[axis] {
+		axis = 2
+	}
+
+	mid := (lo + hi) / 2
+	t.selectNth(lo, hi, mid, axis)
+	t.axes[mid] = uint8(axis)
+	t.build(lo, mid)
+	t.build(mid+1, hi)
+}
+
+// selectNth partially sorts the given range of points along an axis,
+// so that the n-th point is at its sorted position.
+func (t *KDTree3f) selectNth(lo, hi, n, axis int) {
+	// Source: "Algorithm 65: Find" by C. A. R. Hoare, Communications of the ACM, 1961.
+	hi-- // inclusive
+	for lo < hi {
+		pivot := t.points[(lo+hi)/2][axis]
+		i, j := lo, hi
+		for i <= j {
+			for t.points[i][axis] < pivot {
+				i++
+			}
+			for t.points[j][axis] > pivot {
+				j--
+			}
+			if i <= j {
+				t.points[i], t.points[j] = t.points[j], t.points[i]
+				t.indices[i], t.indices[j] = t.indices[j], t.indices[i]
+				i++
+				j--
+			}
+		}
+		switch {
+		case n <= j:
+			hi = j
+		case n >= i:
+			lo = i
+		default:
+			return
+		}
+	}
+}
+
+// Len returns the number of points within the tree.
+func (t *KDTree3f) Len() int {
+	return len(t.points)
+}
+
+// Metric returns the distance metric used by the tree.
+func (t *KDTree3f) Metric() Metric {
+	return t.metric
+}
+
+// Nearest finds the point closest to the given position.
+// Returns the point's index and distance, or false if the tree is empty.
+func (t *KDTree3f) Nearest(pos Vec3f) (int, float32, bool) {
+	if len(t.points) == 0 {
+		return 0, 0, false
+	}
+	best, bestKey := 0, math32.Infinity
+	t.nearest(0, len(t.points), pos, &best, &bestKey)
+	return t.indices[best], t.metric.fromKey(bestKey), true
+}
+
+func (t *KDTree3f) nearest(lo, hi int, pos Vec3f, best *int, bestKey *float32) {
+	if hi-lo <= 0 {
+		return
+	}
+	mid := (lo + hi) / 2
+	p := t.points[mid]
+	if key := t.metric.key3f(p, pos); key < *bestKey {
+		*best, *bestKey = mid, key
+	}
+	diff := pos[t.axes[mid]] - p[t.axes[mid]]
+	if diff < 0 {
+		t.nearest(lo, mid, pos, best, bestKey)
+		if t.metric.toKey(diff) < *bestKey {
+			t.nearest(mid+1, hi, pos, best, bestKey)
+		}
+	} else {
+		t.nearest(mid+1, hi, pos, best, bestKey)
+		if t.metric.toKey(diff) < *bestKey {
+			t.nearest(lo, mid, pos, best, bestKey)
+		}
+	}
+}
+
+// KNearest appends the indices of the k points closest to the given position to dst, and returns the result.
+// Points are ordered by increasing distance. If the tree contains less than k points, all points are returned.
+func (t *KDTree3f) KNearest(pos Vec3f, k int, dst []int) []int {
+	if k <= 0 {
+		return dst
+	}
+	var keyBuf [16]float32
+	res := kNearestResult{dst: dst, start: len(dst), keys: keyBuf[:0], k: k}
+	t.kNearest(0, len(t.points), pos, &res)
+	return res.dst
+}
+
+func (t *KDTree3f) kNearest(lo, hi int, pos Vec3f, res *kNearestResult) {
+	if hi-lo <= 0 {
+		return
+	}
+	mid := (lo + hi) / 2
+	p := t.points[mid]
+	res.add(t.indices[mid], t.metric.key3f(p, pos))
+
+	diff := pos[t.axes[mid]] - p[t.axes[mid]]
+	if diff < 0 {
+		t.kNearest(lo, mid, pos, res)
+		if res.accepts(t.metric.toKey(diff)) {
+			t.kNearest(mid+1, hi, pos, res)
+		}
+	} else {
+		t.kNearest(mid+1, hi, pos, res)
+		if res.accepts(t.metric.toKey(diff)) {
+			t.kNearest(lo, mid, pos, res)
+		}
+	}
+}
+
+// QueryRadius appends the indices of all points within the given distance of a position to dst, and returns the result.
+// Points on the boundary are included. The result is not ordered.
+func (t *KDTree3f) QueryRadius(pos Vec3f, radius float32, dst []int) []int {
+	if radius < 0 {
+		return dst
+	}
+	return t.queryRadius(0, len(t.points), pos, t.metric.toKey(radius), dst)
+}
+
+func (t *KDTree3f) queryRadius(lo, hi int, pos Vec3f, radiusKey float32, dst []int) []int {
+	if hi-lo <= 0 {
+		return dst
+	}
+	mid := (lo + hi) / 2
+	p := t.points[mid]
+	if t.metric.key3f(p, pos) <= radiusKey {
+		dst = append(dst, t.indices[mid])
+	}
+	diff := pos[t.axes[mid]] - p[t.axes[mid]]
+	if diff <= 0 || t.metric.toKey(diff) <= radiusKey {
+		dst = t.queryRadius(lo, mid, pos, radiusKey, dst)
+	}
+	if diff >= 0 || t.metric.toKey(diff) <= radiusKey {
+		dst = t.queryRadius(mid+1, hi, pos, radiusKey, dst)
+	}
+	return dst
+}
+
+// KDTree2f is a static k-d tree, which accelerates nearest-neighbour searches within a set of 2D points.
+// Queries return the points' indices within the slice the tree was built from.
+// Queries can run concurrently.
+type KDTree2f struct {
+	metric Metric
+	// The tree is stored implicitly: the root is the median of the whole range,
+	// the left and right subtrees are the ranges below and above it.
+	points  []Vec2f
+	indices []int   // original index of each point
+	axes    []uint8 // split axis of each node
+}
+
+// NewKDTree2f builds a k-d tree from the given points in O(n log n) expected time.
+// The points are copied and can be modified afterwards.
+func NewKDTree2f(points []Vec2f, metric Metric) *KDTree2f {
+	t := &KDTree2f{
+		metric:  metric,
+		points:  make([]Vec2f, len(points)),
+		indices: make([]int, len(points)),
+		axes:    make([]uint8, len(points)),
+	}
+	copy(t.points, points)
+	for i := range t.indices {
+		t.indices[i] = i
+	}
+	t.build(0, len(points))
+	return t
+}
+
+// build creates the subtree for the given range of points.
+func (t *KDTree2f) build(lo, hi int) {
+	if hi-lo <= 0 {
+		return
+	}
+	// split along the axis with the largest extent
+	rect := Rectf{t.points[lo], t.points[lo]}
+	for _, p := range t.points[lo+1 : hi] {
+		rect = rect.Merge(Rectf{p, p})
+	}
+	size := rect.Size()
+	axis := 0
+	if size[1] > size[0] {
+		axis = 1
+	}
+
+	mid := (lo + hi) / 2
+	t.selectNth(lo, hi, mid, axis)
+	t.axes[mid] = uint8(axis)
+	t.build(lo, mid)
+	t.build(mid+1, hi)
+}
+
+// selectNth partially sorts the given range of points along an axis,
+// so that the n-th point is at its sorted position.
+func (t *KDTree2f) selectNth(lo, hi, n, axis int) {
+	// Source: "Algorithm 65: Find" by C. A. R. Hoare, Communications of the ACM, 1961.
+	hi-- // inclusive
+	for lo < hi {
+		pivot := t.points[(lo+hi)/2][axis]
+		i, j := lo, hi
+		for i <= j {
+			for t.points[i][axis] < pivot {
+				i++
+			}
+			for t.points[j][axis] > pivot {
+				j--
+			}
+			if i <= j {
+				t.points[i], t.points[j] = t.points[j], t.points[i]
+				t.indices[i], t.indices[j] = t.indices[j], t.indices[i]
+				i++
+				j--
+			}
+		}
+		switch {
+		case n <= j:
+			hi = j
+		case n >= i:
+			lo = i
+		default:
+			return
+		}
+	}
+}
+
+// Len returns the number of points within the tree.
+func (t *KDTree2f) Len() int {
+	return len(t.points)
+}
+
+// Metric returns the distance metric used by the tree.
+func (t *KDTree2f) Metric() Metric {
+	return t.metric
+}
+
+// Nearest finds the point closest to the given position.
+// Returns the point's index and distance, or false if the tree is empty.
+func (t *KDTree2f) Nearest(pos Vec2f) (int, float32, bool) {
+	if len(t.points) == 0 {
+		return 0, 0, false
+	}
+	best, bestKey := 0, math32.Infinity
+	t.nearest(0, len(t.points), pos, &best, &bestKey)
+	return t.indices[best], t.metric.fromKey(bestKey), true
+}
+
+func (t *KDTree2f) nearest(lo, hi int, pos Vec2f, best *int, bestKey *float32) {
+	if hi-lo <= 0 {
+		return
+	}
+	mid := (lo + hi) / 2
+	p := t.points[mid]
+	if key := t.metric.key2f(p, pos); key < *bestKey {
+		*best, *bestKey = mid, key
+	}
+	diff := pos[t.axes[mid]] - p[t.axes[mid]]
+	if diff < 0 {
+		t.nearest(lo, mid, pos, best, bestKey)
+		if t.metric.toKey(diff) < *bestKey {
+			t.nearest(mid+1, hi, pos, best, bestKey)
+		}
+	} else {
+		t.nearest(mid+1, hi, pos, best, bestKey)
+		if t.metric.toKey(diff) < *bestKey {
+			t.nearest(lo, mid, pos, best, bestKey)
+		}
+	}
+}
+
+// KNearest appends the indices of the k points closest to the given position to dst, and returns the result.
+// Points are ordered by increasing distance. If the tree contains less than k points, all points are returned.
+func (t *KDTree2f) KNearest(pos Vec2f, k int, dst []int) []int {
+	if k <= 0 {
+		return dst
+	}
+	var keyBuf [16]float32
+	res := kNearestResult{dst: dst, start: len(dst), keys: keyBuf[:0], k: k}
+	t.kNearest(0, len(t.points), pos, &res)
+	return res.dst
+}
+
+func (t *KDTree2f) kNearest(lo, hi int, pos Vec2f, res *kNearestResult) {
+	if hi-lo <= 0 {
+		return
+	}
+	mid := (lo + hi) / 2
+	p := t.points[mid]
+	res.add(t.indices[mid], t.metric.key2f(p, pos))
+
+	diff := pos[t.axes[mid]] - p[t.axes[mid]]
+	if diff < 0 {
+		t.kNearest(lo, mid, pos, res)
+		if res.accepts(t.metric.toKey(diff)) {
+			t.kNearest(mid+1, hi, pos, res)
+		}
+	} else {
+		t.kNearest(mid+1, hi, pos, res)
+		if res.accepts(t.metric.toKey(diff)) {
+			t.kNearest(lo, mid, pos, res)
+		}
+	}
+}
+
+// QueryRadius appends the indices of all points within the given distance of a position to dst, and returns the result.
+// Points on the boundary are included. The result is not ordered.
+func (t *KDTree2f) QueryRadius(pos Vec2f, radius float32, dst []int) []int {
+	if radius < 0 {
+		return dst
+	}
+	return t.queryRadius(0, len(t.points), pos, t.metric.toKey(radius), dst)
+}
+
+func (t *KDTree2f) queryRadius(lo, hi int, pos Vec2f, radiusKey float32, dst []int) []int {
+	if hi-lo <= 0 {
+		return dst
+	}
+	mid := (lo + hi) / 2
+	p := t.points[mid]
+	if t.metric.key2f(p, pos) <= radiusKey {
+		dst = append(dst, t.indices[mid])
+	}
+	diff := pos[t.axes[mid]] - p[t.axes[mid]]
+	if diff <= 0 || t.metric.toKey(diff) <= radiusKey {
+		dst = t.queryRadius(lo, mid, pos, radiusKey, dst)
+	}
+	if diff >= 0 || t.metric.toKey(diff) <= radiusKey {
+		dst = t.queryRadius(mid+1, hi, pos, radiusKey, dst)
+	}
+	return dst
+}
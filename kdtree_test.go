@@ -0,0 +1,134 @@
+package vmath
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetric_String(t *testing.T) {
+	assert.Equal(t, "Euclidean", Euclidean.String())
+	assert.Equal(t, "Manhattan", Manhattan.String())
+	assert.Equal(t, "Chebyshev", Chebyshev.String())
+	assert.Equal(t, "Metric(invalid)", Metric(5).String())
+}
+
+func TestMetric_Distance(t *testing.T) {
+	a, b := Vec3f{1, 2, 3}, Vec3f{2, 0, 5}
+	AssertFloat(t, 3, Euclidean.Distance3f(a, b))
+	AssertFloat(t, 5, Manhattan.Distance3f(a, b))
+	AssertFloat(t, 2, Chebyshev.Distance3f(a, b))
+
+	c, d := Vec2f{1, 2}, Vec2f{4, -2}
+	AssertFloat(t, 5, Euclidean.Distance2f(c, d))
+	AssertFloat(t, 7, Manhattan.Distance2f(c, d))
+	AssertFloat(t, 4, Chebyshev.Distance2f(c, d))
+}
+
+func TestKDTree3f(t *testing.T) {
+	points := randomPoints3f(1000, 1)
+	// duplicates and points on a common plane
+	for i := 0; i < 100; i++ {
+		points = append(points, points[i], Vec3f{points[i][0], points[i][1], 0})
+	}
+	queries := randomPoints3f(50, 2)
+
+	for _, metric := range []Metric{Euclidean, Manhattan, Chebyshev} {
+		tree := NewKDTree3f(points, metric)
+		assert.Equal(t, len(points), tree.Len())
+		assert.Equal(t, metric, tree.Metric())
+
+		for _, pos := range queries {
+			pos = pos.MulScalar(1.2)
+			dists := make([]float32, len(points))
+			order := make([]int, len(points))
+			for i, p := range points {
+				dists[i] = metric.Distance3f(p, pos)
+				order[i] = i
+			}
+			sort.SliceStable(order, func(i, j int) bool { return dists[order[i]] < dists[order[j]] })
+
+			idx, dist, ok := tree.Nearest(pos)
+			assert.True(t, ok)
+			assert.Equal(t, dists[order[0]], dist)
+			assert.Equal(t, dists[order[0]], dists[idx])
+
+			nearest := tree.KNearest(pos, 10, []int{-1})
+			assert.Len(t, nearest, 11)
+			for i, idx := range nearest[1:] {
+				assert.Equal(t, dists[order[i]], dists[idx])
+			}
+
+			var expected []int
+			for i, d := range dists {
+				if d <= 4 {
+					expected = append(expected, i)
+				}
+			}
+			assert.Equal(t, expected, sortedIDs(tree.QueryRadius(pos, 4, nil)))
+		}
+	}
+
+	tree := NewKDTree3f(points, Euclidean)
+	assert.Len(t, tree.KNearest(Vec3f{}, 5000, nil), len(points))
+	assert.Empty(t, tree.KNearest(Vec3f{}, 0, nil))
+	assert.Empty(t, tree.QueryRadius(Vec3f{}, -1, nil))
+
+	// exact hits
+	idx, dist, ok := tree.Nearest(points[42])
+	assert.True(t, ok)
+	assert.Equal(t, float32(0), dist)
+	assert.Equal(t, points[42], points[idx])
+
+	_, _, ok = NewKDTree3f(nil, Euclidean).Nearest(Vec3f{})
+	assert.False(t, ok)
+	assert.Empty(t, NewKDTree3f(nil, Euclidean).KNearest(Vec3f{}, 3, nil))
+}
+
+func TestKDTree2f(t *testing.T) {
+	points := randomPoints2f(1000, 3)
+	for i := 0; i < 100; i++ {
+		points = append(points, points[i], Vec2f{points[i][0], 0})
+	}
+	queries := randomPoints2f(50, 4)
+
+	for _, metric := range []Metric{Euclidean, Manhattan, Chebyshev} {
+		tree := NewKDTree2f(points, metric)
+		assert.Equal(t, len(points), tree.Len())
+		assert.Equal(t, metric, tree.Metric())
+
+		for _, pos := range queries {
+			pos = pos.MulScalar(1.2)
+			dists := make([]float32, len(points))
+			order := make([]int, len(points))
+			for i, p := range points {
+				dists[i] = metric.Distance2f(p, pos)
+				order[i] = i
+			}
+			sort.SliceStable(order, func(i, j int) bool { return dists[order[i]] < dists[order[j]] })
+
+			idx, dist, ok := tree.Nearest(pos)
+			assert.True(t, ok)
+			assert.Equal(t, dists[order[0]], dist)
+			assert.Equal(t, dists[order[0]], dists[idx])
+
+			nearest := tree.KNearest(pos, 10, []int{-1})
+			assert.Len(t, nearest, 11)
+			for i, idx := range nearest[1:] {
+				assert.Equal(t, dists[order[i]], dists[idx])
+			}
+
+			var expected []int
+			for i, d := range dists {
+				if d <= 2 {
+					expected = append(expected, i)
+				}
+			}
+			assert.Equal(t, expected, sortedIDs(tree.QueryRadius(pos, 2, nil)))
+		}
+	}
+
+	_, _, ok := NewKDTree2f(nil, Euclidean).Nearest(Vec2f{})
+	assert.False(t, ok)
+}
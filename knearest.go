@@ -0,0 +1,35 @@
+package vmath
+
+// kNearestResult collects the k closest items, ordered by increasing key.
+type kNearestResult struct {
+	dst   []int
+	start int       // index of the first result within dst
+	keys  []float32 // keys of the results
+	k     int
+}
+
+// add inserts an item into the result, if it is among the k closest items found so far.
+func (r *kNearestResult) add(id int, key float32) {
+	if len(r.keys) == r.k {
+		if key >= r.keys[r.k-1] {
+			return
+		}
+		r.keys = r.keys[:r.k-1]
+		r.dst = r.dst[:r.start+r.k-1]
+	}
+	// insertion sort
+	i := len(r.keys)
+	r.keys = append(r.keys, key)
+	r.dst = append(r.dst, id)
+	for ; i > 0 && r.keys[i-1] > key; i-- {
+		r.keys[i] = r.keys[i-1]
+		r.dst[r.start+i] = r.dst[r.start+i-1]
+	}
+	r.keys[i] = key
+	r.dst[r.start+i] = id
+}
+
+// accepts checks if an item with the given key would be added to the result.
+func (r *kNearestResult) accepts(key float32) bool {
+	return len(r.keys) < r.k || key < r.keys[r.k-1]
+}
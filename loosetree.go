@@ -0,0 +1,209 @@
+package vmath
+
+// looseTree manages the nodes and items of loose quadtrees and octrees, independent of their geometry.
+// Each node has either no children or fanOut children, which are stored as a contiguous block.
+// Nodes are split if they contain more items than the capacity, and merged if their items fit into a single node again.
+// The geometry (node cells and item bounds) is stored by the owner, which decides where items belong.
+type looseTree struct {
+	fanOut   int // number of children per node
+	maxDepth int
+	capacity int
+	nodes    []looseNode // the root node, followed by blocks of fanOut child nodes
+	free     int         // first unused block of child nodes
+	items    map[int]int // node of each item
+	geometry looseGeometry
+}
+
+// looseGeometry provides the geometry-specific parts of a looseTree.
+type looseGeometry interface {
+	// child returns the child of the node that can store the item, or looseNull if the item needs to be stored in the node itself.
+	child(node, id int) int
+	// splitCell sets up the cells of a new block of child nodes.
+	splitCell(node, children int)
+}
+
+// looseNode is a node within a looseTree. Unused blocks of child nodes form a linked list via the children index.
+type looseNode struct {
+	parent   int
+	children int // first of the child nodes, or looseNull for leaves
+	depth    int
+	count    int // number of items within the subtree
+	items    []int
+}
+
+const looseNull = -1
+
+// newLooseTree creates a tree that only consists of the root node.
+func newLooseTree(fanOut, maxDepth, capacity int, geometry looseGeometry) looseTree {
+	return looseTree{
+		fanOut:   fanOut,
+		maxDepth: maxDepth,
+		capacity: capacity,
+		nodes:    []looseNode{{parent: looseNull, children: looseNull}},
+		free:     looseNull,
+		items:    make(map[int]int),
+		geometry: geometry,
+	}
+}
+
+// insert adds a new item, whose bounds must already be known to the geometry.
+func (t *looseTree) insert(id int) {
+	t.add(id, t.find(id))
+}
+
+// update moves an existing item to the correct node after its bounds changed.
+func (t *looseTree) update(id int) {
+	node := t.find(id)
+	if node == t.items[id] {
+		return
+	}
+	t.remove(id)
+	t.add(id, t.find(id)) // the tree might have changed
+}
+
+// find returns the deepest existing node that can store the item.
+func (t *looseTree) find(id int) int {
+	node := 0
+	for {
+		child := t.geometry.child(node, id)
+		if child == looseNull {
+			return node
+		}
+		node = child
+	}
+}
+
+// add stores an item in the given node, and splits the node if it becomes too full.
+func (t *looseTree) add(id, node int) {
+	t.items[id] = node
+	n := &t.nodes[node]
+	n.items = append(n.items, id)
+	for p := node; p != looseNull; p = t.nodes[p].parent {
+		t.nodes[p].count++
+	}
+	if n.children == looseNull && len(n.items) > t.capacity && n.depth < t.maxDepth {
+		t.split(node)
+	}
+}
+
+// remove removes an item, and merges nodes that contain few enough items.
+// Returns false if the item is not within the tree.
+func (t *looseTree) remove(id int) bool {
+	node, ok := t.items[id]
+	if !ok {
+		return false
+	}
+	delete(t.items, id)
+	n := &t.nodes[node]
+	for i, other := range n.items {
+		if other == id {
+			last := len(n.items) - 1
+			n.items[i] = n.items[last]
+			n.items = n.items[:last]
+			break
+		}
+	}
+
+	merge := looseNull
+	for p := node; p != looseNull; p = t.nodes[p].parent {
+		pn := &t.nodes[p]
+		pn.count--
+		if pn.children != looseNull && pn.count <= t.capacity {
+			merge = p
+		}
+	}
+	if merge != looseNull {
+		t.merge(merge, merge)
+	}
+	return true
+}
+
+// split creates the child nodes of a leaf, and moves all items that fit into them.
+func (t *looseTree) split(node int) {
+	children := t.allocate()
+	n := &t.nodes[node]
+	n.children = children
+	for i := 0; i < t.fanOut; i++ {
+		c := &t.nodes[children+i]
+		c.parent = node
+		c.children = looseNull
+		c.depth = n.depth + 1
+		c.count = 0
+	}
+	t.geometry.splitCell(node, children)
+
+	for i := 0; i < len(n.items); {
+		id := n.items[i]
+		child := t.geometry.child(node, id)
+		if child == looseNull {
+			i++
+			continue
+		}
+		c := &t.nodes[child]
+		c.items = append(c.items, id)
+		c.count++
+		t.items[id] = child
+
+		last := len(n.items) - 1
+		n.items[i] = n.items[last]
+		n.items = n.items[:last]
+	}
+
+	for i := 0; i < t.fanOut; i++ {
+		c := &t.nodes[children+i]
+		if len(c.items) > t.capacity && c.depth < t.maxDepth {
+			t.split(children + i)
+		}
+	}
+}
+
+// merge moves all items of the subtree into the target node, and releases the node's children.
+func (t *looseTree) merge(target, node int) {
+	children := t.nodes[node].children
+	if children == looseNull {
+		return
+	}
+	for i := 0; i < t.fanOut; i++ {
+		child := children + i
+		t.merge(target, child)
+		c := &t.nodes[child]
+		tn := &t.nodes[target]
+		for _, id := range c.items {
+			tn.items = append(tn.items, id)
+			t.items[id] = target
+		}
+		c.items = c.items[:0]
+	}
+	t.release(children)
+	t.nodes[node].children = looseNull
+}
+
+// allocate returns the first node of an unused block of child nodes.
+func (t *looseTree) allocate() int {
+	if t.free == looseNull {
+		t.nodes = append(t.nodes, make([]looseNode, t.fanOut)...)
+		return len(t.nodes) - t.fanOut
+	}
+	children := t.free
+	t.free = t.nodes[children].children
+	return children
+}
+
+// release adds the block of child nodes to the free list.
+// The nodes' item slices are kept, so that their memory can be re-used.
+func (t *looseTree) release(children int) {
+	t.nodes[children].children = t.free
+	t.free = children
+}
+
+// appendItems appends the IDs of all items within the subtree.
+func (t *looseTree) appendItems(dst []int, node int) []int {
+	n := &t.nodes[node]
+	dst = append(dst, n.items...)
+	if n.children != looseNull {
+		for i := 0; i < t.fanOut; i++ {
+			dst = t.appendItems(dst, n.children+i)
+		}
+	}
+	return dst
+}
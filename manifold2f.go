@@ -0,0 +1,260 @@
+package vmath
+
+import (
+	"github.com/maja42/vmath/math32"
+)
+
+// Manifold2f describes the contact between two colliding shapes A and B.
+type Manifold2f struct {
+	// Normal is the normalized collision normal, pointing from shape A towards shape B.
+	// Moving B by Normal*Depth separates the shapes.
+	Normal Vec2f
+	// Depth is the penetration depth along the normal.
+	Depth float32
+	// Count is the number of contact points, which is either 1 or 2.
+	Count int
+	// Points contains the contact points, which are located halfway between the surfaces of both shapes.
+	Points [2]Vec2f
+	// Depths contains the penetration depth at each contact point.
+	Depths [2]float32
+}
+
+// Flip returns the manifold with swapped roles of shape A and B.
+func (m Manifold2f) Flip() Manifold2f {
+	m.Normal = m.Normal.Negate()
+	return m
+}
+
+// CollideConvex calculates the contact manifold of two convex polygons, using the separating axis theorem.
+// The polygons can be in clockwise or counter-clockwise order.
+// Returns false if the polygons do not overlap. Touching polygons result in a penetration depth of zero.
+func (p Polygon2f) CollideConvex(other Polygon2f) (Manifold2f, bool) {
+	return collidePolygons(p.counterClockwise(), other.counterClockwise())
+}
+
+// CollideCircle calculates the contact manifold of a convex polygon and a circle.
+// The polygon can be in clockwise or counter-clockwise order.
+// Returns false if the shapes do not overlap. Touching shapes result in a penetration depth of zero.
+func (p Polygon2f) CollideCircle(circle Circle) (Manifold2f, bool) {
+	return collidePolygonCircle(p.counterClockwise(), circle)
+}
+
+// counterClockwise returns the polygon in counter-clockwise order.
+func (p Polygon2f) counterClockwise() Polygon2f {
+	if p.SignedArea() < 0 {
+		return p.Reverse()
+	}
+	return p
+}
+
+// CollideRectf calculates the contact manifold of two rectangles.
+// Returns false if the rectangles do not overlap. Touching rectangles result in a penetration depth of zero.
+func (r Rectf) CollideRectf(other Rectf) (Manifold2f, bool) {
+	if !r.Intersects(other) {
+		return Manifold2f{}, false
+	}
+	// the distance the other rectangle needs to be moved in positive and negative direction along each axis
+	pos := r.Max.Sub(other.Min)
+	neg := other.Max.Sub(r.Min)
+	axis := 0
+	if math32.Min(pos[1], neg[1]) < math32.Min(pos[0], neg[0]) {
+		axis = 1
+	}
+	side := 1 - axis
+
+	var m Manifold2f
+	var center float32 // halfway between the reference and incident edge
+	if pos[axis] <= neg[axis] {
+		m.Normal[axis] = 1
+		m.Depth = pos[axis]
+		center = (r.Max[axis] + other.Min[axis]) / 2
+	} else {
+		m.Normal[axis] = -1
+		m.Depth = neg[axis]
+		center = (r.Min[axis] + other.Max[axis]) / 2
+	}
+	m.Count = 2
+	m.Depths = [2]float32{m.Depth, m.Depth}
+	// the contact points are the ends of the overlapping edges
+	m.Points[0][axis], m.Points[0][side] = center, math32.Max(r.Min[side], other.Min[side])
+	m.Points[1][axis], m.Points[1][side] = center, math32.Min(r.Max[side], other.Max[side])
+	return m, true
+}
+
+// CollideCircle calculates the contact manifold of a rectangle and a circle.
+// Returns false if the shapes do not overlap. Touching shapes result in a penetration depth of zero.
+func (r Rectf) CollideCircle(circle Circle) (Manifold2f, bool) {
+	return collidePolygonCircle(r.Polygon2f(), circle)
+}
+
+// CollideOBB2f calculates the contact manifold of two oriented rectangles, using the separating axis theorem.
+// Returns false if the rectangles do not overlap. Touching rectangles result in a penetration depth of zero.
+func (o OBB2f) CollideOBB2f(other OBB2f) (Manifold2f, bool) {
+	a, b := o.Corners(), other.Corners()
+	return collidePolygons(a[:], b[:])
+}
+
+// CollideCircle calculates the contact manifold of an oriented rectangle and a circle.
+// Returns false if the shapes do not overlap. Touching shapes result in a penetration depth of zero.
+func (o OBB2f) CollideCircle(circle Circle) (Manifold2f, bool) {
+	corners := o.Corners()
+	return collidePolygonCircle(corners[:], circle)
+}
+
+// CollideCircle calculates the contact manifold of two circles.
+// Returns false if the circles do not overlap. Touching circles result in a penetration depth of zero.
+func (c Circle) CollideCircle(other Circle) (Manifold2f, bool) {
+	diff := other.Center.Sub(c.Center)
+	radius := c.Radius + other.Radius
+	sqDist := diff.SquareLength()
+	if sqDist > radius*radius {
+		return Manifold2f{}, false
+	}
+	dist := math32.Sqrt(sqDist)
+	normal := Vec2f{1, 0} // concentric circles can be separated in any direction
+	if dist > 0 {
+		normal = diff.DivScalar(dist)
+	}
+	depth := radius - dist
+	return Manifold2f{
+		Normal: normal,
+		Depth:  depth,
+		Count:  1,
+		Points: [2]Vec2f{c.Center.Add(normal.MulScalar(c.Radius - depth/2))},
+		Depths: [2]float32{depth},
+	}, true
+}
+
+// collidePolygons calculates the contact manifold of two convex polygons in counter-clockwise order.
+func collidePolygons(a, b []Vec2f) (Manifold2f, bool) {
+	// Source: "Contact Manifolds" by Erin Catto, Game Developers Conference, 2007.
+	// The axis with the smallest penetration is found with the separating axis theorem.
+	// Its edge is the reference face. The most anti-parallel edge of the other polygon is the incident face,
+	// which is clipped against the reference face's side planes to obtain the contact points.
+	edgeA, sepA := maxSeparation(a, b)
+	if sepA > 0 {
+		return Manifold2f{}, false
+	}
+	edgeB, sepB := maxSeparation(b, a)
+	if sepB > 0 {
+		return Manifold2f{}, false
+	}
+
+	// prefer A as reference, so that results don't flip between both polygons due to rounding errors
+	ref, inc, edge, sep, flip := a, b, edgeA, sepA, false
+	if sepB > sepA*0.999 {
+		ref, inc, edge, sep, flip = b, a, edgeB, sepB, true
+	}
+	r1, r2 := ref[edge], ref[(edge+1)%len(ref)]
+	normal := edgeNormal(r1, r2)
+
+	incEdge, minDot := 0, float32(2)
+	for i := range inc {
+		if d := edgeNormal(inc[i], inc[(i+1)%len(inc)]).Dot(normal); d < minDot {
+			incEdge, minDot = i, d
+		}
+	}
+	i1, i2 := inc[incEdge], inc[(incEdge+1)%len(inc)]
+
+	tangent := r2.Sub(r1).Normalize()
+	var ok bool
+	if i1, i2, ok = clipSegment(i1, i2, tangent.Negate(), -tangent.Dot(r1)); !ok {
+		return Manifold2f{}, false
+	}
+	if i1, i2, ok = clipSegment(i1, i2, tangent, tangent.Dot(r2)); !ok {
+		return Manifold2f{}, false
+	}
+
+	m := Manifold2f{
+		Normal: normal,
+		Depth:  -sep,
+	}
+	for _, p := range [2]Vec2f{i1, i2} {
+		if s := normal.Dot(p.Sub(r1)); s <= 0 {
+			m.Points[m.Count] = p.Sub(normal.MulScalar(s / 2))
+			m.Depths[m.Count] = -s
+			m.Count++
+		}
+	}
+	if m.Count == 0 { // rounding errors
+		return Manifold2f{}, false
+	}
+	if flip {
+		m = m.Flip()
+	}
+	return m, true
+}
+
+// maxSeparation returns the edge of polygon a with the largest separation from polygon b.
+// The separation is negative if the polygons overlap along the edge's normal.
+func maxSeparation(a, b []Vec2f) (int, float32) {
+	bestEdge, bestSep := 0, math32.NegInfinity
+	for i, p := range a {
+		normal := edgeNormal(p, a[(i+1)%len(a)])
+		sep := math32.Infinity
+		for _, q := range b {
+			sep = math32.Min(sep, normal.Dot(q.Sub(p)))
+		}
+		if sep > bestSep {
+			bestEdge, bestSep = i, sep
+		}
+	}
+	return bestEdge, bestSep
+}
+
+// clipSegment clips the segment a-b, keeping the part where normal·p <= offset.
+// Returns false if the segment is outside.
+func clipSegment(a, b, normal Vec2f, offset float32) (Vec2f, Vec2f, bool) {
+	da := normal.Dot(a) - offset
+	db := normal.Dot(b) - offset
+	switch {
+	case da <= 0 && db <= 0:
+		return a, b, true
+	case da > 0 && db > 0:
+		return a, b, false
+	case da > 0:
+		return a.Lerp(b, da/(da-db)), b, true
+	default:
+		return a, a.Lerp(b, da/(da-db)), true
+	}
+}
+
+// collidePolygonCircle calculates the contact manifold of a convex polygon in counter-clockwise order and a circle.
+func collidePolygonCircle(poly []Vec2f, circle Circle) (Manifold2f, bool) {
+	// Source: "Box2D" by Erin Catto, b2CollidePolygonAndCircle
+	edge, sep := 0, math32.NegInfinity
+	for i, p := range poly {
+		s := edgeNormal(p, poly[(i+1)%len(poly)]).Dot(circle.Center.Sub(p))
+		if s > circle.Radius {
+			return Manifold2f{}, false
+		}
+		if s > sep {
+			edge, sep = i, s
+		}
+	}
+	a, b := poly[edge], poly[(edge+1)%len(poly)]
+
+	var normal, surface Vec2f // the polygon's surface point closest to the circle
+	var dist float32
+	if sep <= 0 { // the center is inside the polygon
+		normal = edgeNormal(a, b)
+		dist = sep
+		surface = circle.Center.Sub(normal.MulScalar(sep))
+	} else {
+		surface, _ = Segment2f{a, b}.ClosestPoint(circle.Center)
+		diff := circle.Center.Sub(surface)
+		dist = diff.Length()
+		if dist > circle.Radius {
+			return Manifold2f{}, false
+		}
+		normal = diff.DivScalar(dist)
+	}
+	depth := circle.Radius - dist
+	return Manifold2f{
+		Normal: normal,
+		Depth:  depth,
+		Count:  1,
+		Points: [2]Vec2f{surface.Sub(normal.MulScalar(depth / 2))},
+		Depths: [2]float32{depth},
+	}, true
+}
@@ -0,0 +1,222 @@
+package vmath
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManifold2f_Flip(t *testing.T) {
+	m := Manifold2f{Normal: Vec2f{1, 0}, Depth: 2, Count: 1, Points: [2]Vec2f{{1, 2}}, Depths: [2]float32{2}}
+	assert.Equal(t, Manifold2f{Normal: Vec2f{-1, 0}, Depth: 2, Count: 1, Points: [2]Vec2f{{1, 2}}, Depths: [2]float32{2}}, m.Flip())
+}
+
+func TestPolygon2f_CollideConvex(t *testing.T) {
+	square := Polygon2f{{0, 0}, {2, 0}, {2, 2}, {0, 2}}
+	other := Polygon2f{{1.5, 0.5}, {4, 0.5}, {4, 1.5}, {1.5, 1.5}}
+	m, ok := square.CollideConvex(other)
+	assert.True(t, ok)
+	AssertVec2f(t, Vec2f{1, 0}, m.Normal)
+	AssertFloat(t, 0.5, m.Depth)
+	assert.Equal(t, 2, m.Count)
+	AssertVec2f(t, Vec2f{1.75, 1.5}, m.Points[0])
+	AssertVec2f(t, Vec2f{1.75, 0.5}, m.Points[1])
+	AssertFloat(t, 0.5, m.Depths[0])
+	AssertFloat(t, 0.5, m.Depths[1])
+
+	// clockwise order and swapped roles
+	m, ok = other.Reverse().CollideConvex(square.Reverse())
+	assert.True(t, ok)
+	AssertVec2f(t, Vec2f{-1, 0}, m.Normal)
+	AssertFloat(t, 0.5, m.Depth)
+	assert.Equal(t, 2, m.Count)
+
+	// incident face is clipped by the reference face
+	wide := Polygon2f{{-1, 1.8}, {3, 1.8}, {3, 3}, {-1, 3}}
+	m, ok = square.CollideConvex(wide)
+	assert.True(t, ok)
+	AssertVec2f(t, Vec2f{0, 1}, m.Normal)
+	AssertFloat(t, 0.2, m.Depth)
+	assert.Equal(t, 2, m.Count)
+	AssertVec2f(t, Vec2f{0, 1.9}, m.Points[0])
+	AssertVec2f(t, Vec2f{2, 1.9}, m.Points[1])
+
+	// touching
+	m, ok = square.CollideConvex(Polygon2f{{2, 0}, {3, 0}, {3, 1}})
+	assert.True(t, ok)
+	AssertFloat(t, 0, m.Depth)
+
+	_, ok = square.CollideConvex(Polygon2f{{2.1, 0}, {3, 0}, {3, 1}})
+	assert.False(t, ok)
+	_, ok = square.CollideConvex(Polygon2f{{3, 1.5}, {3, 3}, {1.5, 3}}) // separated by the diagonal axis
+	assert.False(t, ok)
+}
+
+func TestPolygon2f_CollideCircle(t *testing.T) {
+	square := Polygon2f{{0, 0}, {2, 0}, {2, 2}, {0, 2}}
+	m, ok := square.CollideCircle(Circle{Vec2f{3, 1}, 1.5})
+	assert.True(t, ok)
+	AssertVec2f(t, Vec2f{1, 0}, m.Normal)
+	AssertFloat(t, 0.5, m.Depth)
+	assert.Equal(t, 1, m.Count)
+	AssertVec2f(t, Vec2f{1.75, 1}, m.Points[0])
+	AssertFloat(t, 0.5, m.Depths[0])
+
+	// corner region
+	m, ok = square.Reverse().CollideCircle(Circle{Vec2f{3, 3}, 1.5})
+	assert.True(t, ok)
+	AssertVec2f(t, Vec2f{1, 1}.Normalize(), m.Normal)
+	AssertFloat(t, 1.5-math.Sqrt2, m.Depth)
+
+	// center inside
+	m, ok = square.CollideCircle(Circle{Vec2f{1.8, 1}, 0.5})
+	assert.True(t, ok)
+	AssertVec2f(t, Vec2f{1, 0}, m.Normal)
+	AssertFloat(t, 0.7, m.Depth)
+	AssertVec2f(t, Vec2f{1.65, 1}, m.Points[0])
+
+	_, ok = square.CollideCircle(Circle{Vec2f{3, 3}, 1.4})
+	assert.False(t, ok)
+	_, ok = square.CollideCircle(Circle{Vec2f{5, 1}, 2.9})
+	assert.False(t, ok)
+}
+
+func TestRectf_CollideRectf(t *testing.T) {
+	r := Rectf{Vec2f{0, 0}, Vec2f{2, 2}}
+	m, ok := r.CollideRectf(Rectf{Vec2f{1.5, 0.5}, Vec2f{4, 1.5}})
+	assert.True(t, ok)
+	assert.Equal(t, Manifold2f{
+		Normal: Vec2f{1, 0},
+		Depth:  0.5,
+		Count:  2,
+		Points: [2]Vec2f{{1.75, 0.5}, {1.75, 1.5}},
+		Depths: [2]float32{0.5, 0.5},
+	}, m)
+
+	m, ok = r.CollideRectf(Rectf{Vec2f{-1, -3}, Vec2f{3, 0.5}})
+	assert.True(t, ok)
+	AssertVec2f(t, Vec2f{0, -1}, m.Normal)
+	AssertFloat(t, 0.5, m.Depth)
+	AssertVec2f(t, Vec2f{0, 0.25}, m.Points[0])
+	AssertVec2f(t, Vec2f{2, 0.25}, m.Points[1])
+
+	_, ok = r.CollideRectf(Rectf{Vec2f{2.5, 0}, Vec2f{3, 1}})
+	assert.False(t, ok)
+}
+
+func TestRectf_CollideRectf_Polygon(t *testing.T) {
+	points := randomPoints2f(4000, 1)
+	for i := 0; i < 1000; i++ {
+		r1 := RectfFromCorners(points[4*i], points[4*i+1])
+		r2 := RectfFromCorners(points[4*i+2], points[4*i+3])
+		m, ok := r1.CollideRectf(r2)
+		expected, expectedOk := r1.Polygon2f().CollideConvex(r2.Polygon2f())
+		assert.Equal(t, expectedOk, ok)
+		if !ok {
+			continue
+		}
+		AssertVec2f(t, expected.Normal, m.Normal)
+		AssertFloat(t, expected.Depth, m.Depth)
+		assert.Equal(t, expected.Count, m.Count)
+	}
+}
+
+func TestRectf_CollideCircle(t *testing.T) {
+	r := Rectf{Vec2f{0, 0}, Vec2f{2, 2}}
+	m, ok := r.CollideCircle(Circle{Vec2f{1, -0.5}, 1})
+	assert.True(t, ok)
+	AssertVec2f(t, Vec2f{0, -1}, m.Normal)
+	AssertFloat(t, 0.5, m.Depth)
+	AssertVec2f(t, Vec2f{1, 0.25}, m.Points[0])
+
+	_, ok = r.CollideCircle(Circle{Vec2f{1, -0.5}, 0.4})
+	assert.False(t, ok)
+}
+
+func TestOBB2f_CollideOBB2f(t *testing.T) {
+	a := OBB2f{Vec2f{0, 0}, Vec2f{1, 1}, 0}
+	b := OBB2f{Vec2f{2.2, 0}, Vec2f{1, 1}, pi / 4}
+	m, ok := a.CollideOBB2f(b)
+	assert.True(t, ok)
+	AssertVec2f(t, Vec2f{1, 0}, m.Normal)
+	AssertFloat(t, 1-(2.2-math.Sqrt2), m.Depth)
+	assert.Equal(t, 1, m.Count)
+	AssertVec2f(t, Vec2f{(1 + 2.2 - math.Sqrt2) / 2, 0}, m.Points[0])
+
+	m2, ok := b.CollideOBB2f(a)
+	assert.True(t, ok)
+	AssertVec2f(t, Vec2f{-1, 0}, m2.Normal)
+	AssertFloat(t, m.Depth, m2.Depth)
+
+	b.Center = Vec2f{2.5, 0}
+	_, ok = a.CollideOBB2f(b)
+	assert.False(t, ok)
+}
+
+func TestOBB2f_CollideOBB2f_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	randomOBB := func() OBB2f {
+		return OBB2f{
+			Center:      Vec2f{rnd.Float32()*6 - 3, rnd.Float32()*6 - 3},
+			HalfExtents: Vec2f{rnd.Float32()*2 + 0.1, rnd.Float32()*2 + 0.1},
+			Rotation:    rnd.Float32() * 2 * pi,
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		a, b := randomOBB(), randomOBB()
+		m, ok := a.CollideOBB2f(b)
+		pen, expectedOk := EPAPenetration2f(a, b)
+		assert.Equal(t, expectedOk, ok)
+		if !ok {
+			continue
+		}
+		assert.InDelta(t, pen.Depth, m.Depth, 1e-4)
+		assert.True(t, m.Count >= 1)
+		for c := 0; c < m.Count; c++ {
+			assert.True(t, m.Depths[c] <= m.Depth+1e-4)
+		}
+		// moving b along the normal separates the shapes
+		b.Center = b.Center.Add(m.Normal.MulScalar(m.Depth + 1e-3))
+		assert.False(t, GJKIntersects2f(a, b))
+	}
+}
+
+func TestOBB2f_CollideCircle(t *testing.T) {
+	o := OBB2f{Vec2f{0, 0}, Vec2f{2, 1}, pi / 2}
+	m, ok := o.CollideCircle(Circle{Vec2f{0, 3}, 1.5})
+	assert.True(t, ok)
+	AssertVec2f(t, Vec2f{0, 1}, m.Normal)
+	AssertFloat(t, 0.5, m.Depth)
+	AssertVec2f(t, Vec2f{0, 1.75}, m.Points[0])
+
+	_, ok = o.CollideCircle(Circle{Vec2f{2, 0}, 0.9})
+	assert.False(t, ok)
+}
+
+func TestCircle_CollideCircle(t *testing.T) {
+	c := Circle{Vec2f{0, 0}, 1}
+	m, ok := c.CollideCircle(Circle{Vec2f{1.5, 0}, 1})
+	assert.True(t, ok)
+	assert.Equal(t, Manifold2f{
+		Normal: Vec2f{1, 0},
+		Depth:  0.5,
+		Count:  1,
+		Points: [2]Vec2f{{0.75, 0}},
+		Depths: [2]float32{0.5},
+	}, m)
+
+	m, ok = c.CollideCircle(Circle{Vec2f{0, -2}, 2})
+	assert.True(t, ok)
+	AssertVec2f(t, Vec2f{0, -1}, m.Normal)
+	AssertFloat(t, 1, m.Depth)
+	AssertVec2f(t, Vec2f{0, -0.5}, m.Points[0])
+
+	m, ok = c.CollideCircle(Circle{Vec2f{0, 0}, 0.5})
+	assert.True(t, ok)
+	AssertFloat(t, 1.5, m.Depth)
+
+	_, ok = c.CollideCircle(Circle{Vec2f{2, 2}, 1})
+	assert.False(t, ok)
+}
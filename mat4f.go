@@ -215,6 +215,17 @@ func Mat4fFromZRotation(rad float32) Mat4f {
 		0, 0, 0, 1}
 }
 
+// Mat4fReflection returns a 4x4 matrix that mirrors points on the given plane.
+func Mat4fReflection(plane Plane) Mat4f {
+	plane = plane.Normalize()
+	n, d := plane.Normal, plane.D
+	return Mat4f{
+		1 - 2*n[0]*n[0], -2 * n[0] * n[1], -2 * n[0] * n[2], 0,
+		-2 * n[1] * n[0], 1 - 2*n[1]*n[1], -2 * n[1] * n[2], 0,
+		-2 * n[2] * n[0], -2 * n[2] * n[1], 1 - 2*n[2]*n[2], 0,
+		-2 * d * n[0], -2 * d * n[1], -2 * d * n[2], 1}
+}
+
 // Mat2f shrinks the matrix to 2x2.
 // The right columns and bottom rows are removed.
 func (m Mat4f) Mat2f() Mat2f {
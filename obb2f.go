@@ -0,0 +1,112 @@
+package vmath
+
+import (
+	"fmt"
+
+	"github.com/maja42/vmath/math32"
+)
+
+// OBB2f represents an oriented rectangle on the 2D plane.
+// The rectangle is defined by its center, its half-extents along its local axes and a rotation.
+type OBB2f struct {
+	Center      Vec2f
+	HalfExtents Vec2f
+	// Rotation is the counter-clockwise rotation around the center, in radians.
+	Rotation float32
+}
+
+// OBB2fFromRectf creates a new oriented rectangle with the same dimensions as the given axis-aligned rectangle.
+func OBB2fFromRectf(rect Rectf) OBB2f {
+	return OBB2f{
+		Center:      rect.Min.Add(rect.Max).MulScalar(0.5),
+		HalfExtents: rect.Size().MulScalar(0.5),
+	}
+}
+
+func (o OBB2f) String() string {
+	return fmt.Sprintf("OBB2f([%f x %f], [%f x %f], %f)",
+		o.Center[0], o.Center[1],
+		o.HalfExtents[0], o.HalfExtents[1],
+		o.Rotation)
+}
+
+// Axes returns the rectangle's normalized local X and Y axes.
+func (o OBB2f) Axes() [2]Vec2f {
+	sin, cos := math32.Sincos(o.Rotation)
+	return [2]Vec2f{{cos, sin}, {-sin, cos}}
+}
+
+// Size returns the rectangle's dimensions along its local axes.
+func (o OBB2f) Size() Vec2f {
+	return o.HalfExtents.MulScalar(2)
+}
+
+// Area returns the rectangle's area.
+func (o OBB2f) Area() float32 {
+	return 4 * o.HalfExtents[0] * o.HalfExtents[1]
+}
+
+// Corners returns all four corners of the rectangle in counter-clockwise order,
+// starting with the corner at the negative local X and Y axes.
+func (o OBB2f) Corners() [4]Vec2f {
+	h := o.HalfExtents
+	return [4]Vec2f{
+		o.ToWorld(Vec2f{-h[0], -h[1]}),
+		o.ToWorld(Vec2f{h[0], -h[1]}),
+		o.ToWorld(Vec2f{h[0], h[1]}),
+		o.ToWorld(Vec2f{-h[0], h[1]}),
+	}
+}
+
+// Polygon2f returns the rectangle's corners as polygon in counter-clockwise order.
+func (o OBB2f) Polygon2f() Polygon2f {
+	corners := o.Corners()
+	return corners[:]
+}
+
+// Rectf returns the smallest axis-aligned rectangle that contains the oriented rectangle.
+func (o OBB2f) Rectf() Rectf {
+	axes := o.Axes()
+	extents := axes[0].Abs().MulScalar(o.HalfExtents[0]).Add(axes[1].Abs().MulScalar(o.HalfExtents[1]))
+	return Rectf{
+		Min: o.Center.Sub(extents),
+		Max: o.Center.Add(extents),
+	}
+}
+
+// ToLocal converts a world-space position into the rectangle's local coordinate system,
+// where the rectangle is centered at the origin and aligned with the coordinate axes.
+func (o OBB2f) ToLocal(point Vec2f) Vec2f {
+	axes := o.Axes()
+	d := point.Sub(o.Center)
+	return Vec2f{d.Dot(axes[0]), d.Dot(axes[1])}
+}
+
+// ToWorld converts a position from the rectangle's local coordinate system into world-space.
+func (o OBB2f) ToWorld(point Vec2f) Vec2f {
+	axes := o.Axes()
+	return o.Center.Add(axes[0].MulScalar(point[0])).Add(axes[1].MulScalar(point[1]))
+}
+
+// ContainsPoint checks if a given point resides within the rectangle.
+// If the point is on an edge, it is also considered to be contained within the rectangle.
+func (o OBB2f) ContainsPoint(point Vec2f) bool {
+	local := o.ToLocal(point)
+	return math32.Abs(local[0]) <= o.HalfExtents[0] && math32.Abs(local[1]) <= o.HalfExtents[1]
+}
+
+// ClosestPoint returns the point within the rectangle that is closest to the given point.
+// If the point is contained within the rectangle, it is returned unchanged.
+func (o OBB2f) ClosestPoint(point Vec2f) Vec2f {
+	local := o.ToLocal(point)
+	for i, val := range local {
+		local[i] = Clampf(val, -o.HalfExtents[i], o.HalfExtents[i])
+	}
+	return o.ToWorld(local)
+}
+
+// PointDistance returns the distance between the rectangle and a point.
+// If the point is contained within the rectangle, 0 is returned.
+func (o OBB2f) PointDistance(point Vec2f) float32 {
+	return o.ClosestPoint(point).Distance(point)
+}
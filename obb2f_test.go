@@ -0,0 +1,75 @@
+package vmath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOBB2fFromRectf(t *testing.T) {
+	o := OBB2fFromRectf(Rectf{Vec2f{1, 2}, Vec2f{5, 4}})
+	assert.Equal(t, OBB2f{Vec2f{3, 3}, Vec2f{2, 1}, 0}, o)
+}
+
+func TestOBB2f_String(t *testing.T) {
+	o := OBB2f{Vec2f{1, 2}, Vec2f{3, 4}, 5}
+	assert.Equal(t, "OBB2f([1.000000 x 2.000000], [3.000000 x 4.000000], 5.000000)", o.String())
+}
+
+func TestOBB2f_Axes(t *testing.T) {
+	axes := OBB2f{Rotation: pi / 2}.Axes()
+	AssertVec2f(t, Vec2f{0, 1}, axes[0])
+	AssertVec2f(t, Vec2f{-1, 0}, axes[1])
+}
+
+func TestOBB2f_Size(t *testing.T) {
+	o := OBB2f{Vec2f{1, 2}, Vec2f{3, 4}, 1}
+	assert.Equal(t, Vec2f{6, 8}, o.Size())
+	assert.Equal(t, float32(48), o.Area())
+}
+
+func TestOBB2f_Corners(t *testing.T) {
+	o := OBB2f{Vec2f{1, 0}, Vec2f{2, 1}, pi / 2}
+	corners := o.Corners()
+	AssertVec2f(t, Vec2f{2, -2}, corners[0])
+	AssertVec2f(t, Vec2f{2, 2}, corners[1])
+	AssertVec2f(t, Vec2f{0, 2}, corners[2])
+	AssertVec2f(t, Vec2f{0, -2}, corners[3])
+	assert.True(t, o.Polygon2f().IsCounterClockwise())
+}
+
+func TestOBB2f_Rectf(t *testing.T) {
+	o := OBB2f{Vec2f{1, 0}, Vec2f{2, 1}, pi / 2}
+	r := o.Rectf()
+	AssertVec2f(t, Vec2f{0, -2}, r.Min)
+	AssertVec2f(t, Vec2f{2, 2}, r.Max)
+
+	o = OBB2f{Vec2f{0, 0}, Vec2f{1, 1}, pi / 4}
+	r = o.Rectf()
+	AssertVec2f(t, Vec2f{-1.41421356, -1.41421356}, r.Min)
+	AssertVec2f(t, Vec2f{1.41421356, 1.41421356}, r.Max)
+}
+
+func TestOBB2f_ToLocal(t *testing.T) {
+	o := OBB2f{Vec2f{1, 2}, Vec2f{2, 1}, 0.7}
+	for _, p := range randomPoints2f(20, 1) {
+		AssertVec2f(t, p, o.ToWorld(o.ToLocal(p)))
+	}
+	AssertVec2f(t, Vec2f{1, 0}, OBB2f{Vec2f{1, 2}, Vec2f{2, 1}, pi / 2}.ToLocal(Vec2f{1, 3}))
+}
+
+func TestOBB2f_ContainsPoint(t *testing.T) {
+	o := OBB2f{Vec2f{0, 0}, Vec2f{2, 0.5}, pi / 4}
+	assert.True(t, o.ContainsPoint(Vec2f{0, 0}))
+	assert.True(t, o.ContainsPoint(Vec2f{1, 1}))
+	assert.False(t, o.ContainsPoint(Vec2f{1, -1}))
+	assert.False(t, o.ContainsPoint(Vec2f{1.5, 1.5}))
+}
+
+func TestOBB2f_ClosestPoint(t *testing.T) {
+	o := OBB2f{Vec2f{0, 0}, Vec2f{2, 1}, pi / 2}
+	AssertVec2f(t, Vec2f{0.5, 0.5}, o.ClosestPoint(Vec2f{0.5, 0.5}))
+	AssertVec2f(t, Vec2f{1, 0.5}, o.ClosestPoint(Vec2f{3, 0.5}))
+	AssertVec2f(t, Vec2f{-1, 2}, o.ClosestPoint(Vec2f{-3, 4}))
+	AssertFloat(t, 2, o.PointDistance(Vec2f{3, 0.5}))
+}
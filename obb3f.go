@@ -0,0 +1,320 @@
+package vmath
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/maja42/vmath/math32"
+)
+
+// OBB3f represents an oriented bounding box in 3D space.
+// The box is defined by its center, its half-extents along its local axes and a rotation.
+type OBB3f struct {
+	Center      Vec3f
+	HalfExtents Vec3f
+	Rotation    Quat
+}
+
+// OBB3fFromBox3f creates a new oriented box with the same dimensions as the given axis-aligned box.
+func OBB3fFromBox3f(box Box3f) OBB3f {
+	return OBB3f{
+		Center:      box.Center(),
+		HalfExtents: box.HalfExtents(),
+		Rotation:    IdentQuat(),
+	}
+}
+
+// OBB3fFromPoints creates an oriented box that contains all given points.
+// The box's axes are aligned with the principal components of the point cloud,
+// which are found by calculating the eigenvectors of the points' covariance matrix.
+// The result is not necessarily the smallest possible box.
+// Since every point is weighted equally, the result is most reliable if the points are evenly distributed,
+// such as the vertices of a convex hull.
+// If no points are given, an empty box at the origin is returned.
+func OBB3fFromPoints(points []Vec3f) OBB3f {
+	// Source: "Real-Time Collision Detection" by Christer Ericson, chapter 4.4.3
+	if len(points) == 0 {
+		return OBB3f{Rotation: IdentQuat()}
+	}
+
+	// covariance matrix
+	var mean vec3d
+	for _, p := range points {
+		mean = mean.add(vec3d{float64(p[0]), float64(p[1]), float64(p[2])})
+	}
+	mean = mean.mul(1 / float64(len(points)))
+
+	var cov [3][3]float64
+	for _, p := range points {
+		d := vec3d{float64(p[0]), float64(p[1]), float64(p[2])}.sub(mean)
+		for i := 0; i < 3; i++ {
+			for j := i; j < 3; j++ {
+				cov[i][j] += d[i] * d[j]
+			}
+		}
+	}
+	cov[1][0], cov[2][0], cov[2][1] = cov[0][1], cov[0][2], cov[1][2]
+
+	axes := principalAxes(cov)
+
+	// project all points onto the axes to find the extents
+	var min, max Vec3f
+	for i := range min {
+		min[i], max[i] = math32.Infinity, math32.NegInfinity
+	}
+	for _, p := range points {
+		for i, axis := range axes {
+			d := p.Dot(axis)
+			min[i] = math32.Min(min[i], d)
+			max[i] = math32.Max(max[i], d)
+		}
+	}
+
+	var center Vec3f
+	for i, axis := range axes {
+		center = center.Add(axis.MulScalar((min[i] + max[i]) / 2))
+	}
+	return OBB3f{
+		Center:      center,
+		HalfExtents: max.Sub(min).MulScalar(0.5),
+		Rotation:    QuatFromMat3f(Mat3fFromCols(axes[0], axes[1], axes[2])).Normalize(),
+	}
+}
+
+// principalAxes returns the eigenvectors of a symmetric 3x3 matrix, sorted by descending eigenvalues.
+// The returned axes form a right-handed orthonormal basis.
+func principalAxes(a [3][3]float64) [3]Vec3f {
+	// Source: "Numerical Recipes in C", chapter 11.1 (cyclic Jacobi method)
+	v := [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+
+	for sweep := 0; sweep < 50; sweep++ {
+		off := a[0][1]*a[0][1] + a[0][2]*a[0][2] + a[1][2]*a[1][2]
+		diag := a[0][0]*a[0][0] + a[1][1]*a[1][1] + a[2][2]*a[2][2]
+		if off <= 1e-24*diag || off == 0 {
+			break
+		}
+		for p := 0; p < 2; p++ {
+			for q := p + 1; q < 3; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+				// calculate the Jacobi rotation that eliminates a[p][q]
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+
+				// a = Jᵀ * a * J
+				for k := 0; k < 3; k++ {
+					akp, akq := a[k][p], a[k][q]
+					a[k][p] = c*akp - s*akq
+					a[k][q] = s*akp + c*akq
+				}
+				for k := 0; k < 3; k++ {
+					apk, aqk := a[p][k], a[q][k]
+					a[p][k] = c*apk - s*aqk
+					a[q][k] = s*apk + c*aqk
+				}
+				// v = v * J
+				for k := 0; k < 3; k++ {
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p] = c*vkp - s*vkq
+					v[k][q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+
+	// sort eigenvectors (columns of v) by their eigenvalues
+	order := [3]int{0, 1, 2}
+	for i := 0; i < 3; i++ {
+		for j := i + 1; j < 3; j++ {
+			if a[order[j]][order[j]] > a[order[i]][order[i]] {
+				order[i], order[j] = order[j], order[i]
+			}
+		}
+	}
+	var axes [3]Vec3f
+	for i := 0; i < 2; i++ {
+		col := order[i]
+		axes[i] = Vec3f{float32(v[0][col]), float32(v[1][col]), float32(v[2][col])}.Normalize()
+	}
+	axes[2] = axes[0].Cross(axes[1]).Normalize()
+	return axes
+}
+
+func (o OBB3f) String() string {
+	return fmt.Sprintf("OBB3f([%f x %f x %f], [%f x %f x %f], %v)",
+		o.Center[0], o.Center[1], o.Center[2],
+		o.HalfExtents[0], o.HalfExtents[1], o.HalfExtents[2],
+		o.Rotation)
+}
+
+// Axes returns the box's normalized local X, Y and Z axes.
+func (o OBB3f) Axes() [3]Vec3f {
+	m := o.Rotation.Mat3f()
+	return [3]Vec3f{m.Col(0), m.Col(1), m.Col(2)}
+}
+
+// Size returns the box's dimensions along its local axes.
+func (o OBB3f) Size() Vec3f {
+	return o.HalfExtents.MulScalar(2)
+}
+
+// Volume returns the box's volume.
+func (o OBB3f) Volume() float32 {
+	return 8 * o.HalfExtents[0] * o.HalfExtents[1] * o.HalfExtents[2]
+}
+
+// Corners returns all eight corners of the box.
+// The order is the same as in Box3f.Corners, with respect to the box's local axes.
+func (o OBB3f) Corners() [8]Vec3f {
+	local := Box3f{o.HalfExtents.Negate(), o.HalfExtents}.Corners()
+	for i, c := range local {
+		local[i] = o.Rotation.RotateVec(c).Add(o.Center)
+	}
+	return local
+}
+
+// Box3f returns the smallest axis-aligned box that contains the oriented box.
+func (o OBB3f) Box3f() Box3f {
+	axes := o.Axes()
+	var extents Vec3f
+	for i, axis := range axes {
+		extents = extents.Add(axis.Abs().MulScalar(o.HalfExtents[i]))
+	}
+	return Box3f{
+		Min: o.Center.Sub(extents),
+		Max: o.Center.Add(extents),
+	}
+}
+
+// Mat4f returns a transformation matrix that maps the cube [-1, 1]³ onto the box.
+// This can be used for rendering the box.
+func (o OBB3f) Mat4f() Mat4f {
+	return Mat4fFromRotationTranslationScale(o.Rotation, o.Center, o.HalfExtents)
+}
+
+// ToLocal converts a world-space position into the box's local coordinate system,
+// where the box is centered at the origin and aligned with the coordinate axes.
+func (o OBB3f) ToLocal(point Vec3f) Vec3f {
+	return o.Rotation.Conjugate().RotateVec(point.Sub(o.Center))
+}
+
+// ToWorld converts a position from the box's local coordinate system into world-space.
+func (o OBB3f) ToWorld(point Vec3f) Vec3f {
+	return o.Rotation.RotateVec(point).Add(o.Center)
+}
+
+// ContainsPoint checks if a given point resides within the box.
+// If the point is on a face, it is also considered to be contained within the box.
+func (o OBB3f) ContainsPoint(point Vec3f) bool {
+	local := o.ToLocal(point)
+	for i, val := range local {
+		if math32.Abs(val) > o.HalfExtents[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ClosestPoint returns the point within the box that is closest to the given point.
+// If the point is contained within the box, it is returned unchanged.
+func (o OBB3f) ClosestPoint(point Vec3f) Vec3f {
+	// Source: "Real-Time Collision Detection" by Christer Ericson, chapter 5.1.4
+	local := o.ToLocal(point)
+	for i, val := range local {
+		local[i] = Clampf(val, -o.HalfExtents[i], o.HalfExtents[i])
+	}
+	return o.ToWorld(local)
+}
+
+// SquarePointDistance returns the squared distance between the box and a point.
+// If the point is contained within the box, 0 is returned.
+func (o OBB3f) SquarePointDistance(point Vec3f) float32 {
+	local := o.ToLocal(point)
+	return Box3f{o.HalfExtents.Negate(), o.HalfExtents}.SquarePointDistance(local)
+}
+
+// PointDistance returns the distance between the box and a point.
+// If the point is contained within the box, 0 is returned.
+func (o OBB3f) PointDistance(point Vec3f) float32 {
+	return math32.Sqrt(o.SquarePointDistance(point))
+}
+
+// IntersectRay calculates the first intersection of a ray with the box.
+// See Ray3f.IntersectBox for details.
+func (o OBB3f) IntersectRay(ray Ray3f) (RayHit, bool) {
+	inv := o.Rotation.Conjugate()
+	local := Ray3f{
+		Origin: inv.RotateVec(ray.Origin.Sub(o.Center)),
+		Dir:    inv.RotateVec(ray.Dir),
+	}
+	hit, ok := local.IntersectBox(o.HalfExtents.Negate(), o.HalfExtents)
+	if !ok {
+		return hit, false
+	}
+	hit.Point = ray.At(hit.Distance)
+	hit.Normal = o.Rotation.RotateVec(hit.Normal)
+	return hit, true
+}
+
+// Intersects checks if this box intersects another oriented box, using the separating axis theorem.
+// Touching boxes are also considered to intersect.
+func (o OBB3f) Intersects(other OBB3f) bool {
+	// Source: "Real-Time Collision Detection" by Christer Ericson, chapter 4.4.1
+	a := o.Axes()
+	b := other.Axes()
+	ea := o.HalfExtents
+	eb := other.HalfExtents
+
+	// rotation matrix expressing b in a's coordinate frame
+	var r, absR [3][3]float32
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			r[i][j] = a[i].Dot(b[j])
+			// add an epsilon to counteract arithmetic errors when two edges are parallel
+			// and their cross product is (near) null
+			absR[i][j] = math32.Abs(r[i][j]) + Epsilon
+		}
+	}
+
+	// translation vector in a's coordinate frame
+	tw := other.Center.Sub(o.Center)
+	t := Vec3f{tw.Dot(a[0]), tw.Dot(a[1]), tw.Dot(a[2])}
+
+	var ra, rb float32
+	// test axes L = A0, L = A1, L = A2
+	for i := 0; i < 3; i++ {
+		ra = ea[i]
+		rb = eb[0]*absR[i][0] + eb[1]*absR[i][1] + eb[2]*absR[i][2]
+		if math32.Abs(t[i]) > ra+rb {
+			return false
+		}
+	}
+	// test axes L = B0, L = B1, L = B2
+	for i := 0; i < 3; i++ {
+		ra = ea[0]*absR[0][i] + ea[1]*absR[1][i] + ea[2]*absR[2][i]
+		rb = eb[i]
+		if math32.Abs(t[0]*r[0][i]+t[1]*r[1][i]+t[2]*r[2][i]) > ra+rb {
+			return false
+		}
+	}
+	// test axes L = Ai x Bj
+	for i := 0; i < 3; i++ {
+		i1, i2 := (i+1)%3, (i+2)%3
+		for j := 0; j < 3; j++ {
+			j1, j2 := (j+1)%3, (j+2)%3
+			ra = ea[i1]*absR[i2][j] + ea[i2]*absR[i1][j]
+			rb = eb[j1]*absR[i][j2] + eb[j2]*absR[i][j1]
+			if math32.Abs(t[i2]*r[i1][j]-t[i1]*r[i2][j]) > ra+rb {
+				return false
+			}
+		}
+	}
+	// no separating axis found
+	return true
+}
@@ -0,0 +1,128 @@
+package vmath
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOBB3fFromBox3f(t *testing.T) {
+	o := OBB3fFromBox3f(Box3f{Vec3f{1, 2, 3}, Vec3f{3, 6, 9}})
+	assert.Equal(t, OBB3f{Vec3f{2, 4, 6}, Vec3f{1, 2, 3}, IdentQuat()}, o)
+	AssertFloat(t, 48, o.Volume())
+	assert.Equal(t, Box3f{Vec3f{1, 2, 3}, Vec3f{3, 6, 9}}, o.Box3f())
+}
+
+func TestOBB3fFromPoints(t *testing.T) {
+	rot := QuatFromAxisAngle(Vec3f{1, 1, 0}, 0.7)
+	box := OBB3f{Vec3f{5, -3, 2}, Vec3f{4, 2, 1}, rot}
+
+	points := box.Corners()
+	fit := OBB3fFromPoints(points[:])
+	AssertVec3f(t, box.Center, fit.Center)
+	AssertVec3f(t, box.HalfExtents, fit.HalfExtents)
+	for _, p := range points {
+		assert.True(t, fit.ContainsPoint(p.Add(fit.Center.Sub(p).MulScalar(0.0001))))
+	}
+
+	// axes are sorted by extent and form a right-handed coordinate system
+	axes := fit.Axes()
+	AssertVec3f(t, axes[2], axes[0].Cross(axes[1]))
+
+	// empty
+	assert.Equal(t, OBB3f{Rotation: IdentQuat()}, OBB3fFromPoints(nil))
+}
+
+func TestOBB3f_Axes(t *testing.T) {
+	o := OBB3f{Rotation: QuatFromAxisAngle(Vec3f{0, 0, 1}, deg90)}
+	axes := o.Axes()
+	AssertVec3f(t, Vec3f{0, 1, 0}, axes[0])
+	AssertVec3f(t, Vec3f{-1, 0, 0}, axes[1])
+	AssertVec3f(t, Vec3f{0, 0, 1}, axes[2])
+}
+
+func TestOBB3f_Box3f(t *testing.T) {
+	o := OBB3f{Vec3f{1, 1, 1}, Vec3f{1, 1, 1}, QuatFromAxisAngle(Vec3f{0, 0, 1}, deg90/2)}
+	box := o.Box3f()
+	sqrt2 := float32(math.Sqrt2)
+	AssertVec3f(t, Vec3f{1 - sqrt2, 1 - sqrt2, 0}, box.Min)
+	AssertVec3f(t, Vec3f{1 + sqrt2, 1 + sqrt2, 2}, box.Max)
+}
+
+func TestOBB3f_Mat4f(t *testing.T) {
+	o := OBB3f{Vec3f{5, -3, 2}, Vec3f{4, 2, 1}, QuatFromAxisAngle(Vec3f{1, 1, 0}, 0.7)}
+	m := o.Mat4f()
+
+	cube := Box3f{Vec3f{-1, -1, -1}, Vec3f{1, 1, 1}}.Corners()
+	corners := o.Corners()
+	for i, c := range cube {
+		AssertVec3f(t, corners[i], m.MulVec(c.Vec4f(1)).XYZ())
+	}
+}
+
+func TestOBB3f_ContainsPoint(t *testing.T) {
+	o := OBB3f{Vec3f{0, 0, 0}, Vec3f{2, 1, 1}, QuatFromAxisAngle(Vec3f{0, 0, 1}, deg90)}
+	assert.True(t, o.ContainsPoint(Vec3f{0, 0, 0}))
+	assert.True(t, o.ContainsPoint(Vec3f{0, 1.9, 0}))
+	assert.True(t, o.ContainsPoint(Vec3f{0.9, 0, 0}))
+	assert.False(t, o.ContainsPoint(Vec3f{1.9, 0, 0}))
+	assert.False(t, o.ContainsPoint(Vec3f{0, 2.1, 0}))
+}
+
+func TestOBB3f_ClosestPoint(t *testing.T) {
+	o := OBB3f{Vec3f{1, 0, 0}, Vec3f{2, 1, 1}, QuatFromAxisAngle(Vec3f{0, 0, 1}, deg90)}
+	AssertVec3f(t, Vec3f{1, 0, 0}, o.ClosestPoint(Vec3f{1, 0, 0}))
+	AssertVec3f(t, Vec3f{2, 2, 0}, o.ClosestPoint(Vec3f{5, 5, 0}))
+	AssertVec3f(t, Vec3f{0, 1, 1}, o.ClosestPoint(Vec3f{0, 1, 3}))
+
+	AssertFloat(t, 0, o.SquarePointDistance(Vec3f{1, 1, 0}))
+	AssertFloat(t, 9+9, o.SquarePointDistance(Vec3f{5, 5, 0}))
+	AssertFloat(t, 2, o.PointDistance(Vec3f{0, 1, 3}))
+}
+
+func TestOBB3f_IntersectRay(t *testing.T) {
+	o := OBB3f{Vec3f{0, 0, 0}, Vec3f{2, 1, 1}, QuatFromAxisAngle(Vec3f{0, 0, 1}, deg90)}
+
+	hit, ok := o.IntersectRay(Ray3f{Vec3f{0, 10, 0}, Vec3f{0, -1, 0}})
+	assert.True(t, ok)
+	AssertFloat(t, 8, hit.Distance)
+	AssertVec3f(t, Vec3f{0, 2, 0}, hit.Point)
+	AssertVec3f(t, Vec3f{0, 1, 0}, hit.Normal)
+
+	hit, ok = o.IntersectRay(Ray3f{Vec3f{10, 0, 0}, Vec3f{-1, 0, 0}})
+	assert.True(t, ok)
+	AssertFloat(t, 9, hit.Distance)
+	AssertVec3f(t, Vec3f{1, 0, 0}, hit.Normal)
+
+	_, ok = o.IntersectRay(Ray3f{Vec3f{1.5, 10, 0}, Vec3f{0, -1, 0}})
+	assert.False(t, ok)
+}
+
+func TestOBB3f_Intersects(t *testing.T) {
+	a := OBB3f{Vec3f{0, 0, 0}, Vec3f{1, 1, 1}, IdentQuat()}
+
+	b := OBB3f{Vec3f{2.3, 0, 0}, Vec3f{1, 1, 1}, IdentQuat()}
+	assert.False(t, a.Intersects(b))
+	assert.False(t, b.Intersects(a))
+
+	// rotating b by 45° makes its edge reach into a
+	b.Rotation = QuatFromAxisAngle(Vec3f{0, 0, 1}, deg90/2)
+	assert.True(t, a.Intersects(b))
+	assert.True(t, b.Intersects(a))
+
+	// touching
+	b = OBB3f{Vec3f{2, 0, 0}, Vec3f{1, 1, 1}, QuatFromAxisAngle(Vec3f{1, 0, 0}, deg90)}
+	assert.True(t, a.Intersects(b))
+
+	// both rotated; only separated along one of b's axes
+	a = OBB3f{Vec3f{0, 0, 0}, Vec3f{1, 1, 1}, QuatFromAxisAngle(Vec3f{0, 0, 1}, deg90/2)}
+	b = OBB3f{Vec3f{0, 2, 2}, Vec3f{1, 1, 1}, QuatFromAxisAngle(Vec3f{1, 0, 0}, deg90/2)}
+	assert.False(t, a.Intersects(b))
+	assert.False(t, b.Intersects(a))
+	b.Center = Vec3f{0, 1.5, 1.5}
+	assert.True(t, a.Intersects(b))
+
+	// identical
+	assert.True(t, a.Intersects(a))
+}
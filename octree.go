@@ -0,0 +1,320 @@
+package vmath
+
+// Octree is a loose octree, which accelerates spatial queries over points and items with box-shaped bounds.
+// Items are identified by integer IDs.
+// It works like Quadtree, but each node covers an octant of its parent's region.
+// Queries can run concurrently, but not while the tree is modified.
+type Octree struct {
+	tree   looseTree
+	cells  []octCell // cell of each node within the tree
+	bounds map[int]Box3f
+}
+
+// octCell is the region of an octree node.
+type octCell struct {
+	cell  Box3f // region covered by the node
+	loose Box3f // region that contains all items of the subtree
+}
+
+// octStackSize is the initial capacity of traversal stacks, which avoids allocations for reasonable tree depths.
+const octStackSize = 128
+
+// NewOctree creates an empty octree covering the given bounds.
+// Nodes are split if they contain more than capacity items, but never beyond the maximum depth.
+// The root node has a depth of 0.
+func NewOctree(bounds Box3f, maxDepth, capacity int) *Octree {
+	o := &Octree{
+		cells:  []octCell{newOctCell(bounds)},
+		bounds: make(map[int]Box3f),
+	}
+	o.tree = newLooseTree(8, maxDepth, capacity, o)
+	return o
+}
+
+func newOctCell(cell Box3f) octCell {
+	half := cell.Size().MulScalar(0.5)
+	return octCell{cell, Box3f{cell.Min.Sub(half), cell.Max.Add(half)}}
+}
+
+// Len returns the number of items within the tree.
+func (o *Octree) Len() int {
+	return len(o.bounds)
+}
+
+// Bounds returns the bounds of the item with the given ID.
+// Returns false if the item is not within the tree.
+func (o *Octree) Bounds(id int) (Box3f, bool) {
+	bounds, ok := o.bounds[id]
+	return bounds, ok
+}
+
+// Insert adds an item to the tree. If an item with the same ID already exists, it is moved.
+func (o *Octree) Insert(id int, bounds Box3f) {
+	if o.Move(id, bounds) {
+		return
+	}
+	o.bounds[id] = bounds
+	o.tree.insert(id)
+}
+
+// InsertPoint adds a point to the tree. If an item with the same ID already exists, it is moved.
+func (o *Octree) InsertPoint(id int, point Vec3f) {
+	o.Insert(id, Box3f{point, point})
+}
+
+// Remove removes the item with the given ID.
+// Returns false if the item is not within the tree.
+func (o *Octree) Remove(id int) bool {
+	if !o.tree.remove(id) {
+		return false
+	}
+	delete(o.bounds, id)
+	return true
+}
+
+// Move moves an item to new bounds.
+// Returns false if the item is not within the tree.
+func (o *Octree) Move(id int, bounds Box3f) bool {
+	if _, ok := o.bounds[id]; !ok {
+		return false
+	}
+	o.bounds[id] = bounds
+	o.tree.update(id)
+	return true
+}
+
+// MovePoint moves an item to a new point.
+// Returns false if the item is not within the tree.
+func (o *Octree) MovePoint(id int, point Vec3f) bool {
+	return o.Move(id, Box3f{point, point})
+}
+
+// child returns the child node that can store the item, or looseNull if the item needs to be stored in the node itself.
+func (o *Octree) child(node, id int) int {
+	children := o.tree.nodes[node].children
+	if children == looseNull {
+		return looseNull
+	}
+	bounds, cell := o.bounds[id], o.cells[node].cell
+	child := children
+	for dim := 0; dim < 3; dim++ {
+		if bounds.Min[dim]+bounds.Max[dim] >= cell.Min[dim]+cell.Max[dim] {
+			child += 1 << uint(dim)
+		}
+	}
+	if !o.cells[child].loose.ContainsBox3f(bounds) {
+		return looseNull
+	}
+	return child
+}
+
+// splitCell divides the node's cell into the octants of its children.
+func (o *Octree) splitCell(node, children int) {
+	for len(o.cells) < len(o.tree.nodes) {
+		o.cells = append(o.cells, octCell{})
+	}
+	parent := o.cells[node].cell
+	center := parent.Center()
+	for i := 0; i < 8; i++ {
+		cell := Box3f{parent.Min, center}
+		for dim := 0; dim < 3; dim++ {
+			if i&(1<<uint(dim)) != 0 {
+				cell.Min[dim], cell.Max[dim] = center[dim], parent.Max[dim]
+			}
+		}
+		o.cells[children+i] = newOctCell(cell)
+	}
+}
+
+// QueryBox appends the IDs of all items whose bounds intersect the given box to dst, and returns the result.
+func (o *Octree) QueryBox(box Box3f, dst []int) []int {
+	return o.query(dst, func(bounds Box3f) Containment {
+		if !box.Intersects(bounds) {
+			return Outside
+		}
+		if box.ContainsBox3f(bounds) {
+			return Inside
+		}
+		return Intersecting
+	})
+}
+
+// QuerySphere appends the IDs of all items whose bounds intersect the given sphere to dst, and returns the result.
+func (o *Octree) QuerySphere(sphere Sphere, dst []int) []int {
+	return o.query(dst, func(bounds Box3f) Containment {
+		if !sphere.IntersectsBox3f(bounds) {
+			return Outside
+		}
+		return Intersecting
+	})
+}
+
+// QueryFrustum appends the IDs of all items whose bounds are inside or intersect the given frustum to dst,
+// and returns the result. Like ViewFrustum.IntersectsBox, items close to the frustum might be included as well.
+func (o *Octree) QueryFrustum(frustum ViewFrustum, dst []int) []int {
+	return o.query(dst, frustum.IntersectsBox)
+}
+
+// query appends all items whose bounds are not outside according to the test function.
+// Nodes whose loose bounds are inside are added without testing their items.
+func (o *Octree) query(dst []int, test func(Box3f) Containment) []int {
+	var buf [octStackSize]int
+	stack := append(buf[:0], 0)
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		n := &o.tree.nodes[node]
+		if node != 0 { // the root can contain items outside of its bounds
+			switch test(o.cells[node].loose) {
+			case Outside:
+				continue
+			case Inside:
+				dst = o.tree.appendItems(dst, node)
+				continue
+			}
+		}
+		for _, id := range n.items {
+			if test(o.bounds[id]) != Outside {
+				dst = append(dst, id)
+			}
+		}
+		if n.children != looseNull {
+			for i := 0; i < 8; i++ {
+				if o.tree.nodes[n.children+i].count > 0 {
+					stack = append(stack, n.children+i)
+				}
+			}
+		}
+	}
+	return dst
+}
+
+// TraverseRay calls visit for all items whose bounds are hit by the ray, within the ray parameter range [0, maxDist].
+// The ray parameter where the ray enters the item's bounds is passed to the visitor.
+// Nodes are traversed in front-to-back order, which allows stopping early by returning false from visit.
+// Because the nodes' loose bounds overlap, items are only approximately ordered by their distance.
+func (o *Octree) TraverseRay(ray Ray3f, maxDist float32, visit func(id int, dist float32) bool) {
+	o.traverseRay(ray, &maxDist, visit)
+}
+
+// RayCast finds the item that is hit first by the ray, within the ray parameter range [0, maxDist].
+// The hit function tests the ray against an item and returns the ray parameter of the hit.
+// It is only called for items whose bounds are hit by the ray.
+// If it is nil, the bounds themselves are intersected with Box3f.IntersectRay.
+// Returns the item's ID and the hit's ray parameter, or false if no item is hit.
+func (o *Octree) RayCast(ray Ray3f, maxDist float32, hit func(id int) (float32, bool)) (int, float32, bool) {
+	bestID, found := 0, false
+	o.traverseRay(ray, &maxDist, func(id int, _ float32) bool {
+		if dist, ok := rayHitDistance(ray, o.bounds[id], id, hit); ok && dist <= maxDist {
+			bestID, maxDist, found = id, dist, true
+		}
+		return true
+	})
+	if !found {
+		return 0, 0, false
+	}
+	return bestID, maxDist, true
+}
+
+// traverseRay visits all items hit by the ray in front-to-back order of their nodes.
+// The maximum distance can be reduced by the visitor, to skip nodes and items that are farther away.
+func (o *Octree) traverseRay(ray Ray3f, maxDist *float32, visit func(id int, dist float32) bool) {
+	var invDir Vec3f
+	for i, d := range ray.Dir {
+		invDir[i] = 1 / d
+	}
+
+	var buf [octStackSize]int
+	stack := append(buf[:0], 0)
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		n := &o.tree.nodes[node]
+		if node != 0 {
+			if _, ok := rayBoxEntry(ray, invDir, o.cells[node].loose, *maxDist); !ok {
+				continue
+			}
+		}
+		for _, id := range n.items {
+			if dist, ok := rayBoxEntry(ray, invDir, o.bounds[id], *maxDist); ok {
+				if !visit(id, dist) {
+					return
+				}
+			}
+		}
+		if n.children == looseNull {
+			continue
+		}
+
+		// push the children in order of decreasing entry distance, so that the closest one is visited first
+		var order [8]int
+		var entries [8]float32
+		count := 0
+		for i := 0; i < 8; i++ {
+			child := n.children + i
+			if o.tree.nodes[child].count == 0 {
+				continue
+			}
+			entry, ok := rayBoxEntry(ray, invDir, o.cells[child].loose, *maxDist)
+			if !ok {
+				continue
+			}
+			j := count
+			for ; j > 0 && entries[j-1] < entry; j-- {
+				order[j], entries[j] = order[j-1], entries[j-1]
+			}
+			order[j], entries[j] = child, entry
+			count++
+		}
+		stack = append(stack, order[:count]...)
+	}
+}
+
+// KNearest appends the IDs of the k items closest to the given point to dst, and returns the result.
+// The distance of an item is the distance between its bounds and the point.
+// Items are ordered by increasing distance. If the tree contains less than k items, all items are returned.
+func (o *Octree) KNearest(point Vec3f, k int, dst []int) []int {
+	if k <= 0 {
+		return dst
+	}
+	var keyBuf [16]float32
+	res := kNearestResult{dst: dst, start: len(dst), keys: keyBuf[:0], k: k}
+
+	var buf [octStackSize]int
+	stack := append(buf[:0], 0)
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		n := &o.tree.nodes[node]
+		if node != 0 && !res.accepts(o.cells[node].loose.SquarePointDistance(point)) {
+			continue
+		}
+
+		for _, id := range n.items {
+			res.add(id, o.bounds[id].SquarePointDistance(point))
+		}
+
+		if n.children == looseNull {
+			continue
+		}
+		// push the children in order of decreasing distance, so that the closest one is visited first
+		var order [8]int
+		var childDists [8]float32
+		count := 0
+		for i := 0; i < 8; i++ {
+			child := n.children + i
+			if o.tree.nodes[child].count == 0 {
+				continue
+			}
+			d := o.cells[child].loose.SquarePointDistance(point)
+			j := count
+			for ; j > 0 && childDists[j-1] < d; j-- {
+				order[j], childDists[j] = order[j-1], childDists[j-1]
+			}
+			order[j], childDists[j] = child, d
+			count++
+		}
+		stack = append(stack, order[:count]...)
+	}
+	return res.dst
+}
@@ -0,0 +1,221 @@
+package vmath
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// assertValidOctree checks the tree's structure and that it contains exactly the given items.
+func assertValidOctree(t *testing.T, o *Octree, items map[int]Box3f) {
+	assert.Equal(t, len(items), o.Len())
+	tree := &o.tree
+	var check func(node int) int
+	check = func(node int) int {
+		n := tree.nodes[node]
+		count := len(n.items)
+		for _, id := range n.items {
+			assert.Equal(t, items[id], o.bounds[id])
+			assert.Equal(t, node, tree.items[id])
+			if node != 0 {
+				assert.True(t, o.cells[node].loose.ContainsBox3f(items[id]))
+			}
+			assert.Equal(t, looseNull, o.child(node, id)) // stored in the deepest possible node
+		}
+		if n.children == looseNull {
+			assert.True(t, n.depth == tree.maxDepth || len(n.items) <= tree.capacity)
+		} else {
+			for i := 0; i < 8; i++ {
+				c := tree.nodes[n.children+i]
+				assert.Equal(t, node, c.parent)
+				assert.Equal(t, n.depth+1, c.depth)
+				assert.True(t, o.cells[node].cell.ContainsBox3f(o.cells[n.children+i].cell))
+				count += check(n.children + i)
+			}
+			assert.True(t, count > tree.capacity)
+		}
+		assert.Equal(t, count, n.count)
+		return count
+	}
+	assert.Equal(t, len(items), check(0))
+}
+
+func TestOctree_InsertRemove(t *testing.T) {
+	o := NewOctree(Box3f{Vec3f{-10, -10, -10}, Vec3f{10, 10, 10}}, 5, 4)
+	items := make(map[int]Box3f)
+	for i, box := range randomBoxes3f(300, 1) {
+		o.Insert(i, box)
+		items[i] = box
+	}
+	for i, p := range randomPoints3f(300, 2) {
+		o.InsertPoint(300+i, p)
+		items[300+i] = Box3f{p, p}
+	}
+	// outside of the tree's bounds
+	o.Insert(1000, Box3f{Vec3f{20, 20, 20}, Vec3f{21, 21, 21}})
+	items[1000] = Box3f{Vec3f{20, 20, 20}, Vec3f{21, 21, 21}}
+	assertValidOctree(t, o, items)
+	assert.True(t, o.tree.nodes[0].children != looseNull)
+
+	for i := 0; i < 600; i += 2 {
+		assert.True(t, o.Remove(i))
+		delete(items, i)
+	}
+	assert.False(t, o.Remove(0))
+	assertValidOctree(t, o, items)
+
+	for i, box := range randomBoxes3f(300, 3) {
+		if i%2 == 1 {
+			assert.True(t, o.Move(i, box))
+		} else {
+			o.Insert(i, box)
+		}
+		items[i] = box
+	}
+	for i, p := range randomPoints3f(300, 4) {
+		if i%2 == 1 {
+			assert.True(t, o.MovePoint(300+i, p))
+			items[300+i] = Box3f{p, p}
+		}
+	}
+	assert.False(t, o.Move(-1, Box3f{}))
+	assertValidOctree(t, o, items)
+
+	box, ok := o.Bounds(1000)
+	assert.True(t, ok)
+	assert.Equal(t, items[1000], box)
+	_, ok = o.Bounds(-1)
+	assert.False(t, ok)
+
+	// nodes are merged when they become empty
+	for id := range items {
+		o.Remove(id)
+	}
+	assertValidOctree(t, o, nil)
+	assert.Equal(t, looseNull, o.tree.nodes[0].children)
+}
+
+func TestOctree_Query(t *testing.T) {
+	boxes := randomBoxes3f(1000, 5)
+	o := NewOctree(Box3f{Vec3f{-10, -10, -10}, Vec3f{10, 10, 10}}, 6, 8)
+	for i, box := range boxes {
+		o.Insert(i, box)
+	}
+
+	query := func(test func(Box3f) bool) []int {
+		var ids []int
+		for i, box := range boxes {
+			if test(box) {
+				ids = append(ids, i)
+			}
+		}
+		return ids
+	}
+	for _, box := range randomBoxes3f(50, 6) {
+		box = box.Grow(2)
+		assert.Equal(t, query(box.Intersects), sortedIDs(o.QueryBox(box, nil)))
+	}
+	assert.Len(t, o.QueryBox(Box3f{Vec3f{-20, -20, -20}, Vec3f{20, 20, 20}}, nil), len(boxes))
+
+	for _, center := range randomPoints3f(50, 7) {
+		sphere := Sphere{center, 3}
+		assert.Equal(t, query(sphere.IntersectsBox3f), sortedIDs(o.QuerySphere(sphere, nil)))
+	}
+
+	f := ViewFrustumFromMatrix(Frustum(-1, 1, -1, 1, 1, 10))
+	expected := query(func(box Box3f) bool { return f.IntersectsBox(box) != Outside })
+	assert.Equal(t, expected, sortedIDs(o.QueryFrustum(f, nil)))
+}
+
+func TestOctree_RayCast(t *testing.T) {
+	boxes := randomBoxes3f(1000, 8)
+	o := NewOctree(Box3f{Vec3f{-10, -10, -10}, Vec3f{10, 10, 10}}, 6, 8)
+	for i, box := range boxes {
+		o.Insert(i, box)
+	}
+	origins := randomPoints3f(50, 9)
+	targets := randomPoints3f(50, 10)
+	for i := range origins {
+		ray := Ray3f{origins[i].MulScalar(2), targets[i].Sub(origins[i].MulScalar(2)).Normalize()}
+
+		var expected []int
+		expectedID, expectedDist, expectedOk := 0, float32(100), false
+		for id, box := range boxes {
+			if hit, ok := box.IntersectRay(ray); ok && hit.Distance <= 100 {
+				expected = append(expected, id)
+				if hit.Distance < expectedDist {
+					expectedID, expectedDist, expectedOk = id, hit.Distance, true
+				}
+			}
+		}
+
+		id, dist, ok := o.RayCast(ray, 100, nil)
+		assert.Equal(t, expectedOk, ok)
+		if ok {
+			assert.Equal(t, expectedID, id)
+			AssertFloat(t, expectedDist, dist)
+		}
+
+		var visited []int
+		o.TraverseRay(ray, 100, func(id int, dist float32) bool {
+			visited = append(visited, id)
+			return true
+		})
+		assert.Equal(t, expected, sortedIDs(visited))
+
+		// stop early
+		visited = visited[:0]
+		o.TraverseRay(ray, 100, func(id int, dist float32) bool {
+			visited = append(visited, id)
+			return false
+		})
+		assert.True(t, len(visited) <= 1)
+	}
+
+	// custom hit test against spheres within the boxes
+	target := boxes[0].Center()
+	ray := Ray3f{Vec3f{-20, target[1], target[2]}, Vec3f{1, 0, 0}}
+	hit := func(id int) (float32, bool) {
+		h, ok := Sphere{boxes[id].Center(), 0.2}.IntersectRay(ray)
+		return h.Distance, ok
+	}
+	expectedID, expectedDist := 0, float32(100)
+	for id := range boxes {
+		if dist, ok := hit(id); ok && dist < expectedDist {
+			expectedID, expectedDist = id, dist
+		}
+	}
+	id, dist, ok := o.RayCast(ray, 100, hit)
+	assert.True(t, ok)
+	assert.Equal(t, expectedID, id)
+	AssertFloat(t, expectedDist, dist)
+}
+
+func TestOctree_KNearest(t *testing.T) {
+	points := randomPoints3f(1000, 11)
+	o := NewOctree(Box3f{Vec3f{-10, -10, -10}, Vec3f{10, 10, 10}}, 6, 8)
+	for i, p := range points {
+		o.InsertPoint(i, p)
+	}
+
+	for _, point := range randomPoints3f(50, 12) {
+		point = point.MulScalar(1.5)
+		dists := make([]float32, len(points))
+		for i, p := range points {
+			dists[i] = p.SquareDistance(point)
+		}
+		sort.Slice(dists, func(i, j int) bool { return dists[i] < dists[j] })
+
+		nearest := o.KNearest(point, 20, []int{-1})
+		assert.Len(t, nearest, 21)
+		assert.Equal(t, -1, nearest[0])
+		for i, id := range nearest[1:] {
+			assert.Equal(t, dists[i], points[id].SquareDistance(point))
+		}
+	}
+
+	assert.Len(t, o.KNearest(Vec3f{}, 2000, nil), len(points))
+	assert.Empty(t, o.KNearest(Vec3f{}, 0, nil))
+	assert.Empty(t, NewOctree(Box3f{}, 4, 4).KNearest(Vec3f{}, 3, nil))
+}
@@ -0,0 +1,302 @@
+package vmath
+
+import (
+	"math"
+
+	"github.com/maja42/vmath/math32"
+)
+
+// JoinType defines how the offset edges at polygon corners or polyline vertices are connected.
+type JoinType int
+
+const (
+	// JoinMiter extends the offset edges until they meet.
+	// Corners that would extend further than the miter limit are squared off.
+	JoinMiter JoinType = iota
+	// JoinRound connects the offset edges with a circular arc.
+	JoinRound
+	// JoinSquare squares off corners at the offset distance.
+	JoinSquare
+	// JoinBevel connects the ends of the offset edges directly.
+	JoinBevel
+)
+
+func (j JoinType) String() string {
+	switch j {
+	case JoinMiter:
+		return "Miter"
+	case JoinRound:
+		return "Round"
+	case JoinSquare:
+		return "Square"
+	case JoinBevel:
+		return "Bevel"
+	}
+	return "JoinType(invalid)"
+}
+
+// CapType defines how the ends of open polylines are closed.
+type CapType int
+
+const (
+	// CapButt ends the outline exactly at the polyline's endpoints.
+	CapButt CapType = iota
+	// CapSquare extends the outline beyond the polyline's endpoints by the offset distance.
+	CapSquare
+	// CapRound ends the outline with a half circle.
+	CapRound
+)
+
+func (c CapType) String() string {
+	switch c {
+	case CapButt:
+		return "Butt"
+	case CapSquare:
+		return "Square"
+	case CapRound:
+		return "Round"
+	}
+	return "CapType(invalid)"
+}
+
+// OffsetOptions configures polygon and polyline offsetting.
+type OffsetOptions struct {
+	// Join defines how corners are connected.
+	Join JoinType
+	// MiterLimit is the maximum distance of miter joins from the original vertex, in multiples of the offset distance.
+	// Sharper corners are squared off. Defaults to 2 if not set; values below 1 always square off corners.
+	MiterLimit float32
+	// ArcTolerance is the maximum distance between round joins and caps and the true arc.
+	// Defaults to 1% of the offset distance if not set.
+	ArcTolerance float32
+}
+
+// OffsetPolygons grows or shrinks polygons by the given distance.
+// Outer rings must be in counter-clockwise order and holes in clockwise order, like in PolygonWithHoles2f.
+// Positive distances grow the polygons and shrink their holes, negative distances shrink the polygons.
+// Overlapping results are merged. Rings with less than three vertices are ignored.
+func OffsetPolygons(rings []Polygon2f, delta float32, opts OffsetOptions) []PolygonWithHoles2f {
+	// Source: "Polygon Offsetting by Computing Winding Numbers" by Chen and McMains, 2005.
+	// Each ring is offset individually. Invalid loops at concave corners or collapsed parts have a non-positive
+	// winding number and are removed by the final union.
+	o := newOffsetter(delta, opts)
+	var res []Polygon2f
+	for _, ring := range rings {
+		if ring = removeDuplicates(ring, true); len(ring) < 3 {
+			continue
+		}
+		if delta == 0 {
+			res = append(res, ring)
+		} else {
+			res = append(res, o.offsetRing(ring))
+		}
+	}
+	return PolygonBoolean2f(res, nil, OpUnion, FillPositive)
+}
+
+// Offset grows or shrinks the polygon by the given distance.
+// See OffsetPolygons for details.
+func (p PolygonWithHoles2f) Offset(delta float32, opts OffsetOptions) []PolygonWithHoles2f {
+	return OffsetPolygons(append([]Polygon2f{p.Outer}, p.Holes...), delta, opts)
+}
+
+// OffsetPolyline returns the outline of an open polyline, in the given distance on both sides.
+// The result covers the area of a line with a width of 2*delta.
+// Single points result in a circle or square around them, depending on the cap type.
+func OffsetPolyline(points []Vec2f, delta float32, cap CapType, opts OffsetOptions) []PolygonWithHoles2f {
+	o := newOffsetter(math32.Abs(delta), opts)
+	points = removeDuplicates(points, false)
+	if len(points) == 0 || o.delta == 0 {
+		return nil
+	}
+
+	if len(points) == 1 {
+		p := points[0]
+		switch cap {
+		case CapSquare:
+			d := o.delta
+			o.out = append(o.out, p.Add(Vec2f{-d, -d}), p.Add(Vec2f{d, -d}), p.Add(Vec2f{d, d}), p.Add(Vec2f{-d, d}))
+		case CapRound:
+			o.arc(p, Vec2f{1, 0}, 2*math.Pi)
+			o.out = o.out[:len(o.out)-1] // the full circle repeats the first point
+		}
+	} else {
+		reversed := Polygon2f(points).Reverse()
+		o.offsetSide(points)
+		o.cap(reversed[1], points[len(points)-1], cap)
+		o.offsetSide(reversed)
+		o.cap(points[1], points[0], cap)
+	}
+	if len(o.out) < 3 {
+		return nil
+	}
+	return PolygonBoolean2f([]Polygon2f{o.out}, nil, OpUnion, FillPositive)
+}
+
+// offsetter creates the outline of offset rings and polylines.
+type offsetter struct {
+	delta      float32 // positive distances offset to the right hand side of the path
+	join       JoinType
+	miterLimit float32
+	arcStep    float32 // maximum angle between two vertices of round joins and caps
+	out        Polygon2f
+}
+
+func newOffsetter(delta float32, opts OffsetOptions) *offsetter {
+	o := &offsetter{
+		delta:      delta,
+		join:       opts.Join,
+		miterLimit: opts.MiterLimit,
+	}
+	if o.miterLimit <= 0 {
+		o.miterLimit = 2
+	}
+	o.arcStep = arcStep(math32.Abs(delta), opts.ArcTolerance)
+	return o
+}
+
+// offsetRing appends the outline of a closed ring and returns it.
+func (o *offsetter) offsetRing(ring []Vec2f) Polygon2f {
+	o.out = nil
+	prevNormal := edgeNormal(ring[len(ring)-1], ring[0])
+	for i, p := range ring {
+		normal := edgeNormal(p, ring[(i+1)%len(ring)])
+		o.offsetJoin(p, prevNormal, normal)
+		prevNormal = normal
+	}
+	return o.out
+}
+
+// offsetSide appends the offset of the polyline's inner vertices.
+func (o *offsetter) offsetSide(points []Vec2f) {
+	prevNormal := edgeNormal(points[0], points[1])
+	for i := 1; i < len(points)-1; i++ {
+		normal := edgeNormal(points[i], points[i+1])
+		o.offsetJoin(points[i], prevNormal, normal)
+		prevNormal = normal
+	}
+}
+
+// offsetJoin appends the offset of a vertex p, with the (right hand side) normals of the incoming and outgoing edges.
+func (o *offsetter) offsetJoin(p, n1, n2 Vec2f) {
+	// Source: "Clipper - an open source polygon clipping library" by Angus Johnson, ClipperOffset::OffsetPoint
+	sinA := n1.MagCross(n2)
+	cosA := n1.Dot(n2)
+	if cosA > 1-Epsilon { // (nearly) collinear edges
+		o.out = append(o.out, p.Add(n1.Add(n2).MulScalar(o.delta/(1+cosA))))
+		return
+	}
+	if sinA*o.delta < 0 { // concave corner; the resulting loop is removed afterwards
+		o.out = append(o.out, p.Add(n1.MulScalar(o.delta)), p, p.Add(n2.MulScalar(o.delta)))
+		return
+	}
+
+	switch o.join {
+	case JoinMiter:
+		// the miter's distance is delta/cos(angle/2), with 1+cosA = 2*cos²(angle/2)
+		if 1+cosA >= 2/(o.miterLimit*o.miterLimit) {
+			o.out = append(o.out, p.Add(n1.Add(n2).MulScalar(o.delta/(1+cosA))))
+			return
+		}
+		o.squareJoin(p, n1, n2)
+	case JoinRound:
+		angle := math32.Atan2(math32.Abs(sinA), cosA)
+		o.arc(p, n1.MulScalar(sign(o.delta)), angle*sign(o.delta))
+	case JoinBevel:
+		o.out = append(o.out, p.Add(n1.MulScalar(o.delta)), p.Add(n2.MulScalar(o.delta)))
+	default:
+		o.squareJoin(p, n1, n2)
+	}
+}
+
+// squareJoin appends a squared off corner, in offset distance from p.
+func (o *offsetter) squareJoin(p, n1, n2 Vec2f) {
+	dist := math32.Abs(o.delta)
+	n1 = n1.MulScalar(sign(o.delta))
+	n2 = n2.MulScalar(sign(o.delta))
+
+	bisector := n1.Add(n2)
+	if bisector.SquareLength() < Epsilon { // the path reverses its direction
+		bisector = n1.NormalVec(sign(o.delta) > 0)
+	}
+	bisector = bisector.Normalize()
+	perp := bisector.NormalVec(true)
+	if perp.Dot(n1) < 0 {
+		perp = perp.Negate()
+	}
+	// the corner's edges are perpendicular to the bisector; their half width is dist*tan(angle/4)
+	angle := math32.Atan2(math32.Abs(n1.MagCross(n2)), n1.Dot(n2))
+	perp = perp.MulScalar(math32.Tan(angle / 4))
+	o.out = append(o.out,
+		p.Add(bisector.Add(perp).MulScalar(dist)),
+		p.Add(bisector.Sub(perp).MulScalar(dist)))
+}
+
+// cap appends the end cap of a polyline, going from prev to the endpoint p.
+func (o *offsetter) cap(prev, p Vec2f, cap CapType) {
+	normal := edgeNormal(prev, p)
+	switch cap {
+	case CapButt:
+		o.out = append(o.out, p.Add(normal.MulScalar(o.delta)), p.Sub(normal.MulScalar(o.delta)))
+	case CapSquare:
+		dir := normal.NormalVec(true).MulScalar(o.delta)
+		normal = normal.MulScalar(o.delta)
+		o.out = append(o.out, p.Add(normal).Add(dir), p.Sub(normal).Add(dir))
+	case CapRound:
+		o.arc(p, normal, math.Pi)
+	}
+}
+
+// arc appends a circular arc around the center with the offset distance as radius.
+// The arc starts in the given (normalized) direction and covers the given signed angle.
+func (o *offsetter) arc(center, from Vec2f, angle float32) {
+	dist := math32.Abs(o.delta)
+	steps := int(math32.Ceil(math32.Abs(angle) / o.arcStep))
+	if steps < 1 {
+		steps = 1
+	}
+	for i := 0; i <= steps; i++ {
+		sin, cos := math32.Sincos(angle * float32(i) / float32(steps))
+		dir := Vec2f{from[0]*cos - from[1]*sin, from[0]*sin + from[1]*cos}
+		o.out = append(o.out, center.Add(dir.MulScalar(dist)))
+	}
+}
+
+// arcStep returns the maximum angle between two vertices of a circular arc,
+// so that the arc's chords deviate from it by less than the given tolerance.
+// The tolerance defaults to 1% of the radius if not set.
+func arcStep(radius, tolerance float32) float32 {
+	if tolerance <= 0 {
+		tolerance = radius * 0.01
+	}
+	// the maximum distance between an arc and its chord is r*(1-cos(angle/2))
+	return 2 * math32.Acos(1-math32.Min(tolerance/radius, 1))
+}
+
+// edgeNormal returns the normalized normal vector on the right hand side of the edge a->b.
+func edgeNormal(a, b Vec2f) Vec2f {
+	return b.Sub(a).NormalVec(false).Normalize()
+}
+
+// removeDuplicates returns the points without consecutive duplicates.
+// If closed is true, the last point is also removed if it equals the first one.
+func removeDuplicates(points []Vec2f, closed bool) []Vec2f {
+	res := make([]Vec2f, 0, len(points))
+	for _, p := range points {
+		if len(res) == 0 || p != res[len(res)-1] {
+			res = append(res, p)
+		}
+	}
+	if closed && len(res) > 1 && res[0] == res[len(res)-1] {
+		res = res[:len(res)-1]
+	}
+	return res
+}
+
+// sign returns -1 for negative values and 1 otherwise.
+func sign(v float32) float32 {
+	if v < 0 {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,125 @@
+package vmath
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOffsetPolygons(t *testing.T) {
+	square := Polygon2f{{0, 0}, {4, 0}, {4, 4}, {0, 4}}
+
+	res := OffsetPolygons([]Polygon2f{square}, 1, OffsetOptions{Join: JoinMiter})
+	assert.Equal(t, []PolygonWithHoles2f{{
+		Outer: Polygon2f{{-1, -1}, {5, -1}, {5, 5}, {-1, 5}},
+	}}, res)
+
+	res = OffsetPolygons([]Polygon2f{square}, -1, OffsetOptions{Join: JoinMiter})
+	assert.Equal(t, []PolygonWithHoles2f{{
+		Outer: Polygon2f{{1, 1}, {3, 1}, {3, 3}, {1, 3}},
+	}}, res)
+
+	// collapses completely
+	assert.Empty(t, OffsetPolygons([]Polygon2f{square}, -2.5, OffsetOptions{}))
+
+	// the miter limit squares off the corners
+	res = OffsetPolygons([]Polygon2f{square}, 1, OffsetOptions{Join: JoinMiter, MiterLimit: 1.2})
+	assert.Len(t, res[0].Outer, 8)
+	res = OffsetPolygons([]Polygon2f{square}, 1, OffsetOptions{Join: JoinSquare})
+	assert.Len(t, res[0].Outer, 8)
+	for _, v := range res[0].Outer {
+		// the corners are cut at the offset distance
+		assert.True(t, square.Edge(0).PointDistance(v) >= 1-1e-5 || square.Edge(1).PointDistance(v) >= 1-1e-5)
+	}
+	AssertFloat(t, 36-4*(math.Sqrt2-1)*(math.Sqrt2-1), res[0].Area())
+
+	res = OffsetPolygons([]Polygon2f{square}, 1, OffsetOptions{Join: JoinBevel})
+	assert.Len(t, res[0].Outer, 8)
+	AssertFloat(t, 34, res[0].Area())
+
+	// round joins approximate the rounded square
+	res = OffsetPolygons([]Polygon2f{square}, 1, OffsetOptions{Join: JoinRound, ArcTolerance: 0.001})
+	assert.InDelta(t, 16+4*4+math.Pi, res[0].Area(), 0.01)
+	for _, v := range res[0].Outer {
+		dist := float32(math.MaxFloat32)
+		for i := range square {
+			if d := square.Edge(i).PointDistance(v); d < dist {
+				dist = d
+			}
+		}
+		assert.InDelta(t, 1, dist, 1e-5)
+	}
+}
+
+func TestOffsetPolygons_Concave(t *testing.T) {
+	u := Polygon2f{{0, 0}, {6, 0}, {6, 6}, {4, 6}, {4, 2}, {2, 2}, {2, 6}, {0, 6}}
+
+	// the gap closes
+	res := OffsetPolygons([]Polygon2f{u}, 1, OffsetOptions{Join: JoinMiter})
+	assert.Equal(t, []PolygonWithHoles2f{{
+		Outer: Polygon2f{{-1, -1}, {7, -1}, {7, 7}, {-1, 7}},
+	}}, res)
+
+	// the legs separate
+	res = OffsetPolygons([]Polygon2f{u}, -0.5, OffsetOptions{Join: JoinMiter})
+	assert.Equal(t, []PolygonWithHoles2f{{
+		Outer: Polygon2f{{0.5, 0.5}, {5.5, 0.5}, {5.5, 5.5}, {4.5, 5.5}, {4.5, 1.5}, {1.5, 1.5}, {1.5, 5.5}, {0.5, 5.5}},
+	}}, res)
+	res = OffsetPolygons([]Polygon2f{u}, -0.75, OffsetOptions{Join: JoinMiter})
+	assert.Len(t, res, 1)
+	res = OffsetPolygons([]Polygon2f{{{0, 0}, {6, 0}, {6, 6}, {4, 6}, {4, 1}, {2, 1}, {2, 6}, {0, 6}}}, -0.75, OffsetOptions{})
+	assert.Len(t, res, 2)
+}
+
+func TestPolygonWithHoles2f_Offset(t *testing.T) {
+	p := PolygonWithHoles2f{
+		Outer: Polygon2f{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+		Holes: []Polygon2f{{{3, 3}, {3, 7}, {7, 7}, {7, 3}}},
+	}
+	res := p.Offset(1, OffsetOptions{})
+	assert.Equal(t, []PolygonWithHoles2f{{
+		Outer: Polygon2f{{-1, -1}, {11, -1}, {11, 11}, {-1, 11}},
+		Holes: []Polygon2f{{{4, 4}, {4, 6}, {6, 6}, {6, 4}}},
+	}}, res)
+
+	// the hole closes
+	res = p.Offset(2, OffsetOptions{})
+	assert.Len(t, res, 1)
+	assert.Empty(t, res[0].Holes)
+
+	// unchanged
+	assert.Equal(t, []PolygonWithHoles2f{p}, p.Offset(0, OffsetOptions{}))
+}
+
+func TestOffsetPolyline(t *testing.T) {
+	line := []Vec2f{{0, 0}, {4, 0}, {4, 4}}
+
+	res := OffsetPolyline(line, 1, CapButt, OffsetOptions{Join: JoinMiter})
+	assert.Equal(t, []PolygonWithHoles2f{{
+		Outer: Polygon2f{{0, -1}, {5, -1}, {5, 4}, {3, 4}, {3, 1}, {0, 1}},
+	}}, res)
+
+	res = OffsetPolyline(line, 1, CapSquare, OffsetOptions{Join: JoinMiter})
+	assert.Equal(t, []PolygonWithHoles2f{{
+		Outer: Polygon2f{{-1, -1}, {5, -1}, {5, 5}, {3, 5}, {3, 1}, {-1, 1}},
+	}}, res)
+
+	res = OffsetPolyline(line, 1, CapRound, OffsetOptions{Join: JoinRound, ArcTolerance: 0.001})
+	assert.InDelta(t, 8*2-1+math.Pi*5/4, res[0].Area(), 0.01) // the corner overlaps
+
+	// single points
+	assert.Nil(t, OffsetPolyline([]Vec2f{{1, 1}}, 1, CapButt, OffsetOptions{}))
+	res = OffsetPolyline([]Vec2f{{1, 1}, {1, 1}}, 1, CapSquare, OffsetOptions{})
+	assert.Equal(t, []PolygonWithHoles2f{{
+		Outer: Polygon2f{{0, 0}, {2, 0}, {2, 2}, {0, 2}},
+	}}, res)
+	res = OffsetPolyline([]Vec2f{{1, 1}}, 1, CapRound, OffsetOptions{ArcTolerance: 0.001})
+	assert.InDelta(t, math.Pi, res[0].Area(), 0.01)
+
+	// self-overlapping lines are merged
+	zigzag := []Vec2f{{0, 0}, {10, 0}, {0, 1}}
+	res = OffsetPolyline(zigzag, 1, CapButt, OffsetOptions{Join: JoinRound})
+	assert.Len(t, res, 1)
+	assert.Empty(t, res[0].Holes)
+}
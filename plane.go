@@ -0,0 +1,175 @@
+package vmath
+
+import (
+	"fmt"
+
+	"github.com/maja42/vmath/math32"
+)
+
+// Plane represents an infinite plane in 3D space.
+// All points X on the plane fulfill the equation Normal·X + D = 0.
+// The normal points towards the plane's positive half-space.
+type Plane struct {
+	Normal Vec3f
+	D      float32
+}
+
+// PlaneFromPointNormal creates a new plane that contains the given point and is perpendicular to the normal.
+// The normal is normalized.
+func PlaneFromPointNormal(point, normal Vec3f) Plane {
+	normal = normal.Normalize()
+	return Plane{
+		Normal: normal,
+		D:      -normal.Dot(point),
+	}
+}
+
+// PlaneFromPoints creates a new plane that contains all three points.
+// The normal is facing towards the side from which the points appear in counter-clockwise order.
+// If the points are collinear, the plane's normal is zero.
+func PlaneFromPoints(a, b, c Vec3f) Plane {
+	normal := b.Sub(a).Cross(c.Sub(a)).Normalize()
+	return Plane{
+		Normal: normal,
+		D:      -normal.Dot(a),
+	}
+}
+
+// PlaneFromVec4f creates a new plane from the plane equation coefficients (a, b, c, d),
+// with a*x + b*y + c*z + d = 0.
+func PlaneFromVec4f(v Vec4f) Plane {
+	return Plane{
+		Normal: v.XYZ(),
+		D:      v[3],
+	}
+}
+
+func (p Plane) String() string {
+	return fmt.Sprintf("Plane([%f x %f x %f], %f)", p.Normal[0], p.Normal[1], p.Normal[2], p.D)
+}
+
+// Vec4f returns the plane equation coefficients (a, b, c, d).
+func (p Plane) Vec4f() Vec4f {
+	return p.Normal.Vec4f(p.D)
+}
+
+// Normalize scales the plane equation so that the normal has a length of 1.
+// If the normal's length is zero, the plane is returned unchanged.
+func (p Plane) Normalize() Plane {
+	length := p.Normal.Length()
+	if Equalf(length, 0) {
+		return p
+	}
+	return Plane{
+		Normal: p.Normal.DivScalar(length),
+		D:      p.D / length,
+	}
+}
+
+// SignedDistance returns the signed distance between the plane and a point.
+// The distance is positive if the point is in front of the plane (on the side the normal points to).
+// The plane must be normalized, otherwise the distance is scaled by the normal's length.
+func (p Plane) SignedDistance(point Vec3f) float32 {
+	return p.Normal.Dot(point) + p.D
+}
+
+// Point returns the point on the plane that is closest to the origin.
+func (p Plane) Point() Vec3f {
+	return p.Normal.MulScalar(-p.D / p.Normal.SquareLength())
+}
+
+// Flip returns the same plane with an inverted normal.
+func (p Plane) Flip() Plane {
+	return Plane{
+		Normal: p.Normal.Negate(),
+		D:      -p.D,
+	}
+}
+
+// Distance returns the absolute distance between the plane and a point.
+// The plane must be normalized, otherwise the distance is scaled by the normal's length.
+func (p Plane) Distance(point Vec3f) float32 {
+	return math32.Abs(p.SignedDistance(point))
+}
+
+// ProjectPoint returns the orthogonal projection of a point onto the plane.
+// This is the point on the plane that is closest to the given point.
+func (p Plane) ProjectPoint(point Vec3f) Vec3f {
+	s := p.SignedDistance(point) / p.Normal.SquareLength()
+	return point.Sub(p.Normal.MulScalar(s))
+}
+
+// ReflectPoint mirrors a point on the plane.
+func (p Plane) ReflectPoint(point Vec3f) Vec3f {
+	s := 2 * p.SignedDistance(point) / p.Normal.SquareLength()
+	return point.Sub(p.Normal.MulScalar(s))
+}
+
+// ReflectVec mirrors a direction vector on the plane.
+func (p Plane) ReflectVec(v Vec3f) Vec3f {
+	s := 2 * p.Normal.Dot(v) / p.Normal.SquareLength()
+	return v.Sub(p.Normal.MulScalar(s))
+}
+
+// IntersectLine calculates the intersection with the infinite line passing through a and b.
+// Returns the intersection point and its line parameter t, with point = a + t*(b-a).
+// If t is in range [0, 1], the line segment between a and b intersects the plane.
+// Returns false if the line is parallel to the plane.
+func (p Plane) IntersectLine(a, b Vec3f) (Vec3f, float32, bool) {
+	// Source: "Real-Time Collision Detection" by Christer Ericson, chapter 5.3.1
+	ab := b.Sub(a)
+	denom := p.Normal.Dot(ab)
+	if Equalf(denom, 0) {
+		return Vec3f{}, 0, false
+	}
+	t := -p.SignedDistance(a) / denom
+	return a.Add(ab.MulScalar(t)), t, true
+}
+
+// IntersectRay calculates the intersection with a ray.
+// See Ray3f.IntersectPlane for details.
+func (p Plane) IntersectRay(ray Ray3f) (RayHit, bool) {
+	return ray.IntersectPlane(p.Point(), p.Normal)
+}
+
+// IntersectPlane calculates the line where two planes intersect.
+// Returns a point on the line and the line's (normalized) direction.
+// Returns false if the planes are parallel.
+func (p Plane) IntersectPlane(other Plane) (Vec3f, Vec3f, bool) {
+	// Source: "Real-Time Collision Detection" by Christer Ericson, chapter 5.4.4
+	dir := p.Normal.Cross(other.Normal)
+	denom := dir.SquareLength()
+	if Equalf(denom, 0) {
+		return Vec3f{}, Vec3f{}, false
+	}
+	point := p.Normal.MulScalar(other.D).Sub(other.Normal.MulScalar(p.D)).Cross(dir).DivScalar(denom)
+	return point, dir.Normalize(), true
+}
+
+// Transform applies a transformation matrix to the plane.
+// The plane is transformed with the matrix's inverse transpose and normalized afterwards.
+// If the matrix cannot be inverted (singular), the original plane and false is returned.
+func (p Plane) Transform(m Mat4f) (Plane, bool) {
+	invT, ok := m.InverseTranspose()
+	if !ok {
+		return p, false
+	}
+	return PlaneFromVec4f(invT.MulVec(p.Vec4f())).Normalize(), true
+}
+
+// IntersectPlanes returns the point where the three planes intersect.
+// Returns false if two or more planes are parallel.
+func IntersectPlanes(p1, p2, p3 Plane) (Vec3f, bool) {
+	// Source: "Real-Time Collision Detection" by Christer Ericson, chapter 5.4.5
+	n23 := p2.Normal.Cross(p3.Normal)
+	denom := p1.Normal.Dot(n23)
+	if Equalf(denom, 0) {
+		return Vec3f{}, false
+	}
+	n31 := p3.Normal.Cross(p1.Normal)
+	n12 := p1.Normal.Cross(p2.Normal)
+	point := n23.MulScalar(-p1.D).
+		Add(n31.MulScalar(-p2.D)).
+		Add(n12.MulScalar(-p3.D))
+	return point.DivScalar(denom), true
+}
@@ -0,0 +1,170 @@
+package vmath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaneFromPointNormal(t *testing.T) {
+	p := PlaneFromPointNormal(Vec3f{3, 2, 1}, Vec3f{0, 5, 0})
+	AssertVec3f(t, Vec3f{0, 1, 0}, p.Normal)
+	AssertFloat(t, -2, p.D)
+}
+
+func TestPlaneFromPoints(t *testing.T) {
+	p := PlaneFromPoints(Vec3f{0, 0, 3}, Vec3f{1, 0, 3}, Vec3f{0, 1, 3})
+	AssertVec3f(t, Vec3f{0, 0, 1}, p.Normal)
+	AssertFloat(t, -3, p.D)
+
+	// clockwise
+	p = PlaneFromPoints(Vec3f{0, 0, 3}, Vec3f{0, 1, 3}, Vec3f{1, 0, 3})
+	AssertVec3f(t, Vec3f{0, 0, -1}, p.Normal)
+	AssertFloat(t, 3, p.D)
+}
+
+func TestPlane_String(t *testing.T) {
+	p := Plane{Vec3f{0, 1, 0}, -2}
+	assert.Equal(t, "Plane([0.000000 x 1.000000 x 0.000000], -2.000000)", p.String())
+}
+
+func TestPlane_Normalize(t *testing.T) {
+	p := Plane{Vec3f{0, 4, 0}, -8}.Normalize()
+	AssertVec3f(t, Vec3f{0, 1, 0}, p.Normal)
+	AssertFloat(t, -2, p.D)
+
+	assert.Equal(t, Plane{}, Plane{}.Normalize())
+}
+
+func TestPlane_Vec4f(t *testing.T) {
+	p := Plane{Vec3f{1, 2, 3}, 4}
+	assert.Equal(t, Vec4f{1, 2, 3, 4}, p.Vec4f())
+	assert.Equal(t, p, PlaneFromVec4f(p.Vec4f()))
+}
+
+func TestPlane_Distance(t *testing.T) {
+	p := PlaneFromPointNormal(Vec3f{0, 2, 0}, Vec3f{0, 1, 0})
+	AssertFloat(t, 3, p.SignedDistance(Vec3f{7, 5, -3}))
+	AssertFloat(t, -3, p.SignedDistance(Vec3f{7, -1, -3}))
+	AssertFloat(t, 0, p.SignedDistance(Vec3f{7, 2, -3}))
+	AssertFloat(t, 3, p.Distance(Vec3f{7, -1, -3}))
+}
+
+func TestPlane_Point(t *testing.T) {
+	AssertVec3f(t, Vec3f{0, 2, 0}, PlaneFromPointNormal(Vec3f{5, 2, 5}, Vec3f{0, 1, 0}).Point())
+	AssertVec3f(t, Vec3f{0, 2, 0}, Plane{Vec3f{0, 3, 0}, -6}.Point())
+}
+
+func TestPlane_Flip(t *testing.T) {
+	p := PlaneFromPointNormal(Vec3f{0, 2, 0}, Vec3f{0, 1, 0}).Flip()
+	AssertVec3f(t, Vec3f{0, -1, 0}, p.Normal)
+	AssertFloat(t, -3, p.SignedDistance(Vec3f{7, 5, -3}))
+}
+
+func TestPlane_ProjectPoint(t *testing.T) {
+	p := PlaneFromPointNormal(Vec3f{0, 2, 0}, Vec3f{0, 1, 0})
+	AssertVec3f(t, Vec3f{7, 2, -3}, p.ProjectPoint(Vec3f{7, 5, -3}))
+	AssertVec3f(t, Vec3f{7, 2, -3}, p.ProjectPoint(Vec3f{7, -5, -3}))
+
+	// non-normalized plane
+	p = Plane{Vec3f{0, 0, 2}, -2}
+	AssertVec3f(t, Vec3f{1, 1, 1}, p.ProjectPoint(Vec3f{1, 1, 5}))
+}
+
+func TestPlane_Reflect(t *testing.T) {
+	p := PlaneFromPointNormal(Vec3f{0, 2, 0}, Vec3f{0, 1, 0})
+	AssertVec3f(t, Vec3f{7, -1, -3}, p.ReflectPoint(Vec3f{7, 5, -3}))
+	AssertVec3f(t, Vec3f{7, 2, -3}, p.ReflectPoint(Vec3f{7, 2, -3}))
+	AssertVec3f(t, Vec3f{1, 1, 0}, p.ReflectVec(Vec3f{1, -1, 0}))
+}
+
+func TestPlane_IntersectLine(t *testing.T) {
+	p := PlaneFromPointNormal(Vec3f{0, 2, 0}, Vec3f{0, 1, 0})
+
+	point, tl, ok := p.IntersectLine(Vec3f{1, 0, 1}, Vec3f{1, 4, 1})
+	assert.True(t, ok)
+	AssertVec3f(t, Vec3f{1, 2, 1}, point)
+	AssertFloat(t, 0.5, tl)
+
+	// outside of the segment
+	point, tl, ok = p.IntersectLine(Vec3f{0, 4, 0}, Vec3f{2, 6, 0})
+	assert.True(t, ok)
+	AssertVec3f(t, Vec3f{-2, 2, 0}, point)
+	AssertFloat(t, -1, tl)
+
+	_, _, ok = p.IntersectLine(Vec3f{0, 4, 0}, Vec3f{2, 4, 0})
+	assert.False(t, ok)
+}
+
+func TestPlane_IntersectRay(t *testing.T) {
+	p := PlaneFromPointNormal(Vec3f{0, 2, 0}, Vec3f{0, 1, 0})
+	hit, ok := p.IntersectRay(Ray3f{Vec3f{1, 5, 1}, Vec3f{0, -1, 0}})
+	assert.True(t, ok)
+	AssertFloat(t, 3, hit.Distance)
+	AssertVec3f(t, Vec3f{1, 2, 1}, hit.Point)
+}
+
+func TestPlane_IntersectPlane(t *testing.T) {
+	p1 := PlaneFromPointNormal(Vec3f{0, 2, 0}, Vec3f{0, 1, 0})
+	p2 := PlaneFromPointNormal(Vec3f{3, 0, 0}, Vec3f{1, 0, 0})
+
+	point, dir, ok := p1.IntersectPlane(p2)
+	assert.True(t, ok)
+	AssertFloat(t, 0, p1.SignedDistance(point))
+	AssertFloat(t, 0, p2.SignedDistance(point))
+	AssertVec3f(t, Vec3f{3, 2, 0}, point)
+	AssertVec3f(t, Vec3f{0, 0, -1}, dir)
+
+	_, _, ok = p1.IntersectPlane(PlaneFromPointNormal(Vec3f{0, 5, 0}, Vec3f{0, -1, 0}))
+	assert.False(t, ok)
+}
+
+func TestIntersectPlanes(t *testing.T) {
+	p1 := PlaneFromPointNormal(Vec3f{1, 2, 3}, Vec3f{1, 0, 0})
+	p2 := PlaneFromPointNormal(Vec3f{1, 2, 3}, Vec3f{1, 1, 0})
+	p3 := PlaneFromPointNormal(Vec3f{1, 2, 3}, Vec3f{0, 1, 1})
+
+	point, ok := IntersectPlanes(p1, p2, p3)
+	assert.True(t, ok)
+	AssertVec3f(t, Vec3f{1, 2, 3}, point)
+
+	_, ok = IntersectPlanes(p1, p2, PlaneFromPointNormal(Vec3f{5, 5, 5}, Vec3f{-1, 0, 0}))
+	assert.False(t, ok)
+}
+
+func TestPlane_Transform(t *testing.T) {
+	p := PlaneFromPointNormal(Vec3f{0, 2, 0}, Vec3f{0, 1, 0})
+
+	// translation
+	tp, ok := p.Transform(Mat4fFromTranslation(Vec3f{5, 3, 1}))
+	assert.True(t, ok)
+	AssertVec3f(t, Vec3f{0, 1, 0}, tp.Normal)
+	AssertFloat(t, -5, tp.D)
+
+	// non-uniform scaling keeps the normal perpendicular to the plane
+	p = PlaneFromPoints(Vec3f{1, 0, 0}, Vec3f{0, 1, 0}, Vec3f{0, 0, 1})
+	m := Mat4fFromScaling(Vec3f{2, 1, 1})
+	tp, ok = p.Transform(m)
+	assert.True(t, ok)
+	expected := PlaneFromPoints(Vec3f{2, 0, 0}, Vec3f{0, 1, 0}, Vec3f{0, 0, 1})
+	AssertVec3f(t, expected.Normal, tp.Normal)
+	AssertFloat(t, expected.D, tp.D)
+
+	// singular matrix
+	_, ok = p.Transform(Mat4fFromScaling(Vec3f{0, 1, 1}))
+	assert.False(t, ok)
+}
+
+func TestMat4fReflection(t *testing.T) {
+	p := PlaneFromPointNormal(Vec3f{1, 2, 3}, Vec3f{1, 1, 0})
+	m := Mat4fReflection(p)
+
+	for _, point := range []Vec3f{{0, 0, 0}, {5, -2, 7}, {1, 2, 3}} {
+		AssertVec3f(t, p.ReflectPoint(point), m.MulVec(point.Vec4f(1)).XYZ())
+	}
+	// mirroring twice results in the identity
+	mm := m.Mul(m)
+	for i, v := range Ident4f() {
+		AssertFloat(t, v, mm[i])
+	}
+}
@@ -0,0 +1,299 @@
+package vmath
+
+import (
+	"math"
+
+	"github.com/maja42/vmath/math32"
+)
+
+// Polygon2f represents a closed polygon on the 2D plane.
+// The last vertex is implicitly connected with the first one and must not be repeated.
+type Polygon2f []Vec2f
+
+// Edge returns the polygon's i-th edge, going from vertex i to vertex i+1.
+func (p Polygon2f) Edge(i int) Segment2f {
+	return Segment2f{p[i], p[(i+1)%len(p)]}
+}
+
+// SignedArea returns the polygon's signed area.
+// The area is positive if the vertices are in counter-clockwise order, and negative otherwise.
+// Self-intersecting polygons result in the difference of their parts' areas.
+func (p Polygon2f) SignedArea() float32 {
+	// Source: https://en.wikipedia.org/wiki/Shoelace_formula
+	var area float32
+	for i := range p {
+		area += p[i].MagCross(p[(i+1)%len(p)])
+	}
+	return area / 2
+}
+
+// Area returns the polygon's area.
+func (p Polygon2f) Area() float32 {
+	return math32.Abs(p.SignedArea())
+}
+
+// IsCounterClockwise returns true if the polygon's vertices are in counter-clockwise order.
+func (p Polygon2f) IsCounterClockwise() bool {
+	return p.SignedArea() > 0
+}
+
+// Reverse returns a copy of the polygon with reversed vertex order.
+func (p Polygon2f) Reverse() Polygon2f {
+	res := make(Polygon2f, len(p))
+	for i, v := range p {
+		res[len(p)-1-i] = v
+	}
+	return res
+}
+
+// Centroid returns the polygon's center of mass.
+// If the polygon has no area, the average of all vertices is returned.
+func (p Polygon2f) Centroid() Vec2f {
+	// Source: https://en.wikipedia.org/wiki/Centroid#Of_a_polygon
+	if len(p) == 0 {
+		return Vec2f{}
+	}
+	// translate the polygon to improve precision for polygons far away from the origin
+	origin := p[0]
+	var sum Vec2f
+	var area float32
+	for i := range p {
+		a := p[i].Sub(origin)
+		b := p[(i+1)%len(p)].Sub(origin)
+		cross := a.MagCross(b)
+		area += cross
+		sum = sum.Add(a.Add(b).MulScalar(cross))
+	}
+	if area == 0 {
+		var avg Vec2f
+		for _, v := range p {
+			avg = avg.Add(v)
+		}
+		return avg.DivScalar(float32(len(p)))
+	}
+	return sum.DivScalar(3 * area).Add(origin)
+}
+
+// Perimeter returns the length of the polygon's outline.
+func (p Polygon2f) Perimeter() float32 {
+	var length float32
+	for i := range p {
+		length += p[i].Distance(p[(i+1)%len(p)])
+	}
+	return length
+}
+
+// Bounds returns the smallest axis-aligned rectangle that contains the polygon.
+// If the polygon has no vertices, an empty rectangle at the origin is returned.
+func (p Polygon2f) Bounds() Rectf {
+	if len(p) == 0 {
+		return Rectf{}
+	}
+	bounds := Rectf{p[0], p[0]}
+	for _, v := range p[1:] {
+		bounds.Min[0] = math32.Min(bounds.Min[0], v[0])
+		bounds.Min[1] = math32.Min(bounds.Min[1], v[1])
+		bounds.Max[0] = math32.Max(bounds.Max[0], v[0])
+		bounds.Max[1] = math32.Max(bounds.Max[1], v[1])
+	}
+	return bounds
+}
+
+// IsConvex returns true if the polygon is convex.
+// Collinear consecutive edges are allowed. Polygons that wind around more than once are not convex.
+// Polygons with less than three vertices are not convex.
+func (p Polygon2f) IsConvex() bool {
+	if len(p) < 3 {
+		return false
+	}
+	sign := 0
+	var angleSum float32
+	for i := range p {
+		a := p[i]
+		b := p[(i+1)%len(p)]
+		c := p[(i+2)%len(p)]
+		ab := b.Sub(a)
+		bc := c.Sub(b)
+		cross := ab.MagCross(bc)
+		if cross > 0 {
+			if sign < 0 {
+				return false
+			}
+			sign = 1
+		} else if cross < 0 {
+			if sign > 0 {
+				return false
+			}
+			sign = -1
+		}
+		angleSum += math32.Abs(math32.Atan2(cross, ab.Dot(bc)))
+	}
+	// a star-shaped polygon turns consistently in the same direction, but more than once
+	return sign != 0 && angleSum < 2*math.Pi+0.001
+}
+
+// IsSimple returns true if the polygon does not intersect itself.
+// Adjacent edges are only allowed to share their common vertex.
+// Polygons with less than three vertices are not simple.
+func (p Polygon2f) IsSimple() bool {
+	// brute-force check of all edge pairs in O(n²)
+	n := len(p)
+	if n < 3 {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		e1 := p.Edge(i)
+		for j := i + 1; j < n; j++ {
+			res := e1.Intersect(p.Edge(j))
+			if res.Count == 0 {
+				continue
+			}
+			adjacent := j == i+1 || (i == 0 && j == n-1)
+			if !adjacent || res.Count > 1 {
+				return false
+			}
+			// adjacent edges must only touch at their shared vertex
+			if (j == i+1 && res.T[0] != 1) || (j != i+1 && res.T[0] != 0) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// WindingNumber returns how often the polygon winds around the given point.
+// The result is positive for counter-clockwise windings, and negative for clockwise windings.
+// The result is undefined for points on the polygon's outline.
+func (p Polygon2f) WindingNumber(point Vec2f) int {
+	// Source: "Inclusion of a Point in a Polygon" by Dan Sunday, http://geomalgorithms.com/a03-_inclusion.html
+	wn := 0
+	for i := range p {
+		a := p[i]
+		b := p[(i+1)%len(p)]
+		if a[1] <= point[1] {
+			if b[1] > point[1] && IsPointOnLeft(a, b, point) { // upward crossing
+				wn++
+			}
+		} else {
+			if b[1] <= point[1] && b.Sub(a).MagCross(point.Sub(a)) < 0 { // downward crossing, point on the right
+				wn--
+			}
+		}
+	}
+	return wn
+}
+
+// ContainsPoint checks if a given point resides within the polygon, using the non-zero winding rule.
+// Points on the polygon's outline are also considered to be contained.
+func (p Polygon2f) ContainsPoint(point Vec2f) bool {
+	return p.isOnOutline(point) || p.WindingNumber(point) != 0
+}
+
+// ContainsPointEvenOdd checks if a given point resides within the polygon, using the even-odd rule.
+// Points on the polygon's outline are also considered to be contained.
+func (p Polygon2f) ContainsPointEvenOdd(point Vec2f) bool {
+	if p.isOnOutline(point) {
+		return true
+	}
+	// Source: https://wrf.ecse.rpi.edu/Research/Short_Notes/pnpoly.html
+	inside := false
+	for i := range p {
+		a := p[i]
+		b := p[(i+1)%len(p)]
+		if (a[1] > point[1]) != (b[1] > point[1]) &&
+			point[0] < (b[0]-a[0])*(point[1]-a[1])/(b[1]-a[1])+a[0] {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// isOnOutline returns true if the point lies on one of the polygon's edges.
+func (p Polygon2f) isOnOutline(point Vec2f) bool {
+	for i := range p {
+		if closest, _ := p.Edge(i).ClosestPoint(point); closest.Equal(point) {
+			return true
+		}
+	}
+	return false
+}
+
+// FillRule defines which regions of overlapping or self-intersecting polygon rings are considered to be filled,
+// based on the regions' winding numbers.
+type FillRule int
+
+const (
+	// FillEvenOdd fills regions with an odd winding number.
+	FillEvenOdd FillRule = iota
+	// FillNonZero fills regions with a non-zero winding number.
+	FillNonZero
+	// FillPositive fills regions with a positive winding number (wound counter-clockwise).
+	FillPositive
+	// FillNegative fills regions with a negative winding number (wound clockwise).
+	FillNegative
+)
+
+func (f FillRule) String() string {
+	switch f {
+	case FillEvenOdd:
+		return "EvenOdd"
+	case FillNonZero:
+		return "NonZero"
+	case FillPositive:
+		return "Positive"
+	case FillNegative:
+		return "Negative"
+	}
+	return "FillRule(invalid)"
+}
+
+// IsFilled returns true if a region with the given winding number is filled.
+func (f FillRule) IsFilled(winding int) bool {
+	switch f {
+	case FillEvenOdd:
+		return winding%2 != 0
+	case FillNonZero:
+		return winding != 0
+	case FillPositive:
+		return winding > 0
+	case FillNegative:
+		return winding < 0
+	}
+	return false
+}
+
+// PolygonWithHoles2f represents a polygon with an outer ring and any number of holes.
+// The outer ring is in counter-clockwise order, and the holes are in clockwise order.
+type PolygonWithHoles2f struct {
+	Outer Polygon2f
+	Holes []Polygon2f
+}
+
+// Area returns the polygon's area, excluding its holes.
+func (p PolygonWithHoles2f) Area() float32 {
+	area := p.Outer.Area()
+	for _, hole := range p.Holes {
+		area -= hole.Area()
+	}
+	return area
+}
+
+// ContainsPoint checks if a given point resides within the polygon, but not within one of its holes.
+// Points on the polygon's outline are also considered to be contained.
+func (p PolygonWithHoles2f) ContainsPoint(point Vec2f) bool {
+	if !p.Outer.ContainsPoint(point) {
+		return false
+	}
+	for _, hole := range p.Holes {
+		if hole.WindingNumber(point) != 0 && !hole.isOnOutline(point) {
+			return false
+		}
+	}
+	return true
+}
+
+// Triangulate splits the polygon into triangles, using ear clipping.
+// See TriangulatePolygon for details.
+func (p PolygonWithHoles2f) Triangulate() []uint32 {
+	return TriangulatePolygon(p.Outer, p.Holes...)
+}
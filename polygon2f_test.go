@@ -0,0 +1,143 @@
+package vmath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolygon2f_Edge(t *testing.T) {
+	p := Polygon2f{{0, 0}, {4, 0}, {4, 2}}
+	assert.Equal(t, Segment2f{Vec2f{4, 0}, Vec2f{4, 2}}, p.Edge(1))
+	assert.Equal(t, Segment2f{Vec2f{4, 2}, Vec2f{0, 0}}, p.Edge(2))
+}
+
+func TestPolygon2f_Area(t *testing.T) {
+	p := Polygon2f{{0, 0}, {4, 0}, {4, 2}, {0, 2}}
+	AssertFloat(t, 8, p.SignedArea())
+	AssertFloat(t, 8, p.Area())
+	assert.True(t, p.IsCounterClockwise())
+
+	r := p.Reverse()
+	assert.Equal(t, Polygon2f{{0, 2}, {4, 2}, {4, 0}, {0, 0}}, r)
+	AssertFloat(t, -8, r.SignedArea())
+	AssertFloat(t, 8, r.Area())
+	assert.False(t, r.IsCounterClockwise())
+
+	AssertFloat(t, 0, Polygon2f{}.SignedArea())
+}
+
+func TestPolygon2f_Centroid(t *testing.T) {
+	p := Polygon2f{{1, 1}, {5, 1}, {5, 3}, {1, 3}}
+	AssertVec2f(t, Vec2f{3, 2}, p.Centroid())
+	AssertVec2f(t, Vec2f{3, 2}, p.Reverse().Centroid())
+
+	// L-shape
+	p = Polygon2f{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}}
+	AssertVec2f(t, Vec2f{5.0 / 6.0, 5.0 / 6.0}, p.Centroid())
+
+	// degenerate
+	AssertVec2f(t, Vec2f{1, 1}, Polygon2f{{0, 0}, {1, 1}, {2, 2}}.Centroid())
+	AssertVec2f(t, Vec2f{}, Polygon2f{}.Centroid())
+}
+
+func TestPolygon2f_Perimeter(t *testing.T) {
+	AssertFloat(t, 12, Polygon2f{{0, 0}, {4, 0}, {4, 2}, {0, 2}}.Perimeter())
+	AssertFloat(t, 12, Polygon2f{{0, 0}, {3, 0}, {3, 4}}.Perimeter())
+}
+
+func TestPolygon2f_Bounds(t *testing.T) {
+	p := Polygon2f{{1, 5}, {-2, 0}, {4, 2}}
+	assert.Equal(t, Rectf{Vec2f{-2, 0}, Vec2f{4, 5}}, p.Bounds())
+	assert.Equal(t, Rectf{}, Polygon2f{}.Bounds())
+}
+
+func TestPolygon2f_IsConvex(t *testing.T) {
+	square := Polygon2f{{0, 0}, {4, 0}, {4, 4}, {0, 4}}
+	assert.True(t, square.IsConvex())
+	assert.True(t, square.Reverse().IsConvex())
+	assert.True(t, Polygon2f{{0, 0}, {2, 0}, {4, 0}, {4, 4}, {0, 4}}.IsConvex()) // collinear
+
+	assert.False(t, Polygon2f{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}}.IsConvex())
+	// pentagram
+	assert.False(t, Polygon2f{{0, 10}, {6, -8}, {-9.5, 3}, {9.5, 3}, {-6, -8}}.IsConvex())
+	// degenerate
+	assert.False(t, Polygon2f{{0, 0}, {1, 1}}.IsConvex())
+	assert.False(t, Polygon2f{{0, 0}, {1, 1}, {2, 2}}.IsConvex())
+}
+
+func TestPolygon2f_IsSimple(t *testing.T) {
+	assert.True(t, Polygon2f{{0, 0}, {4, 0}, {4, 4}, {0, 4}}.IsSimple())
+	assert.True(t, Polygon2f{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}}.IsSimple())
+	assert.True(t, Polygon2f{{0, 0}, {1, 0}, {0, 1}}.IsSimple())
+
+	// bow tie
+	assert.False(t, Polygon2f{{0, 0}, {4, 4}, {4, 0}, {0, 4}}.IsSimple())
+	// pentagram
+	assert.False(t, Polygon2f{{0, 10}, {6, -8}, {-9.5, 3}, {9.5, 3}, {-6, -8}}.IsSimple())
+	// touching vertex
+	assert.False(t, Polygon2f{{0, 0}, {4, 0}, {2, 2}, {4, 4}, {0, 4}, {2, 0}}.IsSimple())
+	// spike folding back onto the previous edge
+	assert.False(t, Polygon2f{{0, 0}, {4, 0}, {2, 0}, {2, 2}}.IsSimple())
+	assert.False(t, Polygon2f{{0, 0}, {4, 0}}.IsSimple())
+}
+
+func TestPolygon2f_ContainsPoint(t *testing.T) {
+	// U-shape
+	p := Polygon2f{{0, 0}, {3, 0}, {3, 3}, {2, 3}, {2, 1}, {1, 1}, {1, 3}, {0, 3}}
+	assert.True(t, p.ContainsPoint(Vec2f{0.5, 2}))
+	assert.True(t, p.ContainsPoint(Vec2f{1.5, 0.5}))
+	assert.False(t, p.ContainsPoint(Vec2f{1.5, 2}))
+	assert.False(t, p.ContainsPoint(Vec2f{4, 0.5}))
+	assert.True(t, p.ContainsPoint(Vec2f{3, 2})) // on edge
+	assert.True(t, p.ContainsPoint(Vec2f{1, 1})) // on vertex
+	assert.True(t, p.Reverse().ContainsPoint(Vec2f{0.5, 2}))
+
+	for _, point := range []Vec2f{{0.5, 2}, {1.5, 0.5}, {1.5, 2}, {4, 0.5}, {3, 2}} {
+		assert.Equal(t, p.ContainsPoint(point), p.ContainsPointEvenOdd(point))
+	}
+}
+
+func TestPolygon2f_WindingNumber(t *testing.T) {
+	p := Polygon2f{{0, 0}, {4, 0}, {4, 4}, {0, 4}}
+	assert.Equal(t, 1, p.WindingNumber(Vec2f{2, 2}))
+	assert.Equal(t, -1, p.Reverse().WindingNumber(Vec2f{2, 2}))
+	assert.Equal(t, 0, p.WindingNumber(Vec2f{5, 2}))
+
+	// the pentagram's center is wound around twice
+	star := Polygon2f{{0, 10}, {6, -8}, {-9.5, 3}, {9.5, 3}, {-6, -8}}
+	center := Vec2f{0, 0}
+	assert.Equal(t, -2, star.WindingNumber(center))
+	assert.True(t, star.ContainsPoint(center))
+	assert.False(t, star.ContainsPointEvenOdd(center))
+
+	tip := Vec2f{0, 8}
+	assert.Equal(t, -1, star.WindingNumber(tip))
+	assert.True(t, star.ContainsPoint(tip))
+	assert.True(t, star.ContainsPointEvenOdd(tip))
+}
+
+func TestFillRule_IsFilled(t *testing.T) {
+	for _, wn := range []int{-2, -1, 0, 1, 2, 3} {
+		assert.Equal(t, wn == -1 || wn == 1 || wn == 3, FillEvenOdd.IsFilled(wn))
+		assert.Equal(t, wn != 0, FillNonZero.IsFilled(wn))
+		assert.Equal(t, wn > 0, FillPositive.IsFilled(wn))
+		assert.Equal(t, wn < 0, FillNegative.IsFilled(wn))
+	}
+}
+
+func TestPolygonWithHoles2f(t *testing.T) {
+	p := PolygonWithHoles2f{
+		Outer: Polygon2f{{0, 0}, {4, 0}, {4, 4}, {0, 4}},
+		Holes: []Polygon2f{{{1, 1}, {1, 3}, {3, 3}, {3, 1}}},
+	}
+	AssertFloat(t, 12, p.Area())
+	assert.True(t, p.ContainsPoint(Vec2f{0.5, 2}))
+	assert.True(t, p.ContainsPoint(Vec2f{1, 2})) // on the hole's outline
+	assert.False(t, p.ContainsPoint(Vec2f{2, 2}))
+	assert.False(t, p.ContainsPoint(Vec2f{5, 2}))
+
+	indices := p.Triangulate()
+	assert.Len(t, indices, 8*3)
+	checkTriangulation(t, indices, 12, append([]Polygon2f{p.Outer}, p.Holes...)...)
+}
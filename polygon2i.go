@@ -0,0 +1,48 @@
+package vmath
+
+// Polygon2i represents a closed polygon on the 2D plane with integer coordinates.
+// The last vertex is implicitly connected with the first one and must not be repeated.
+type Polygon2i []Vec2i
+
+// Polygon2f converts the polygon into a floating point polygon.
+func (p Polygon2i) Polygon2f() Polygon2f {
+	res := make(Polygon2f, len(p))
+	for i, v := range p {
+		res[i] = v.Vec2f()
+	}
+	return res
+}
+
+// DoubleSignedArea returns twice the polygon's signed area.
+// The area is positive if the vertices are in counter-clockwise order, and negative otherwise.
+// Doubling the area keeps the result exact.
+func (p Polygon2i) DoubleSignedArea() int {
+	area := 0
+	for i := range p {
+		area += p[i].MagCross(p[(i+1)%len(p)])
+	}
+	return area
+}
+
+// IsCounterClockwise returns true if the polygon's vertices are in counter-clockwise order.
+func (p Polygon2i) IsCounterClockwise() bool {
+	return p.DoubleSignedArea() > 0
+}
+
+// PolygonWithHoles2i represents a polygon with integer coordinates, an outer ring and any number of holes.
+// The outer ring is in counter-clockwise order, and the holes are in clockwise order.
+type PolygonWithHoles2i struct {
+	Outer Polygon2i
+	Holes []Polygon2i
+}
+
+// PolygonWithHoles2f converts the polygon into a floating point polygon.
+func (p PolygonWithHoles2i) PolygonWithHoles2f() PolygonWithHoles2f {
+	res := PolygonWithHoles2f{
+		Outer: p.Outer.Polygon2f(),
+	}
+	for _, hole := range p.Holes {
+		res.Holes = append(res.Holes, hole.Polygon2f())
+	}
+	return res
+}
@@ -0,0 +1,30 @@
+package vmath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolygon2i_DoubleSignedArea(t *testing.T) {
+	square := Polygon2i{{0, 0}, {3, 0}, {3, 3}, {0, 3}}
+	assert.Equal(t, 18, square.DoubleSignedArea())
+	assert.True(t, square.IsCounterClockwise())
+
+	tri := Polygon2i{{0, 0}, {0, 1}, {1, 0}}
+	assert.Equal(t, -1, tri.DoubleSignedArea())
+	assert.False(t, tri.IsCounterClockwise())
+
+	assert.Equal(t, Polygon2f{{0, 0}, {0, 1}, {1, 0}}, tri.Polygon2f())
+}
+
+func TestPolygonWithHoles2i_PolygonWithHoles2f(t *testing.T) {
+	p := PolygonWithHoles2i{
+		Outer: Polygon2i{{0, 0}, {4, 0}, {4, 4}, {0, 4}},
+		Holes: []Polygon2i{{{1, 1}, {1, 2}, {2, 2}, {2, 1}}},
+	}
+	assert.Equal(t, PolygonWithHoles2f{
+		Outer: Polygon2f{{0, 0}, {4, 0}, {4, 4}, {0, 4}},
+		Holes: []Polygon2f{{{1, 1}, {1, 2}, {2, 2}, {2, 1}}},
+	}, p.PolygonWithHoles2f())
+}
@@ -0,0 +1,604 @@
+package vmath
+
+import (
+	"math"
+	"sort"
+)
+
+// BooleanOp defines a boolean operation between two sets of polygons.
+type BooleanOp int
+
+const (
+	// OpUnion results in the regions covered by the subject or the clip polygons.
+	OpUnion BooleanOp = iota
+	// OpIntersection results in the regions covered by both, the subject and the clip polygons.
+	OpIntersection
+	// OpDifference results in the regions covered by the subject, but not by the clip polygons.
+	OpDifference
+	// OpXor results in the regions covered by either the subject or the clip polygons, but not by both.
+	OpXor
+)
+
+func (op BooleanOp) String() string {
+	switch op {
+	case OpUnion:
+		return "Union"
+	case OpIntersection:
+		return "Intersection"
+	case OpDifference:
+		return "Difference"
+	case OpXor:
+		return "Xor"
+	}
+	return "BooleanOp(invalid)"
+}
+
+// apply returns true if a region is part of the result, given whether it is filled by the subject and clip polygons.
+func (op BooleanOp) apply(subject, clip bool) bool {
+	switch op {
+	case OpUnion:
+		return subject || clip
+	case OpIntersection:
+		return subject && clip
+	case OpDifference:
+		return subject && !clip
+	case OpXor:
+		return subject != clip
+	}
+	return false
+}
+
+// PolygonBoolean2f performs a boolean operation between two sets of polygon rings.
+// The fill rule defines which regions of the subject and clip are filled, so rings can overlap, self-intersect or describe holes.
+// The result consists of non-overlapping polygons with counter-clockwise outer rings and clockwise holes.
+// Polygons that only touch at single vertices are returned separately. Collinear vertices are removed.
+// An union without clip polygons can be used to resolve self-intersections and overlaps.
+// All points are snapped to a grid matching the float32 precision of the largest coordinate.
+// Runs in O(n log n + k) for n edges, where k is the number of edge pairs that overlap on the x-axis.
+func PolygonBoolean2f(subject, clip []Polygon2f, op BooleanOp, fillRule FillRule) []PolygonWithHoles2f {
+	var operands [2][][]vec2d
+	var extent float64
+	for k, polys := range [2][]Polygon2f{subject, clip} {
+		for _, poly := range polys {
+			ring := make([]vec2d, len(poly))
+			for i, v := range poly {
+				ring[i] = vec2d{float64(v[0]), float64(v[1])}
+				extent = math.Max(extent, math.Max(math.Abs(ring[i][0]), math.Abs(ring[i][1])))
+			}
+			operands[k] = append(operands[k], ring)
+		}
+	}
+	// snapping to a grid keeps intersection points consistent and exactly representable as float32
+	_, exp := math.Frexp(extent)
+	grid := math.Ldexp(1, exp-24)
+	snap := func(v vec2d) vec2d {
+		return vec2d{math.Round(v[0]/grid) * grid, math.Round(v[1]/grid) * grid}
+	}
+
+	toPolygon := func(ring []vec2d) Polygon2f {
+		poly := make(Polygon2f, len(ring))
+		for i, v := range ring {
+			poly[i] = Vec2f{float32(v[0]), float32(v[1])}
+		}
+		return poly
+	}
+	polys := polygonBoolean(operands, op, fillRule, snap)
+	res := make([]PolygonWithHoles2f, len(polys))
+	for i, poly := range polys {
+		res[i].Outer = toPolygon(poly.outer)
+		for _, hole := range poly.holes {
+			res[i].Holes = append(res[i].Holes, toPolygon(hole))
+		}
+	}
+	return res
+}
+
+// PolygonBoolean2i performs a boolean operation between two sets of polygon rings with integer coordinates.
+// See PolygonBoolean2f for details.
+// Intersection points are rounded to the nearest integer coordinates.
+// The result is exact for geometry without diagonal edges, like tile-aligned polygons.
+func PolygonBoolean2i(subject, clip []Polygon2i, op BooleanOp, fillRule FillRule) []PolygonWithHoles2i {
+	var operands [2][][]vec2d
+	for k, polys := range [2][]Polygon2i{subject, clip} {
+		for _, poly := range polys {
+			ring := make([]vec2d, len(poly))
+			for i, v := range poly {
+				ring[i] = vec2d{float64(v[0]), float64(v[1])}
+			}
+			operands[k] = append(operands[k], ring)
+		}
+	}
+	snap := func(v vec2d) vec2d {
+		return vec2d{math.Round(v[0]), math.Round(v[1])}
+	}
+
+	toPolygon := func(ring []vec2d) Polygon2i {
+		poly := make(Polygon2i, len(ring))
+		for i, v := range ring {
+			poly[i] = Vec2i{int(v[0]), int(v[1])}
+		}
+		return poly
+	}
+	polys := polygonBoolean(operands, op, fillRule, snap)
+	res := make([]PolygonWithHoles2i, len(polys))
+	for i, poly := range polys {
+		res[i].Outer = toPolygon(poly.outer)
+		for _, hole := range poly.holes {
+			res[i].Holes = append(res[i].Holes, toPolygon(hole))
+		}
+	}
+	return res
+}
+
+// ClipConvex clips the polygon against a convex clip polygon, using the Sutherland–Hodgman algorithm.
+// The clip polygon can be in clockwise or counter-clockwise order. The result keeps the polygon's vertex order.
+// If the polygon is concave, the result can contain degenerate edges connecting otherwise separate parts.
+// Returns nil if the polygons do not overlap.
+func (p Polygon2f) ClipConvex(clip Polygon2f) Polygon2f {
+	// Source: "Reentrant Polygon Clipping" by I. Sutherland and G. Hodgman, 1974.
+	if !clip.IsCounterClockwise() {
+		clip = clip.Reverse()
+	}
+	res := p
+	for i := range clip {
+		if len(res) == 0 {
+			break
+		}
+		a := clip[i]
+		edge := clip[(i+1)%len(clip)].Sub(a)
+		// positive if the point is on the inner (left) side of the clip edge
+		side := func(v Vec2f) float32 {
+			return edge.MagCross(v.Sub(a))
+		}
+
+		input := res
+		res = make(Polygon2f, 0, len(input)+1)
+		prev := input[len(input)-1]
+		prevSide := side(prev)
+		for _, cur := range input {
+			curSide := side(cur)
+			if (curSide >= 0) != (prevSide >= 0) {
+				res = append(res, prev.Lerp(cur, prevSide/(prevSide-curSide)))
+			}
+			if curSide >= 0 {
+				res = append(res, cur)
+			}
+			prev, prevSide = cur, curSide
+		}
+	}
+	if len(res) < 3 {
+		return nil
+	}
+	return res
+}
+
+// booleanPolygon is a result polygon of polygonBoolean.
+type booleanPolygon struct {
+	outer []vec2d
+	holes [][]vec2d
+}
+
+// booleanSegment is a directed edge of an input ring.
+type booleanSegment struct {
+	a, b    vec2d
+	operand int
+}
+
+// booleanEdge is an unique, undirected edge after splitting all segments at their intersections.
+// A is lexicographically smaller than B.
+type booleanEdge struct {
+	a, b vec2d
+	// count is the change of each operand's winding number when crossing the edge from right to left.
+	count [2]int
+}
+
+// polygonBoolean performs a boolean operation between the rings of the two operands (subject and clip).
+// All edges are split at their intersections. The resulting edges are kept if the regions on both sides are classified
+// differently, and then connected into rings.
+func polygonBoolean(operands [2][][]vec2d, op BooleanOp, fillRule FillRule, snap func(vec2d) vec2d) []booleanPolygon {
+	var segments []booleanSegment
+	for operand, rings := range operands {
+		for _, ring := range rings {
+			for i := range ring {
+				a := snap(ring[i])
+				b := snap(ring[(i+1)%len(ring)])
+				if a != b {
+					segments = append(segments, booleanSegment{a, b, operand})
+				}
+			}
+		}
+	}
+
+	edges := booleanSplit(segments, snap)
+	windings := booleanWindings(edges)
+
+	var result [][2]vec2d // directed edges with the inside on their left
+	for i, e := range edges {
+		right := windings[i]
+		left := [2]int{right[0] + e.count[0], right[1] + e.count[1]}
+		insideRight := op.apply(fillRule.IsFilled(right[0]), fillRule.IsFilled(right[1]))
+		insideLeft := op.apply(fillRule.IsFilled(left[0]), fillRule.IsFilled(left[1]))
+		if insideLeft == insideRight {
+			continue
+		}
+		if insideLeft {
+			result = append(result, [2]vec2d{e.a, e.b})
+		} else {
+			result = append(result, [2]vec2d{e.b, e.a})
+		}
+	}
+
+	return booleanAssemble(booleanTrace(result))
+}
+
+// booleanSplit splits all segments at their intersections and merges overlapping parts into unique edges.
+// The segments are swept from left to right, and each one is only tested against the segments that overlap it on the x-axis.
+func booleanSplit(segments []booleanSegment, snap func(vec2d) vec2d) []booleanEdge {
+	const eps = 1e-12
+	splits := make([][]vec2d, len(segments))
+	order := make([]int, len(segments))
+	for i, s := range segments {
+		splits[i] = []vec2d{s.a, s.b}
+		order[i] = i
+	}
+	minX := func(s booleanSegment) float64 { return math.Min(s.a[0], s.b[0]) }
+	maxX := func(s booleanSegment) float64 { return math.Max(s.a[0], s.b[0]) }
+	sort.Slice(order, func(x, y int) bool {
+		return minX(segments[order[x]]) < minX(segments[order[y]])
+	})
+
+	var active []int // segments crossing the sweep line
+	for _, cur := range order {
+		x := minX(segments[cur])
+		n := 0
+		for _, other := range active {
+			if maxX(segments[other]) >= x {
+				active[n] = other
+				n++
+			}
+		}
+		active = active[:n]
+
+		for _, other := range active {
+			i, j := cur, other
+			if i > j {
+				i, j = j, i
+			}
+			s1, s2 := segments[i], segments[j]
+			if math.Max(s1.a[1], s1.b[1]) < math.Min(s2.a[1], s2.b[1]) ||
+				math.Min(s1.a[1], s1.b[1]) > math.Max(s2.a[1], s2.b[1]) {
+				continue
+			}
+			d1 := s1.b.sub(s1.a)
+			len1 := math.Sqrt(d1.dot(d1))
+			d2 := s2.b.sub(s2.a)
+			w := s2.a.sub(s1.a)
+			denom := d1.cross(d2)
+
+			if math.Abs(denom) > eps*len1*math.Sqrt(d2.dot(d2)) {
+				t := w.cross(d2) / denom
+				u := w.cross(d1) / denom
+				if t < -eps || t > 1+eps || u < -eps || u > 1+eps {
+					continue
+				}
+				// intersections close to an endpoint are snapped onto it
+				p := snap(s1.a.add(d1.mul(math.Min(math.Max(t, 0), 1))))
+				splits[i] = append(splits[i], p)
+				splits[j] = append(splits[j], p)
+				continue
+			}
+			if math.Abs(d1.cross(w)) > eps*len1*math.Sqrt(w.dot(w)) {
+				continue // parallel, but not collinear
+			}
+			splits[i] = booleanAppendInner(splits[i], s1, s2.a, s2.b)
+			splits[j] = booleanAppendInner(splits[j], s2, s1.a, s1.b)
+		}
+		active = append(active, cur)
+	}
+
+	var edges []booleanEdge
+	index := make(map[[2]vec2d]int)
+	for i, s := range segments {
+		points := splits[i]
+		d := s.b.sub(s.a)
+		sort.Slice(points, func(x, y int) bool {
+			return points[x].sub(s.a).dot(d) < points[y].sub(s.a).dot(d)
+		})
+		for k := 1; k < len(points); k++ {
+			a, b := points[k-1], points[k]
+			if a == b {
+				continue
+			}
+			sign := 1
+			if vec2dLess(b, a) {
+				a, b = b, a
+				sign = -1
+			}
+			key := [2]vec2d{a, b}
+			idx, ok := index[key]
+			if !ok {
+				idx = len(edges)
+				index[key] = idx
+				edges = append(edges, booleanEdge{a: a, b: b})
+			}
+			edges[idx].count[s.operand] += sign
+		}
+	}
+
+	// remove edges that cancel each other out
+	res := edges[:0]
+	for _, e := range edges {
+		if e.count != [2]int{} {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// booleanAppendInner appends those points that lie within the collinear segment, excluding its endpoints.
+func booleanAppendInner(splits []vec2d, s booleanSegment, points ...vec2d) []vec2d {
+	d := s.b.sub(s.a)
+	sqLen := d.dot(d)
+	for _, p := range points {
+		if t := p.sub(s.a).dot(d); t > 0 && t < sqLen {
+			splits = append(splits, p)
+		}
+	}
+	return splits
+}
+
+// booleanWindings returns the winding numbers of both operands on the right side of each edge.
+// The edges must not intersect each other. They are swept from left to right, while the status holds the non-vertical
+// edges crossing the sweep line, ordered from bottom to top. The region below an edge has the same winding numbers as
+// the region above its neighbour below.
+// The status is a sorted slice, so updating it is linear in the number of edges that overlap on the x-axis.
+func booleanWindings(edges []booleanEdge) [][2]int {
+	windings := make([][2]int, len(edges))
+	var starts, ends, verticals []int // events, sorted by their x-coordinate
+	for i, e := range edges {
+		if e.a[0] == e.b[0] {
+			verticals = append(verticals, i)
+		} else {
+			starts = append(starts, i)
+			ends = append(ends, i)
+		}
+	}
+	slope := func(e booleanEdge) float64 {
+		return (e.b[1] - e.a[1]) / (e.b[0] - e.a[0])
+	}
+	yAt := func(e booleanEdge, x float64) float64 {
+		if x == e.a[0] {
+			return e.a[1]
+		}
+		return e.a[1] + (x-e.a[0])*slope(e)
+	}
+	sort.Slice(starts, func(x, y int) bool {
+		e1, e2 := edges[starts[x]], edges[starts[y]]
+		if e1.a != e2.a {
+			return vec2dLess(e1.a, e2.a)
+		}
+		return slope(e1) < slope(e2)
+	})
+	sort.Slice(ends, func(x, y int) bool {
+		return edges[ends[x]].b[0] < edges[ends[y]].b[0]
+	})
+	sort.Slice(verticals, func(x, y int) bool {
+		return edges[verticals[x]].a[0] < edges[verticals[y]].a[0]
+	})
+
+	var status []int
+	// above returns the winding numbers above the k-th edge within the status; or zero below the lowest edge
+	above := func(k int) [2]int {
+		if k < 0 {
+			return [2]int{}
+		}
+		e := status[k]
+		return [2]int{windings[e][0] + edges[e].count[0], windings[e][1] + edges[e].count[1]}
+	}
+
+	for len(starts) > 0 || len(verticals) > 0 {
+		x := math.Inf(1)
+		if len(starts) > 0 {
+			x = edges[starts[0]].a[0]
+		}
+		if len(ends) > 0 {
+			x = math.Min(x, edges[ends[0]].b[0])
+		}
+		if len(verticals) > 0 {
+			x = math.Min(x, edges[verticals[0]].a[0])
+		}
+
+		// edges are removed before new ones start at the same coordinate
+		for ; len(ends) > 0 && edges[ends[0]].b[0] == x; ends = ends[1:] {
+			for k, e := range status {
+				if e == ends[0] {
+					status = append(status[:k], status[k+1:]...)
+					break
+				}
+			}
+		}
+		// edges starting at the same point are inserted from bottom to top, so their neighbours below are known
+		for ; len(starts) > 0 && edges[starts[0]].a[0] == x; starts = starts[1:] {
+			e := edges[starts[0]]
+			k := sort.Search(len(status), func(k int) bool {
+				other := edges[status[k]]
+				y := yAt(other, x)
+				return y > e.a[1] || (y == e.a[1] && slope(other) > slope(e))
+			})
+			windings[starts[0]] = above(k - 1)
+			status = append(status, 0)
+			copy(status[k+1:], status[k:])
+			status[k] = starts[0]
+		}
+		// the right side of vertical edges lies below the first edge above their center
+		for ; len(verticals) > 0 && edges[verticals[0]].a[0] == x; verticals = verticals[1:] {
+			e := edges[verticals[0]]
+			center := (e.a[1] + e.b[1]) / 2
+			k := sort.Search(len(status), func(k int) bool {
+				return yAt(edges[status[k]], x) > center
+			})
+			windings[verticals[0]] = above(k - 1)
+		}
+	}
+	return windings
+}
+
+// booleanTrace connects directed edges into closed rings.
+// If multiple edges leave a vertex, the one with the sharpest left turn is taken.
+// This separates rings that touch each other.
+func booleanTrace(edges [][2]vec2d) [][]vec2d {
+	outgoing := make(map[vec2d][]int)
+	for i, e := range edges {
+		outgoing[e[0]] = append(outgoing[e[0]], i)
+	}
+
+	used := make([]bool, len(edges))
+	var rings [][]vec2d
+	for start := range edges {
+		if used[start] {
+			continue
+		}
+		var ring []vec2d
+		cur := start
+		for {
+			used[cur] = true
+			e := edges[cur]
+			ring = append(ring, e[0])
+			back := e[0].sub(e[1])
+
+			next := -1
+			best := math.Inf(1)
+			for _, cand := range outgoing[e[1]] {
+				if used[cand] && cand != start {
+					continue
+				}
+				dir := edges[cand][1].sub(edges[cand][0])
+				// clockwise angle from the incoming edge, in range ]0, 2π]
+				angle := -math.Atan2(back.cross(dir), back.dot(dir))
+				if angle <= 0 {
+					angle += 2 * math.Pi
+				}
+				if angle < best {
+					best, next = angle, cand
+				}
+			}
+			if next == -1 || next == start {
+				break
+			}
+			cur = next
+		}
+		rings = append(rings, ring)
+	}
+	return rings
+}
+
+// booleanAssemble sorts rings into outer rings and holes, and removes collinear vertices.
+func booleanAssemble(rings [][]vec2d) []booleanPolygon {
+	var polys []booleanPolygon
+	var areas []float64
+	var holes [][]vec2d
+	for _, ring := range rings {
+		if area := ringSignedArea(ring); area > 0 {
+			polys = append(polys, booleanPolygon{outer: ring})
+			areas = append(areas, area)
+		} else {
+			holes = append(holes, ring)
+		}
+	}
+
+	// holes belong to the smallest outer ring that contains them
+	for _, hole := range holes {
+		p := hole[0].add(hole[1%len(hole)]).mul(0.5) // does not lie on any other ring
+		best := -1
+		for i, poly := range polys {
+			if (best < 0 || areas[i] < areas[best]) && ringWindingNumber(poly.outer, p) != 0 {
+				best = i
+			}
+		}
+		if best >= 0 {
+			polys[best].holes = append(polys[best].holes, hole)
+		}
+	}
+
+	res := polys[:0]
+	for _, poly := range polys {
+		if poly.outer = cleanRing(poly.outer); poly.outer == nil {
+			continue
+		}
+		holes := poly.holes[:0]
+		for _, hole := range poly.holes {
+			if hole = cleanRing(hole); hole != nil {
+				holes = append(holes, hole)
+			}
+		}
+		poly.holes = holes
+		res = append(res, poly)
+	}
+	return res
+}
+
+// cleanRing removes collinear vertices and rotates the ring to start at its lexicographically smallest vertex.
+// Returns nil if less than three vertices remain.
+func cleanRing(ring []vec2d) []vec2d {
+	for changed := true; changed; {
+		changed = false
+		for i := 0; i < len(ring) && len(ring) >= 3; {
+			d1 := ring[i].sub(ring[(i+len(ring)-1)%len(ring)])
+			d2 := ring[(i+1)%len(ring)].sub(ring[i])
+			if math.Abs(d1.cross(d2)) <= 1e-12*math.Sqrt(d1.dot(d1)*d2.dot(d2)) {
+				ring = append(ring[:i], ring[i+1:]...)
+				changed = true
+			} else {
+				i++
+			}
+		}
+	}
+	if len(ring) < 3 {
+		return nil
+	}
+	first := 0
+	for i, v := range ring {
+		if vec2dLess(v, ring[first]) {
+			first = i
+		}
+	}
+	res := make([]vec2d, 0, len(ring))
+	res = append(res, ring[first:]...)
+	return append(res, ring[:first]...)
+}
+
+// ringSignedArea returns the ring's signed area, which is positive for counter-clockwise rings.
+func ringSignedArea(ring []vec2d) float64 {
+	var area float64
+	for i := range ring {
+		area += ring[i].cross(ring[(i+1)%len(ring)])
+	}
+	return area / 2
+}
+
+// ringWindingNumber returns how often the ring winds around the given point.
+// See Polygon2f.WindingNumber.
+func ringWindingNumber(ring []vec2d, p vec2d) int {
+	wn := 0
+	for i := range ring {
+		a := ring[i]
+		b := ring[(i+1)%len(ring)]
+		side := b.sub(a).cross(p.sub(a))
+		if a[1] <= p[1] {
+			if b[1] > p[1] && side > 0 {
+				wn++
+			}
+		} else if b[1] <= p[1] && side < 0 {
+			wn--
+		}
+	}
+	return wn
+}
+
+// vec2dLess returns true if a is lexicographically smaller than b.
+func vec2dLess(a, b vec2d) bool {
+	if a[0] != b[0] {
+		return a[0] < b[0]
+	}
+	return a[1] < b[1]
+}
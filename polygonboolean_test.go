@@ -0,0 +1,192 @@
+package vmath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolygonBoolean2f(t *testing.T) {
+	a := Polygon2f{{0, 0}, {4, 0}, {4, 4}, {0, 4}}
+	b := Polygon2f{{2, 2}, {6, 2}, {6, 6}, {2, 6}}
+
+	res := PolygonBoolean2f([]Polygon2f{a}, []Polygon2f{b}, OpUnion, FillNonZero)
+	assert.Equal(t, []PolygonWithHoles2f{{
+		Outer: Polygon2f{{0, 0}, {4, 0}, {4, 2}, {6, 2}, {6, 6}, {2, 6}, {2, 4}, {0, 4}},
+	}}, res)
+
+	res = PolygonBoolean2f([]Polygon2f{a}, []Polygon2f{b}, OpIntersection, FillNonZero)
+	assert.Equal(t, []PolygonWithHoles2f{{
+		Outer: Polygon2f{{2, 2}, {4, 2}, {4, 4}, {2, 4}},
+	}}, res)
+
+	res = PolygonBoolean2f([]Polygon2f{a}, []Polygon2f{b}, OpDifference, FillNonZero)
+	assert.Equal(t, []PolygonWithHoles2f{{
+		Outer: Polygon2f{{0, 0}, {4, 0}, {4, 2}, {2, 2}, {2, 4}, {0, 4}},
+	}}, res)
+
+	res = PolygonBoolean2f([]Polygon2f{a}, []Polygon2f{b}, OpXor, FillNonZero)
+	assert.Len(t, res, 2)
+	assert.InDelta(t, 24, res[0].Area()+res[1].Area(), 1e-5)
+	for _, poly := range res {
+		assert.True(t, poly.Outer.IsCounterClockwise())
+		assert.Empty(t, poly.Holes)
+	}
+
+	// clockwise input
+	res = PolygonBoolean2f([]Polygon2f{a.Reverse()}, []Polygon2f{b.Reverse()}, OpIntersection, FillNonZero)
+	assert.Equal(t, []PolygonWithHoles2f{{
+		Outer: Polygon2f{{2, 2}, {4, 2}, {4, 4}, {2, 4}},
+	}}, res)
+
+	// disjoint
+	c := Polygon2f{{10, 10}, {11, 10}, {11, 11}}
+	assert.Empty(t, PolygonBoolean2f([]Polygon2f{a}, []Polygon2f{c}, OpIntersection, FillNonZero))
+	assert.Len(t, PolygonBoolean2f([]Polygon2f{a}, []Polygon2f{c}, OpUnion, FillNonZero), 2)
+	assert.Empty(t, PolygonBoolean2f(nil, nil, OpUnion, FillNonZero))
+}
+
+func TestPolygonBoolean2f_Holes(t *testing.T) {
+	outer := Polygon2f{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+	inner := Polygon2f{{2, 2}, {8, 2}, {8, 8}, {2, 8}}
+
+	res := PolygonBoolean2f([]Polygon2f{outer}, []Polygon2f{inner}, OpDifference, FillNonZero)
+	assert.Equal(t, []PolygonWithHoles2f{{
+		Outer: outer,
+		Holes: []Polygon2f{{{2, 2}, {2, 8}, {8, 8}, {8, 2}}},
+	}}, res)
+	assert.Equal(t, float32(64), res[0].Area())
+	assert.False(t, res[0].ContainsPoint(Vec2f{5, 5}))
+	assert.True(t, res[0].ContainsPoint(Vec2f{1, 5}))
+
+	// island within the hole
+	island := Polygon2f{{4, 4}, {6, 4}, {6, 6}, {4, 6}}
+	res = PolygonBoolean2f([]Polygon2f{outer, island}, []Polygon2f{inner}, OpXor, FillEvenOdd)
+	assert.Len(t, res, 2)
+	assert.Len(t, res[0].Holes, 1)
+	assert.Empty(t, res[1].Holes)
+	assert.Equal(t, island, res[1].Outer)
+
+	res = PolygonBoolean2f([]Polygon2f{outer, island}, []Polygon2f{inner}, OpIntersection, FillEvenOdd)
+	assert.Equal(t, []PolygonWithHoles2f{{
+		Outer: inner,
+		Holes: []Polygon2f{{{4, 4}, {4, 6}, {6, 6}, {6, 4}}},
+	}}, res)
+
+	// holes in the input, defined by the fill rule
+	res = PolygonBoolean2f([]Polygon2f{outer, inner}, nil, OpUnion, FillEvenOdd)
+	assert.Len(t, res, 1)
+	assert.Len(t, res[0].Holes, 1)
+	res = PolygonBoolean2f([]Polygon2f{outer, inner}, nil, OpUnion, FillNonZero)
+	assert.Equal(t, []PolygonWithHoles2f{{Outer: outer}}, res)
+	res = PolygonBoolean2f([]Polygon2f{outer, inner.Reverse()}, nil, OpUnion, FillNonZero)
+	assert.Len(t, res[0].Holes, 1)
+}
+
+func TestPolygonBoolean2f_Touching(t *testing.T) {
+	a := Polygon2f{{0, 0}, {2, 0}, {2, 2}, {0, 2}}
+	b := Polygon2f{{2, 0}, {4, 0}, {4, 2}, {2, 2}} // shared edge
+	c := Polygon2f{{2, 2}, {4, 2}, {4, 4}, {2, 4}} // shared corner
+
+	res := PolygonBoolean2f([]Polygon2f{a}, []Polygon2f{b}, OpUnion, FillNonZero)
+	assert.Equal(t, []PolygonWithHoles2f{{
+		Outer: Polygon2f{{0, 0}, {4, 0}, {4, 2}, {0, 2}},
+	}}, res)
+	assert.Empty(t, PolygonBoolean2f([]Polygon2f{a}, []Polygon2f{b}, OpIntersection, FillNonZero))
+
+	res = PolygonBoolean2f([]Polygon2f{a}, []Polygon2f{c}, OpUnion, FillNonZero)
+	assert.Equal(t, []PolygonWithHoles2f{{Outer: a}, {Outer: c}}, res)
+}
+
+func TestPolygonBoolean2f_SelfIntersecting(t *testing.T) {
+	// bow tie, wound counter-clockwise and clockwise
+	bowTie := Polygon2f{{0, 0}, {2, 2}, {2, 0}, {0, 2}}
+	res := PolygonBoolean2f([]Polygon2f{bowTie}, nil, OpUnion, FillNonZero)
+	assert.Equal(t, []PolygonWithHoles2f{
+		{Outer: Polygon2f{{0, 0}, {1, 1}, {0, 2}}},
+		{Outer: Polygon2f{{1, 1}, {2, 0}, {2, 2}}},
+	}, res)
+
+	res = PolygonBoolean2f([]Polygon2f{bowTie}, nil, OpUnion, FillPositive)
+	assert.Equal(t, []PolygonWithHoles2f{{Outer: Polygon2f{{0, 0}, {1, 1}, {0, 2}}}}, res)
+	res = PolygonBoolean2f([]Polygon2f{bowTie}, nil, OpUnion, FillNegative)
+	assert.Equal(t, []PolygonWithHoles2f{{Outer: Polygon2f{{1, 1}, {2, 0}, {2, 2}}}}, res)
+
+	// diagonal intersections
+	diamond := Polygon2f{{2, -1}, {5, 2}, {2, 5}, {-1, 2}}
+	square := Polygon2f{{0, 0}, {4, 0}, {4, 4}, {0, 4}}
+	res = PolygonBoolean2f([]Polygon2f{diamond}, []Polygon2f{square}, OpIntersection, FillNonZero)
+	assert.Len(t, res, 1)
+	assert.Len(t, res[0].Outer, 8)
+	assert.InDelta(t, 16-4*0.5, res[0].Area(), 1e-5)
+}
+
+func TestPolygonBoolean2f_Random(t *testing.T) {
+	area := func(polys []PolygonWithHoles2f) float32 {
+		var area float32
+		for _, poly := range polys {
+			area += poly.Area()
+		}
+		return area
+	}
+	// self-intersecting rings with many intersections
+	subject := []Polygon2f{randomPoints2f(40, 1), randomPoints2f(40, 2)}
+	clip := []Polygon2f{randomPoints2f(40, 3)}
+
+	subjectArea := area(PolygonBoolean2f(subject, nil, OpUnion, FillEvenOdd))
+	clipArea := area(PolygonBoolean2f(clip, nil, OpUnion, FillEvenOdd))
+	union := area(PolygonBoolean2f(subject, clip, OpUnion, FillEvenOdd))
+	intersection := area(PolygonBoolean2f(subject, clip, OpIntersection, FillEvenOdd))
+	difference := area(PolygonBoolean2f(subject, clip, OpDifference, FillEvenOdd))
+	xor := area(PolygonBoolean2f(subject, clip, OpXor, FillEvenOdd))
+
+	assert.InDelta(t, subjectArea+clipArea, union+intersection, 1e-3)
+	assert.InDelta(t, subjectArea, difference+intersection, 1e-3)
+	assert.InDelta(t, union-intersection, xor, 1e-3)
+	assert.True(t, intersection > 0)
+}
+
+func TestPolygonBoolean2i(t *testing.T) {
+	a := Polygon2i{{0, 0}, {4, 0}, {4, 4}, {0, 4}}
+	b := Polygon2i{{2, 2}, {6, 2}, {6, 6}, {2, 6}}
+
+	res := PolygonBoolean2i([]Polygon2i{a}, []Polygon2i{b}, OpUnion, FillNonZero)
+	assert.Equal(t, []PolygonWithHoles2i{{
+		Outer: Polygon2i{{0, 0}, {4, 0}, {4, 2}, {6, 2}, {6, 6}, {2, 6}, {2, 4}, {0, 4}},
+	}}, res)
+
+	inner := Polygon2i{{1, 1}, {3, 1}, {3, 3}, {1, 3}}
+	res = PolygonBoolean2i([]Polygon2i{a}, []Polygon2i{inner}, OpDifference, FillNonZero)
+	assert.Equal(t, []PolygonWithHoles2i{{
+		Outer: a,
+		Holes: []Polygon2i{{{1, 1}, {1, 3}, {3, 3}, {3, 1}}},
+	}}, res)
+	assert.Equal(t, 16*2-4*2, res[0].Outer.DoubleSignedArea()+res[0].Holes[0].DoubleSignedArea())
+}
+
+func TestPolygon2f_ClipConvex(t *testing.T) {
+	square := Polygon2f{{0, 0}, {4, 0}, {4, 4}, {0, 4}}
+	clip := Polygon2f{{2, 2}, {6, 2}, {6, 6}, {2, 6}}
+
+	res := square.ClipConvex(clip)
+	assert.Equal(t, Polygon2f{{2, 2}, {4, 2}, {4, 4}, {2, 4}}, res)
+	assert.Equal(t, res, square.ClipConvex(clip.Reverse()))
+
+	// clockwise polygons stay clockwise
+	res = square.Reverse().ClipConvex(clip)
+	assert.False(t, res.IsCounterClockwise())
+	assert.Equal(t, float32(4), res.Area())
+
+	// triangle clip
+	res = square.ClipConvex(Polygon2f{{0, 0}, {4, 0}, {0, 4}})
+	assert.Equal(t, float32(8), res.Area())
+
+	// completely inside / outside
+	assert.Equal(t, square, square.ClipConvex(Polygon2f{{-1, -1}, {5, -1}, {5, 5}, {-1, 5}}))
+	assert.Nil(t, square.ClipConvex(Polygon2f{{5, 5}, {6, 5}, {6, 6}}))
+
+	// concave subject
+	u := Polygon2f{{0, 0}, {3, 0}, {3, 3}, {2, 3}, {2, 1}, {1, 1}, {1, 3}, {0, 3}}
+	res = u.ClipConvex(Polygon2f{{0, 2}, {3, 2}, {3, 4}, {0, 4}})
+	assert.InDelta(t, 2, res.Area(), 1e-6)
+}
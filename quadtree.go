@@ -0,0 +1,237 @@
+package vmath
+
+// Quadtree is a loose quadtree, which accelerates spatial queries over items with rectangular bounds.
+// Items are identified by integer IDs.
+//
+// Each node covers a quadrant of its parent's region. To prevent small items on quadrant borders
+// from getting stuck in upper levels, nodes accept all items that fit into their loose bounds,
+// which extend the node's region by half its size in each direction.
+// Nodes are split if they contain more items than the configured capacity, and merged if their items fit into a single node again.
+// Items outside the tree's bounds are stored within the root node.
+// Queries can run concurrently, but not while the tree is modified.
+type Quadtree struct {
+	tree   looseTree
+	cells  []quadCell // cell of each node within the tree
+	bounds map[int]Rectf
+}
+
+// quadCell is the region of a quadtree node.
+type quadCell struct {
+	cell  Rectf // region covered by the node
+	loose Rectf // region that contains all items of the subtree
+}
+
+// quadStackSize is the initial capacity of traversal stacks, which avoids allocations for reasonable tree depths.
+const quadStackSize = 64
+
+// NewQuadtree creates an empty quadtree covering the given bounds.
+// Nodes are split if they contain more than capacity items, but never beyond the maximum depth.
+// The root node has a depth of 0.
+func NewQuadtree(bounds Rectf, maxDepth, capacity int) *Quadtree {
+	q := &Quadtree{
+		cells:  []quadCell{newQuadCell(bounds)},
+		bounds: make(map[int]Rectf),
+	}
+	q.tree = newLooseTree(4, maxDepth, capacity, q)
+	return q
+}
+
+func newQuadCell(cell Rectf) quadCell {
+	half := cell.Size().MulScalar(0.5)
+	return quadCell{cell, Rectf{cell.Min.Sub(half), cell.Max.Add(half)}}
+}
+
+// Len returns the number of items within the tree.
+func (q *Quadtree) Len() int {
+	return len(q.bounds)
+}
+
+// Bounds returns the bounds of the item with the given ID.
+// Returns false if the item is not within the tree.
+func (q *Quadtree) Bounds(id int) (Rectf, bool) {
+	bounds, ok := q.bounds[id]
+	return bounds, ok
+}
+
+// Insert adds an item to the tree. If an item with the same ID already exists, it is updated.
+func (q *Quadtree) Insert(id int, bounds Rectf) {
+	if q.Update(id, bounds) {
+		return
+	}
+	q.bounds[id] = bounds
+	q.tree.insert(id)
+}
+
+// Remove removes the item with the given ID.
+// Returns false if the item is not within the tree.
+func (q *Quadtree) Remove(id int) bool {
+	if !q.tree.remove(id) {
+		return false
+	}
+	delete(q.bounds, id)
+	return true
+}
+
+// Update moves an item to new bounds.
+// Returns false if the item is not within the tree.
+func (q *Quadtree) Update(id int, bounds Rectf) bool {
+	if _, ok := q.bounds[id]; !ok {
+		return false
+	}
+	q.bounds[id] = bounds
+	q.tree.update(id)
+	return true
+}
+
+// child returns the child node that can store the item, or looseNull if the item needs to be stored in the node itself.
+func (q *Quadtree) child(node, id int) int {
+	children := q.tree.nodes[node].children
+	if children == looseNull {
+		return looseNull
+	}
+	bounds, cell := q.bounds[id], q.cells[node].cell
+	child := children
+	if bounds.Min[0]+bounds.Max[0] >= cell.Min[0]+cell.Max[0] {
+		child++
+	}
+	if bounds.Min[1]+bounds.Max[1] >= cell.Min[1]+cell.Max[1] {
+		child += 2
+	}
+	if !q.cells[child].loose.ContainsRectf(bounds) {
+		return looseNull
+	}
+	return child
+}
+
+// splitCell divides the node's cell into the quadrants of its children.
+func (q *Quadtree) splitCell(node, children int) {
+	for len(q.cells) < len(q.tree.nodes) {
+		q.cells = append(q.cells, quadCell{})
+	}
+	cell := q.cells[node].cell
+	center := cell.Min.Add(cell.Max).MulScalar(0.5)
+	q.cells[children] = newQuadCell(Rectf{cell.Min, center})
+	q.cells[children+1] = newQuadCell(Rectf{Vec2f{center[0], cell.Min[1]}, Vec2f{cell.Max[0], center[1]}})
+	q.cells[children+2] = newQuadCell(Rectf{Vec2f{cell.Min[0], center[1]}, Vec2f{center[0], cell.Max[1]}})
+	q.cells[children+3] = newQuadCell(Rectf{center, cell.Max})
+}
+
+// QueryRect appends the IDs of all items whose bounds intersect the given rectangle to dst, and returns the result.
+func (q *Quadtree) QueryRect(rect Rectf, dst []int) []int {
+	var buf [quadStackSize]int
+	stack := append(buf[:0], 0)
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		n := &q.tree.nodes[node]
+		if node != 0 && rect.ContainsRectf(q.cells[node].loose) {
+			dst = q.tree.appendItems(dst, node)
+			continue
+		}
+		for _, id := range n.items {
+			if q.bounds[id].Intersects(rect) {
+				dst = append(dst, id)
+			}
+		}
+		stack = q.pushChildren(stack, n, rect.Intersects)
+	}
+	return dst
+}
+
+// QueryPoint appends the IDs of all items whose bounds contain the given point to dst, and returns the result.
+func (q *Quadtree) QueryPoint(point Vec2f, dst []int) []int {
+	var buf [quadStackSize]int
+	stack := append(buf[:0], 0)
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		n := &q.tree.nodes[node]
+		for _, id := range n.items {
+			if q.bounds[id].ContainsPoint(point) {
+				dst = append(dst, id)
+			}
+		}
+		stack = q.pushChildren(stack, n, func(loose Rectf) bool {
+			return loose.ContainsPoint(point)
+		})
+	}
+	return dst
+}
+
+// QueryCircle appends the IDs of all items whose bounds intersect the given circle to dst, and returns the result.
+func (q *Quadtree) QueryCircle(circle Circle, dst []int) []int {
+	var buf [quadStackSize]int
+	stack := append(buf[:0], 0)
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		n := &q.tree.nodes[node]
+		for _, id := range n.items {
+			if circle.IntersectsRectf(q.bounds[id]) {
+				dst = append(dst, id)
+			}
+		}
+		stack = q.pushChildren(stack, n, circle.IntersectsRectf)
+	}
+	return dst
+}
+
+// pushChildren pushes all children whose loose bounds pass the given test onto the stack.
+func (q *Quadtree) pushChildren(stack []int, n *looseNode, test func(loose Rectf) bool) []int {
+	if n.children == looseNull {
+		return stack
+	}
+	for child := n.children; child < n.children+4; child++ {
+		if q.tree.nodes[child].count > 0 && test(q.cells[child].loose) {
+			stack = append(stack, child)
+		}
+	}
+	return stack
+}
+
+// KNearest appends the IDs of the k items closest to the given point to dst, and returns the result.
+// The distance of an item is the distance between its bounds and the point.
+// Items are ordered by increasing distance. If the tree contains less than k items, all items are returned.
+func (q *Quadtree) KNearest(point Vec2f, k int, dst []int) []int {
+	if k <= 0 {
+		return dst
+	}
+	var keyBuf [16]float32
+	res := kNearestResult{dst: dst, start: len(dst), keys: keyBuf[:0], k: k}
+
+	var buf [quadStackSize]int
+	stack := append(buf[:0], 0)
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		n := &q.tree.nodes[node]
+		if node != 0 && !res.accepts(q.cells[node].loose.SquarePointDistance(point)) {
+			continue
+		}
+
+		for _, id := range n.items {
+			res.add(id, q.bounds[id].SquarePointDistance(point))
+		}
+
+		if n.children == looseNull {
+			continue
+		}
+		// push the children in order of decreasing distance, so that the closest one is visited first
+		var order [4]int
+		var childDists [4]float32
+		for i := range order {
+			order[i] = n.children + i
+			childDists[i] = q.cells[order[i]].loose.SquarePointDistance(point)
+			for j := i; j > 0 && childDists[j-1] < childDists[j]; j-- {
+				order[j-1], order[j] = order[j], order[j-1]
+				childDists[j-1], childDists[j] = childDists[j], childDists[j-1]
+			}
+		}
+		for _, child := range order {
+			if q.tree.nodes[child].count > 0 {
+				stack = append(stack, child)
+			}
+		}
+	}
+	return res.dst
+}
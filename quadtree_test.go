@@ -0,0 +1,176 @@
+package vmath
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func randomRects(count int, seed int64) []Rectf {
+	rnd := rand.New(rand.NewSource(seed))
+	rects := make([]Rectf, count)
+	for i, p := range randomPoints2f(count, seed) {
+		rects[i] = RectfFromPosSize(p, Vec2f{rnd.Float32() * 2, rnd.Float32() * 2})
+	}
+	return rects
+}
+
+// assertValidQuadtree checks the tree's structure and that it contains exactly the given items.
+func assertValidQuadtree(t *testing.T, q *Quadtree, items map[int]Rectf) {
+	assert.Equal(t, len(items), q.Len())
+	tree := &q.tree
+	var check func(node int) int
+	check = func(node int) int {
+		n := tree.nodes[node]
+		count := len(n.items)
+		for _, id := range n.items {
+			assert.Equal(t, items[id], q.bounds[id])
+			assert.Equal(t, node, tree.items[id])
+			if node != 0 {
+				assert.True(t, q.cells[node].loose.ContainsRectf(items[id]))
+			}
+			assert.Equal(t, looseNull, q.child(node, id)) // stored in the deepest possible node
+		}
+		if n.children == looseNull {
+			assert.True(t, n.depth == tree.maxDepth || len(n.items) <= tree.capacity)
+		} else {
+			for i := 0; i < 4; i++ {
+				c := tree.nodes[n.children+i]
+				assert.Equal(t, node, c.parent)
+				assert.Equal(t, n.depth+1, c.depth)
+				assert.True(t, q.cells[node].cell.ContainsRectf(q.cells[n.children+i].cell))
+				count += check(n.children + i)
+			}
+			assert.True(t, count > tree.capacity)
+		}
+		assert.Equal(t, count, n.count)
+		return count
+	}
+	assert.Equal(t, len(items), check(0))
+}
+
+func TestQuadtree_InsertRemove(t *testing.T) {
+	q := NewQuadtree(Rectf{Vec2f{-10, -10}, Vec2f{10, 10}}, 6, 4)
+	items := make(map[int]Rectf)
+	for i, r := range randomRects(500, 1) {
+		q.Insert(i, r)
+		items[i] = r
+	}
+	// outside of the tree's bounds
+	q.Insert(1000, Rectf{Vec2f{20, 20}, Vec2f{21, 21}})
+	items[1000] = Rectf{Vec2f{20, 20}, Vec2f{21, 21}}
+	assertValidQuadtree(t, q, items)
+	assert.True(t, q.tree.nodes[0].children != looseNull)
+
+	for i := 0; i < 500; i += 2 {
+		assert.True(t, q.Remove(i))
+		delete(items, i)
+	}
+	assert.False(t, q.Remove(0))
+	assertValidQuadtree(t, q, items)
+
+	for i, r := range randomRects(500, 2) {
+		if i%2 == 1 {
+			assert.True(t, q.Update(i, r))
+		} else {
+			q.Insert(i, r)
+		}
+		items[i] = r
+	}
+	assert.False(t, q.Update(-1, Rectf{}))
+	assertValidQuadtree(t, q, items)
+
+	r, ok := q.Bounds(1000)
+	assert.True(t, ok)
+	assert.Equal(t, items[1000], r)
+	_, ok = q.Bounds(-1)
+	assert.False(t, ok)
+
+	// nodes are merged when they become empty
+	for id := range items {
+		q.Remove(id)
+	}
+	assertValidQuadtree(t, q, nil)
+	assert.Equal(t, looseNull, q.tree.nodes[0].children)
+}
+
+func TestQuadtree_MaxDepth(t *testing.T) {
+	q := NewQuadtree(Rectf{Vec2f{0, 0}, Vec2f{8, 8}}, 2, 1)
+	items := make(map[int]Rectf)
+	for i := 0; i < 10; i++ {
+		r := Rectf{Vec2f{1, 1}, Vec2f{1.1, 1.1}}
+		q.Insert(i, r)
+		items[i] = r
+	}
+	assertValidQuadtree(t, q, items)
+	node, ok := q.tree.items[0]
+	assert.True(t, ok)
+	assert.Equal(t, 2, q.tree.nodes[node].depth)
+}
+
+func TestQuadtree_Query(t *testing.T) {
+	rects := randomRects(1000, 3)
+	q := NewQuadtree(Rectf{Vec2f{-10, -10}, Vec2f{10, 10}}, 8, 8)
+	for i, r := range rects {
+		q.Insert(i, r)
+	}
+
+	query := func(test func(Rectf) bool) []int {
+		var ids []int
+		for i, r := range rects {
+			if test(r) {
+				ids = append(ids, i)
+			}
+		}
+		return ids
+	}
+	for _, rect := range randomRects(50, 4) {
+		rect = RectfFromPosSize(rect.Min, rect.Size().MulScalar(3))
+		assert.Equal(t, query(rect.Intersects), sortedIDs(q.QueryRect(rect, nil)))
+	}
+	assert.Len(t, q.QueryRect(Rectf{Vec2f{-20, -20}, Vec2f{20, 20}}, nil), len(rects))
+
+	for _, point := range randomPoints2f(50, 5) {
+		expected := query(func(r Rectf) bool { return r.ContainsPoint(point) })
+		assert.Equal(t, expected, sortedIDs(q.QueryPoint(point, nil)))
+
+		circle := Circle{point, 2}
+		assert.Equal(t, query(circle.IntersectsRectf), sortedIDs(q.QueryCircle(circle, nil)))
+	}
+}
+
+func TestQuadtree_KNearest(t *testing.T) {
+	rects := randomRects(1000, 6)
+	q := NewQuadtree(Rectf{Vec2f{-10, -10}, Vec2f{10, 10}}, 8, 8)
+	for i, r := range rects {
+		q.Insert(i, r)
+	}
+
+	for _, point := range randomPoints2f(50, 7) {
+		point = point.MulScalar(1.5)
+		dists := make([]float32, len(rects))
+		for i, r := range rects {
+			dists[i] = r.SquarePointDistance(point)
+		}
+		sort.Slice(dists, func(i, j int) bool { return dists[i] < dists[j] })
+
+		nearest := q.KNearest(point, 20, []int{-1})
+		assert.Len(t, nearest, 21)
+		assert.Equal(t, -1, nearest[0])
+		for i, id := range nearest[1:] {
+			assert.Equal(t, dists[i], rects[id].SquarePointDistance(point))
+		}
+	}
+
+	assert.Len(t, q.KNearest(Vec2f{}, 2000, nil), len(rects))
+	assert.Empty(t, q.KNearest(Vec2f{}, 0, nil))
+	assert.Empty(t, NewQuadtree(Rectf{}, 4, 4).KNearest(Vec2f{}, 3, nil))
+}
+
+// sortedIDs sorts the IDs in-place and returns them.
+func sortedIDs(ids []int) []int {
+	sort.Ints(ids)
+	return ids
+}
@@ -49,6 +49,46 @@ func QuatFromEuler(yaw, pitch, roll float32) Quat {
 	}
 }
 
+// QuatFromMat3f returns a quaternion representing the rotation of the given rotation matrix.
+// The matrix must be orthonormal and must not contain scaling.
+func QuatFromMat3f(m Mat3f) Quat {
+	// Source: "Quaternion Calculus and Fast Animation" by Ken Shoemake, SIGGRAPH course notes, 1987.
+	m00, m11, m22 := m[0], m[4], m[8]
+	trace := m00 + m11 + m22
+	if trace > 0 {
+		s := math32.Sqrt(trace+1) * 2
+		return Quat{
+			0.25 * s,
+			(m[5] - m[7]) / s,
+			(m[6] - m[2]) / s,
+			(m[1] - m[3]) / s,
+		}
+	} else if m00 > m11 && m00 > m22 {
+		s := math32.Sqrt(1+m00-m11-m22) * 2
+		return Quat{
+			(m[5] - m[7]) / s,
+			0.25 * s,
+			(m[3] + m[1]) / s,
+			(m[6] + m[2]) / s,
+		}
+	} else if m11 > m22 {
+		s := math32.Sqrt(1+m11-m00-m22) * 2
+		return Quat{
+			(m[6] - m[2]) / s,
+			(m[3] + m[1]) / s,
+			0.25 * s,
+			(m[7] + m[5]) / s,
+		}
+	}
+	s := math32.Sqrt(1+m22-m00-m11) * 2
+	return Quat{
+		(m[1] - m[3]) / s,
+		(m[6] + m[2]) / s,
+		(m[7] + m[5]) / s,
+		0.25 * s,
+	}
+}
+
 // Equals compares two quaternions.
 // Uses the default Epsilon as relative tolerance.
 func (q Quat) Equals(other Quat) bool {
@@ -269,6 +309,15 @@ func (q Quat) Mat4f() Mat4f {
 	}
 }
 
+// Mat3f returns a 3D rotation matrix based on the quaternion.
+func (q Quat) Mat3f() Mat3f {
+	return Mat3f{
+		1 - 2*q.Y*q.Y - 2*q.Z*q.Z, 2*q.X*q.Y + 2*q.W*q.Z, 2*q.X*q.Z - 2*q.W*q.Y,
+		2*q.X*q.Y - 2*q.W*q.Z, 1 - 2*q.X*q.X - 2*q.Z*q.Z, 2*q.Y*q.Z + 2*q.W*q.X,
+		2*q.X*q.Z + 2*q.W*q.Y, 2*q.Y*q.Z - 2*q.W*q.X, 1 - 2*q.X*q.X - 2*q.Y*q.Y,
+	}
+}
+
 // RotateVec rotates a vector.
 func (q Quat) RotateVec(v Vec3f) Vec3f {
 	// Source: https://gamedev.stackexchange.com/a/50545/39091
@@ -234,3 +234,24 @@ func TestQuat_AngleTo(t *testing.T) {
 	quatC := QuatFromAxisAngle(Vec3f{0, 1, 0}, deg90)
 	AssertFloat(t, deg90, quatA.AngleTo(quatC))
 }
+
+func TestQuat_Mat3f(t *testing.T) {
+	quats := []Quat{
+		IdentQuat(),
+		QuatFromAxisAngle(Vec3f{1, 0, 0}, deg90),
+		QuatFromAxisAngle(Vec3f{0, 1, 0}, deg180),
+		QuatFromAxisAngle(Vec3f{1, 2, 3}, 2.5),
+	}
+	for _, q := range quats {
+		m := q.Mat3f()
+		v := Vec3f{1, -2, 3}
+		AssertVec3f(t, q.RotateVec(v), m.MulVec(v))
+
+		// quaternions q and -q represent the same rotation
+		res := QuatFromMat3f(m)
+		if res.Dot(q) < 0 {
+			res = res.MulScalar(-1)
+		}
+		AssertQuat(t, q, res)
+	}
+}
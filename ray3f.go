@@ -0,0 +1,221 @@
+package vmath
+
+import (
+	"fmt"
+
+	"github.com/maja42/vmath/math32"
+)
+
+// Ray3f represents a half-line in 3D space, starting at Origin and extending into direction Dir.
+// The direction does not need to be normalized.
+// If it is, intersection distances are euclidean distances.
+type Ray3f struct {
+	Origin Vec3f
+	Dir    Vec3f
+}
+
+// RayHit describes where a ray hits a surface.
+type RayHit struct {
+	// Distance is the ray parameter t of the hit position (Origin + t*Dir).
+	// It is measured in multiples of the ray's direction length.
+	Distance float32
+	// Point is the hit position.
+	Point Vec3f
+	// Normal is the normalized surface normal at the hit position.
+	Normal Vec3f
+}
+
+// Ray3fFromPoints creates a new ray that starts at "from" and passes through "to".
+// The ray's direction is normalized.
+func Ray3fFromPoints(from, to Vec3f) Ray3f {
+	return Ray3f{
+		Origin: from,
+		Dir:    to.Sub(from).Normalize(),
+	}
+}
+
+func (r Ray3f) String() string {
+	return fmt.Sprintf("Ray3f([%f x %f x %f]->[%f x %f x %f])",
+		r.Origin[0], r.Origin[1], r.Origin[2],
+		r.Dir[0], r.Dir[1], r.Dir[2])
+}
+
+// At returns the position on the ray with the given ray parameter.
+func (r Ray3f) At(t float32) Vec3f {
+	return r.Origin.Add(r.Dir.MulScalar(t))
+}
+
+// Normalize returns a ray with a normalized direction.
+func (r Ray3f) Normalize() Ray3f {
+	r.Dir = r.Dir.Normalize()
+	return r
+}
+
+// Transform applies an affine transformation matrix to the ray.
+// The direction is transformed without translation and is not re-normalized,
+// so that ray parameters of hits stay the same in both coordinate systems.
+func (r Ray3f) Transform(m Mat4f) Ray3f {
+	return Ray3f{
+		Origin: m.MulVec(r.Origin.Vec4f(1)).XYZ(),
+		Dir:    m.MulVec(r.Dir.Vec4f(0)).XYZ(),
+	}
+}
+
+// ClosestPoint returns the position on the ray that is closest to the given point, and its ray parameter.
+func (r Ray3f) ClosestPoint(point Vec3f) (Vec3f, float32) {
+	sqLen := r.Dir.SquareLength()
+	if sqLen == 0 {
+		return r.Origin, 0
+	}
+	t := point.Sub(r.Origin).Dot(r.Dir) / sqLen
+	if t < 0 {
+		t = 0
+	}
+	return r.At(t), t
+}
+
+// IntersectPlane calculates the intersection with a plane, given by a point on the plane and its normal.
+// Both sides of the plane can be hit. The returned normal is the normalized plane normal.
+// Returns false if the ray is parallel to the plane or points away from it.
+func (r Ray3f) IntersectPlane(planePoint, planeNormal Vec3f) (RayHit, bool) {
+	denom := planeNormal.Dot(r.Dir)
+	if Equalf(denom, 0) {
+		return RayHit{}, false
+	}
+	t := planePoint.Sub(r.Origin).Dot(planeNormal) / denom
+	if t < 0 {
+		return RayHit{}, false
+	}
+	return RayHit{
+		Distance: t,
+		Point:    r.At(t),
+		Normal:   planeNormal.Normalize(),
+	}, true
+}
+
+// IntersectSphere calculates the first intersection with a sphere.
+// If the ray starts within the sphere, the exit position is returned.
+// The returned normal always points away from the sphere's center.
+func (r Ray3f) IntersectSphere(center Vec3f, radius float32) (RayHit, bool) {
+	// Source: "Real-Time Collision Detection" by Christer Ericson, chapter 5.3.2
+	// Solves |Origin + t*Dir - center|² = radius² for t.
+	a := r.Dir.SquareLength()
+	if a == 0 {
+		return RayHit{}, false
+	}
+	m := r.Origin.Sub(center)
+	b := m.Dot(r.Dir)
+	c := m.SquareLength() - radius*radius
+	if c > 0 && b > 0 { // origin outside the sphere and pointing away
+		return RayHit{}, false
+	}
+	discr := b*b - a*c
+	if discr < 0 {
+		return RayHit{}, false
+	}
+	sqrtDiscr := math32.Sqrt(discr)
+	t := (-b - sqrtDiscr) / a
+	if t < 0 { // origin inside the sphere
+		t = (-b + sqrtDiscr) / a
+	}
+	point := r.At(t)
+	return RayHit{
+		Distance: t,
+		Point:    point,
+		Normal:   point.Sub(center).Normalize(),
+	}, true
+}
+
+// IntersectTriangle calculates the intersection with a triangle, using the Möller–Trumbore algorithm.
+// Both sides of the triangle can be hit.
+// The returned normal is the normalized face normal, oriented according to the counter-clockwise winding order of a, b and c.
+func (r Ray3f) IntersectTriangle(a, b, c Vec3f) (RayHit, bool) {
+	// Source: "Fast, Minimum Storage Ray/Triangle Intersection" by T. Möller and B. Trumbore, 1997.
+	edge1 := b.Sub(a)
+	edge2 := c.Sub(a)
+
+	pvec := r.Dir.Cross(edge2)
+	det := edge1.Dot(pvec)
+	if Equalf(det, 0) { // ray is parallel to the triangle's plane
+		return RayHit{}, false
+	}
+	invDet := 1 / det
+
+	tvec := r.Origin.Sub(a)
+	u := tvec.Dot(pvec) * invDet
+	if u < 0 || u > 1 {
+		return RayHit{}, false
+	}
+
+	qvec := tvec.Cross(edge1)
+	v := r.Dir.Dot(qvec) * invDet
+	if v < 0 || u+v > 1 {
+		return RayHit{}, false
+	}
+
+	t := edge2.Dot(qvec) * invDet
+	if t < 0 {
+		return RayHit{}, false
+	}
+	return RayHit{
+		Distance: t,
+		Point:    r.At(t),
+		Normal:   edge1.Cross(edge2).Normalize(),
+	}, true
+}
+
+// IntersectBox calculates the first intersection with an axis-aligned box, given by its min and max corner.
+// If the ray starts within the box, the exit position is returned.
+// The returned normal always points outwards of the box.
+func (r Ray3f) IntersectBox(min, max Vec3f) (RayHit, bool) {
+	// Source: "Real-Time Collision Detection" by Christer Ericson, chapter 5.3.3 (slab method)
+	tMin := math32.NegInfinity
+	tMax := math32.Infinity
+	minAxis, maxAxis := -1, -1
+
+	for i := 0; i < 3; i++ {
+		if r.Dir[i] == 0 {
+			// parallel to the slab; no hit if the origin is not within the slab
+			if r.Origin[i] < min[i] || r.Origin[i] > max[i] {
+				return RayHit{}, false
+			}
+			continue
+		}
+		invD := 1 / r.Dir[i]
+		t1 := (min[i] - r.Origin[i]) * invD
+		t2 := (max[i] - r.Origin[i]) * invD
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		if t1 > tMin {
+			tMin = t1
+			minAxis = i
+		}
+		if t2 < tMax {
+			tMax = t2
+			maxAxis = i
+		}
+		if tMin > tMax {
+			return RayHit{}, false
+		}
+	}
+	if tMax < 0 || maxAxis < 0 { // box is behind the ray, or the direction is zero
+		return RayHit{}, false
+	}
+
+	var normal Vec3f
+	t := tMin
+	if tMin >= 0 {
+		// entering the box: the normal is facing against the ray
+		normal[minAxis] = -math32.Copysign(1, r.Dir[minAxis])
+	} else {
+		// origin within the box: leaving it
+		t = tMax
+		normal[maxAxis] = math32.Copysign(1, r.Dir[maxAxis])
+	}
+	return RayHit{
+		Distance: t,
+		Point:    r.At(t),
+		Normal:   normal,
+	}, true
+}
@@ -0,0 +1,163 @@
+package vmath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRay3fFromPoints(t *testing.T) {
+	r := Ray3fFromPoints(Vec3f{1, 2, 3}, Vec3f{1, 2, 8})
+	AssertVec3f(t, Vec3f{1, 2, 3}, r.Origin)
+	AssertVec3f(t, Vec3f{0, 0, 1}, r.Dir)
+}
+
+func TestRay3f_String(t *testing.T) {
+	r := Ray3f{Vec3f{1, 2, 3}, Vec3f{0, -1, 0}}
+	assert.Equal(t, "Ray3f([1.000000 x 2.000000 x 3.000000]->[0.000000 x -1.000000 x 0.000000])", r.String())
+}
+
+func TestRay3f_At(t *testing.T) {
+	r := Ray3f{Vec3f{1, 2, 3}, Vec3f{0, 2, 0}}
+	AssertVec3f(t, Vec3f{1, 2, 3}, r.At(0))
+	AssertVec3f(t, Vec3f{1, 8, 3}, r.At(3))
+	AssertVec3f(t, Vec3f{1, 0, 3}, r.At(-1))
+}
+
+func TestRay3f_Transform(t *testing.T) {
+	r := Ray3f{Vec3f{1, 0, 0}, Vec3f{0, 1, 0}}
+	m := Mat4fFromTranslation(Vec3f{0, 0, 5}).Mul(Mat4fFromScaling(Vec3f{2, 2, 2}))
+
+	tr := r.Transform(m)
+	AssertVec3f(t, Vec3f{2, 0, 5}, tr.Origin)
+	AssertVec3f(t, Vec3f{0, 2, 0}, tr.Dir)
+	// ray parameters are retained
+	AssertVec3f(t, m.MulVec(r.At(3).Vec4f(1)).XYZ(), tr.At(3))
+}
+
+func TestRay3f_ClosestPoint(t *testing.T) {
+	r := Ray3f{Vec3f{0, 0, 0}, Vec3f{2, 0, 0}}
+	p, tp := r.ClosestPoint(Vec3f{4, 3, 0})
+	AssertVec3f(t, Vec3f{4, 0, 0}, p)
+	AssertFloat(t, 2, tp)
+
+	p, tp = r.ClosestPoint(Vec3f{-4, 3, 0})
+	AssertVec3f(t, Vec3f{0, 0, 0}, p)
+	AssertFloat(t, 0, tp)
+}
+
+func TestRay3f_IntersectPlane(t *testing.T) {
+	r := Ray3f{Vec3f{0, 5, 0}, Vec3f{0, -1, 0}}
+
+	hit, ok := r.IntersectPlane(Vec3f{3, 1, 3}, Vec3f{0, 2, 0})
+	assert.True(t, ok)
+	AssertFloat(t, 4, hit.Distance)
+	AssertVec3f(t, Vec3f{0, 1, 0}, hit.Point)
+	AssertVec3f(t, Vec3f{0, 1, 0}, hit.Normal)
+
+	// hitting the plane from below
+	hit, ok = r.IntersectPlane(Vec3f{0, -1, 0}, Vec3f{0, -1, 0})
+	assert.True(t, ok)
+	AssertFloat(t, 6, hit.Distance)
+
+	// plane behind the ray
+	_, ok = r.IntersectPlane(Vec3f{0, 6, 0}, Vec3f{0, 1, 0})
+	assert.False(t, ok)
+	// parallel
+	_, ok = r.IntersectPlane(Vec3f{1, 0, 0}, Vec3f{1, 0, 0})
+	assert.False(t, ok)
+}
+
+func TestRay3f_IntersectSphere(t *testing.T) {
+	r := Ray3f{Vec3f{-10, 0, 0}, Vec3f{1, 0, 0}}
+
+	hit, ok := r.IntersectSphere(Vec3f{0, 0, 0}, 2)
+	assert.True(t, ok)
+	AssertFloat(t, 8, hit.Distance)
+	AssertVec3f(t, Vec3f{-2, 0, 0}, hit.Point)
+	AssertVec3f(t, Vec3f{-1, 0, 0}, hit.Normal)
+
+	// non-normalized direction
+	hit, ok = Ray3f{Vec3f{-10, 0, 0}, Vec3f{4, 0, 0}}.IntersectSphere(Vec3f{0, 0, 0}, 2)
+	assert.True(t, ok)
+	AssertFloat(t, 2, hit.Distance)
+	AssertVec3f(t, Vec3f{-2, 0, 0}, hit.Point)
+
+	// origin inside the sphere
+	hit, ok = Ray3f{Vec3f{0, 0, 0}, Vec3f{0, 1, 0}}.IntersectSphere(Vec3f{0, 0, 0}, 2)
+	assert.True(t, ok)
+	AssertFloat(t, 2, hit.Distance)
+	AssertVec3f(t, Vec3f{0, 1, 0}, hit.Normal)
+
+	// missed
+	_, ok = r.IntersectSphere(Vec3f{0, 3, 0}, 2)
+	assert.False(t, ok)
+	// behind
+	_, ok = r.IntersectSphere(Vec3f{-20, 0, 0}, 2)
+	assert.False(t, ok)
+}
+
+func TestRay3f_IntersectTriangle(t *testing.T) {
+	a, b, c := Vec3f{0, 0, 0}, Vec3f{4, 0, 0}, Vec3f{0, 4, 0}
+	r := Ray3f{Vec3f{1, 1, 5}, Vec3f{0, 0, -1}}
+
+	hit, ok := r.IntersectTriangle(a, b, c)
+	assert.True(t, ok)
+	AssertFloat(t, 5, hit.Distance)
+	AssertVec3f(t, Vec3f{1, 1, 0}, hit.Point)
+	AssertVec3f(t, Vec3f{0, 0, 1}, hit.Normal)
+
+	// back-face
+	hit, ok = Ray3f{Vec3f{1, 1, -5}, Vec3f{0, 0, 1}}.IntersectTriangle(a, b, c)
+	assert.True(t, ok)
+	AssertFloat(t, 5, hit.Distance)
+	AssertVec3f(t, Vec3f{0, 0, 1}, hit.Normal)
+
+	// outside of the triangle
+	_, ok = Ray3f{Vec3f{3, 3, 5}, Vec3f{0, 0, -1}}.IntersectTriangle(a, b, c)
+	assert.False(t, ok)
+	// behind
+	_, ok = Ray3f{Vec3f{1, 1, 5}, Vec3f{0, 0, 1}}.IntersectTriangle(a, b, c)
+	assert.False(t, ok)
+	// parallel
+	_, ok = Ray3f{Vec3f{1, 1, 5}, Vec3f{1, 0, 0}}.IntersectTriangle(a, b, c)
+	assert.False(t, ok)
+}
+
+func TestRay3f_IntersectBox(t *testing.T) {
+	min, max := Vec3f{-1, -1, -1}, Vec3f{1, 1, 1}
+
+	hit, ok := Ray3f{Vec3f{-5, 0, 0}, Vec3f{1, 0, 0}}.IntersectBox(min, max)
+	assert.True(t, ok)
+	AssertFloat(t, 4, hit.Distance)
+	AssertVec3f(t, Vec3f{-1, 0, 0}, hit.Point)
+	AssertVec3f(t, Vec3f{-1, 0, 0}, hit.Normal)
+
+	hit, ok = Ray3f{Vec3f{0.5, 5, 0.5}, Vec3f{0, -2, 0}}.IntersectBox(min, max)
+	assert.True(t, ok)
+	AssertFloat(t, 2, hit.Distance)
+	AssertVec3f(t, Vec3f{0.5, 1, 0.5}, hit.Point)
+	AssertVec3f(t, Vec3f{0, 1, 0}, hit.Normal)
+
+	// diagonal
+	hit, ok = Ray3f{Vec3f{-3, -2, 0}, Vec3f{1, 1, 0}}.IntersectBox(min, max)
+	assert.True(t, ok)
+	AssertFloat(t, 2, hit.Distance)
+	AssertVec3f(t, Vec3f{-1, 0, 0}, hit.Point)
+	AssertVec3f(t, Vec3f{-1, 0, 0}, hit.Normal)
+
+	// origin inside the box
+	hit, ok = Ray3f{Vec3f{0, 0, 0}, Vec3f{0, 0, -1}}.IntersectBox(min, max)
+	assert.True(t, ok)
+	AssertFloat(t, 1, hit.Distance)
+	AssertVec3f(t, Vec3f{0, 0, -1}, hit.Normal)
+
+	// missed
+	_, ok = Ray3f{Vec3f{-5, 2, 0}, Vec3f{1, 0, 0}}.IntersectBox(min, max)
+	assert.False(t, ok)
+	_, ok = Ray3f{Vec3f{-5, 0, 0}, Vec3f{1, 5, 0}}.IntersectBox(min, max)
+	assert.False(t, ok)
+	// behind
+	_, ok = Ray3f{Vec3f{5, 0, 0}, Vec3f{1, 0, 0}}.IntersectBox(min, max)
+	assert.False(t, ok)
+}
@@ -93,6 +93,11 @@ func (r Rectf) Area() float32 {
 	return size[0] * size[1]
 }
 
+// Polygon2f returns the rectangle's corners as polygon in counter-clockwise order, starting with the minimum.
+func (r Rectf) Polygon2f() Polygon2f {
+	return Polygon2f{r.Min, {r.Max[0], r.Min[1]}, r.Max, {r.Min[0], r.Max[1]}}
+}
+
 // Left returns the rectangle's left position (smaller X).
 func (r Rectf) Left() float32 {
 	return r.Min[0]
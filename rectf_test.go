@@ -181,3 +181,10 @@ func TestRectf_PointDistance(t *testing.T) {
 	// above + right
 	AssertFloat(t, math32.Sqrt(37), r.PointDistance(Vec2f{16, 11}))
 }
+
+func TestRectf_Polygon2f(t *testing.T) {
+	r := Rectf{Vec2f{1, 2}, Vec2f{4, 6}}
+	p := r.Polygon2f()
+	assert.Equal(t, Polygon2f{{1, 2}, {4, 2}, {4, 6}, {1, 6}}, p)
+	assert.True(t, p.IsCounterClockwise())
+}
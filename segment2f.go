@@ -0,0 +1,250 @@
+package vmath
+
+import (
+	"fmt"
+
+	"github.com/maja42/vmath/math32"
+)
+
+// Segment2f represents a line segment on the 2D plane, going from A to B.
+type Segment2f struct {
+	A, B Vec2f
+}
+
+// SegmentIntersection describes where two line segments intersect.
+type SegmentIntersection struct {
+	// Count is the number of intersection points.
+	// 0 if the segments do not intersect, 1 if they cross or touch at a single point,
+	// and 2 if the segments are collinear and overlap. In that case, the points are the start and end of the overlap.
+	Count int
+	// Points contains the intersection points.
+	Points [2]Vec2f
+	// T contains the intersection points' parameters along the first segment, in range [0, 1].
+	T [2]float32
+	// U contains the intersection points' parameters along the second segment, in range [0, 1].
+	U [2]float32
+}
+
+func (s Segment2f) String() string {
+	return fmt.Sprintf("Segment2f([%f x %f]-[%f x %f])", s.A[0], s.A[1], s.B[0], s.B[1])
+}
+
+// Dir returns the (non-normalized) direction from A to B.
+func (s Segment2f) Dir() Vec2f {
+	return s.B.Sub(s.A)
+}
+
+// Length returns the segment's length.
+func (s Segment2f) Length() float32 {
+	return s.A.Distance(s.B)
+}
+
+// SquareLength returns the segment's squared length.
+func (s Segment2f) SquareLength() float32 {
+	return s.A.SquareDistance(s.B)
+}
+
+// Center returns the segment's midpoint.
+func (s Segment2f) Center() Vec2f {
+	return s.A.Lerp(s.B, 0.5)
+}
+
+// At returns the point A + t*(B-A).
+// t=0 returns A, t=1 returns B.
+func (s Segment2f) At(t float32) Vec2f {
+	return s.A.Lerp(s.B, t)
+}
+
+// Rectf returns the smallest axis-aligned rectangle that contains the segment.
+func (s Segment2f) Rectf() Rectf {
+	return RectfFromCorners(s.A, s.B)
+}
+
+// ClosestPoint returns the point on the segment that is closest to the given point,
+// as well as its segment parameter t in range [0, 1].
+func (s Segment2f) ClosestPoint(point Vec2f) (Vec2f, float32) {
+	ab := s.Dir()
+	sqLen := ab.SquareLength()
+	if sqLen == 0 {
+		return s.A, 0
+	}
+	t := Clampf(point.Sub(s.A).Dot(ab)/sqLen, 0, 1)
+	return s.A.Add(ab.MulScalar(t)), t
+}
+
+// PointDistance returns the distance between the segment and a point.
+func (s Segment2f) PointDistance(point Vec2f) float32 {
+	return PointToLineSegmentDistance2D(s.A, s.B, point)
+}
+
+// Intersect calculates where two segments intersect.
+// Segments that touch are also considered to intersect.
+// Uses the default Epsilon as relative tolerance for detecting parallel and collinear segments.
+func (s Segment2f) Intersect(other Segment2f) SegmentIntersection {
+	// Source: https://stackoverflow.com/a/565282/2224996
+	r := s.Dir()
+	q := other.Dir()
+	w := other.A.Sub(s.A)
+
+	rr := r.SquareLength()
+	qq := q.SquareLength()
+	if rr == 0 || qq == 0 {
+		return s.intersectDegenerate(other)
+	}
+
+	if !r.IsParallel(q) {
+		denom := r.MagCross(q)
+		t := w.MagCross(q) / denom
+		u := w.MagCross(r) / denom
+		if t < 0 || t > 1 || u < 0 || u > 1 {
+			return SegmentIntersection{}
+		}
+		return SegmentIntersection{
+			Count:  1,
+			Points: [2]Vec2f{s.At(t)},
+			T:      [2]float32{t},
+			U:      [2]float32{u},
+		}
+	}
+
+	if !IsPointOnLine(s.A, s.B, other.A) {
+		return SegmentIntersection{} // parallel, but not collinear
+	}
+
+	// collinear: project the other segment onto this one
+	t0 := w.Dot(r) / rr
+	t1 := t0 + q.Dot(r)/rr
+	if t0 > t1 {
+		t0, t1 = t1, t0
+	}
+	t0 = math32.Max(t0, 0)
+	t1 = math32.Min(t1, 1)
+	if t0 > t1 {
+		return SegmentIntersection{}
+	}
+
+	// parameter along the other segment
+	param := func(p Vec2f) float32 {
+		return Clampf(p.Sub(other.A).Dot(q)/qq, 0, 1)
+	}
+	p0 := s.At(t0)
+	res := SegmentIntersection{
+		Count:  1,
+		Points: [2]Vec2f{p0},
+		T:      [2]float32{t0},
+		U:      [2]float32{param(p0)},
+	}
+	if t0 < t1 {
+		p1 := s.At(t1)
+		res.Count = 2
+		res.Points[1] = p1
+		res.T[1] = t1
+		res.U[1] = param(p1)
+	}
+	return res
+}
+
+// intersectDegenerate calculates the intersection if at least one of the segments is a single point.
+func (s Segment2f) intersectDegenerate(other Segment2f) SegmentIntersection {
+	if s.A == s.B {
+		p, u := other.ClosestPoint(s.A)
+		if !p.Equal(s.A) {
+			return SegmentIntersection{}
+		}
+		return SegmentIntersection{
+			Count:  1,
+			Points: [2]Vec2f{s.A},
+			U:      [2]float32{u},
+		}
+	}
+	p, t := s.ClosestPoint(other.A)
+	if !p.Equal(other.A) {
+		return SegmentIntersection{}
+	}
+	return SegmentIntersection{
+		Count:  1,
+		Points: [2]Vec2f{other.A},
+		T:      [2]float32{t},
+	}
+}
+
+// Intersects checks if two segments intersect.
+// Segments that touch are also considered to intersect.
+func (s Segment2f) Intersects(other Segment2f) bool {
+	return s.Intersect(other).Count > 0
+}
+
+// ClipRectf clips the segment to the inside of a rectangle.
+// Returns false if the segment is completely outside of the rectangle.
+func (s Segment2f) ClipRectf(rect Rectf) (Segment2f, bool) {
+	// Source: "A New Concept and Method for Line Clipping" by Y. Liang and B. Barsky, 1984.
+	d := s.Dir()
+	t0, t1 := float32(0), float32(1)
+
+	clip := func(p, q float32) bool {
+		if p == 0 { // parallel to the edge
+			return q >= 0
+		}
+		r := q / p
+		if p < 0 { // entering
+			if r > t1 {
+				return false
+			}
+			t0 = math32.Max(t0, r)
+		} else { // leaving
+			if r < t0 {
+				return false
+			}
+			t1 = math32.Min(t1, r)
+		}
+		return true
+	}
+
+	if clip(-d[0], s.A[0]-rect.Min[0]) &&
+		clip(d[0], rect.Max[0]-s.A[0]) &&
+		clip(-d[1], s.A[1]-rect.Min[1]) &&
+		clip(d[1], rect.Max[1]-s.A[1]) {
+		return Segment2f{s.At(t0), s.At(t1)}, true
+	}
+	return s, false
+}
+
+// IntersectCircle calculates where the segment crosses the circle's edge.
+// Returns the number of intersection points (0, 1 or 2), the points and their segment parameters, sorted by t.
+// If the segment is completely within the circle, there are no intersection points.
+func (s Segment2f) IntersectCircle(circle Circle) (int, [2]Vec2f, [2]float32) {
+	// Source: "Real-Time Collision Detection" by Christer Ericson, chapter 5.3.2
+	var points [2]Vec2f
+	var ts [2]float32
+
+	d := s.Dir()
+	m := s.A.Sub(circle.Center)
+	a := d.Dot(d)
+	if a == 0 {
+		return 0, points, ts
+	}
+	b := m.Dot(d)
+	c := m.Dot(m) - circle.Radius*circle.Radius
+	discr := b*b - a*c
+	if discr < 0 {
+		return 0, points, ts
+	}
+	sqrtDiscr := math32.Sqrt(discr)
+
+	count := 0
+	for _, t := range [2]float32{(-b - sqrtDiscr) / a, (-b + sqrtDiscr) / a} {
+		if t < 0 || t > 1 || (count == 1 && t == ts[0]) {
+			continue
+		}
+		points[count] = s.At(t)
+		ts[count] = t
+		count++
+	}
+	return count, points, ts
+}
+
+// IntersectsCircle checks if the segment intersects a circle.
+// Segments that are completely within the circle, or touch it, are also considered to intersect.
+func (s Segment2f) IntersectsCircle(circle Circle) bool {
+	return s.PointDistance(circle.Center) <= circle.Radius
+}
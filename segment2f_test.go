@@ -0,0 +1,147 @@
+package vmath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegment2f_String(t *testing.T) {
+	s := Segment2f{Vec2f{1, 2}, Vec2f{3, 4}}
+	assert.Equal(t, "Segment2f([1.000000 x 2.000000]-[3.000000 x 4.000000])", s.String())
+}
+
+func TestSegment2f_Properties(t *testing.T) {
+	s := Segment2f{Vec2f{1, 2}, Vec2f{4, 6}}
+	AssertVec2f(t, Vec2f{3, 4}, s.Dir())
+	AssertFloat(t, 5, s.Length())
+	AssertFloat(t, 25, s.SquareLength())
+	AssertVec2f(t, Vec2f{2.5, 4}, s.Center())
+	AssertVec2f(t, Vec2f{4, 6}, s.At(1))
+	assert.Equal(t, Rectf{Vec2f{1, 2}, Vec2f{4, 6}}, Segment2f{Vec2f{4, 2}, Vec2f{1, 6}}.Rectf())
+}
+
+func TestSegment2f_ClosestPoint(t *testing.T) {
+	s := Segment2f{Vec2f{0, 0}, Vec2f{10, 0}}
+
+	p, tp := s.ClosestPoint(Vec2f{4, 3})
+	AssertVec2f(t, Vec2f{4, 0}, p)
+	AssertFloat(t, 0.4, tp)
+	AssertFloat(t, 3, s.PointDistance(Vec2f{4, 3}))
+
+	p, tp = s.ClosestPoint(Vec2f{-4, 3})
+	AssertVec2f(t, Vec2f{0, 0}, p)
+	AssertFloat(t, 0, tp)
+	AssertFloat(t, 5, s.PointDistance(Vec2f{-4, 3}))
+}
+
+func TestSegment2f_Intersect(t *testing.T) {
+	s := Segment2f{Vec2f{0, 0}, Vec2f{10, 0}}
+
+	// proper crossing
+	res := s.Intersect(Segment2f{Vec2f{4, -2}, Vec2f{4, 6}})
+	assert.Equal(t, 1, res.Count)
+	AssertVec2f(t, Vec2f{4, 0}, res.Points[0])
+	AssertFloat(t, 0.4, res.T[0])
+	AssertFloat(t, 0.25, res.U[0])
+	assert.True(t, s.Intersects(Segment2f{Vec2f{4, -2}, Vec2f{4, 6}}))
+
+	// touching at an endpoint
+	res = s.Intersect(Segment2f{Vec2f{10, 0}, Vec2f{12, 5}})
+	assert.Equal(t, 1, res.Count)
+	AssertVec2f(t, Vec2f{10, 0}, res.Points[0])
+	AssertFloat(t, 1, res.T[0])
+	AssertFloat(t, 0, res.U[0])
+
+	// lines cross outside of the segments
+	assert.Equal(t, 0, s.Intersect(Segment2f{Vec2f{4, 1}, Vec2f{4, 6}}).Count)
+	assert.False(t, s.Intersects(Segment2f{Vec2f{11, -1}, Vec2f{11, 1}}))
+
+	// parallel
+	assert.Equal(t, 0, s.Intersect(Segment2f{Vec2f{0, 1}, Vec2f{10, 1}}).Count)
+
+	// collinear, overlapping
+	res = s.Intersect(Segment2f{Vec2f{12, 0}, Vec2f{6, 0}})
+	assert.Equal(t, 2, res.Count)
+	AssertVec2f(t, Vec2f{6, 0}, res.Points[0])
+	AssertVec2f(t, Vec2f{10, 0}, res.Points[1])
+	AssertFloat(t, 0.6, res.T[0])
+	AssertFloat(t, 1, res.T[1])
+	AssertFloat(t, 1, res.U[0])
+	AssertFloat(t, 1.0/3.0, res.U[1])
+
+	// collinear, touching
+	res = s.Intersect(Segment2f{Vec2f{10, 0}, Vec2f{15, 0}})
+	assert.Equal(t, 1, res.Count)
+	AssertVec2f(t, Vec2f{10, 0}, res.Points[0])
+
+	// collinear, disjoint
+	assert.Equal(t, 0, s.Intersect(Segment2f{Vec2f{11, 0}, Vec2f{15, 0}}).Count)
+
+	// degenerate
+	res = s.Intersect(Segment2f{Vec2f{5, 0}, Vec2f{5, 0}})
+	assert.Equal(t, 1, res.Count)
+	AssertVec2f(t, Vec2f{5, 0}, res.Points[0])
+	AssertFloat(t, 0.5, res.T[0])
+	res = Segment2f{Vec2f{5, 0}, Vec2f{5, 0}}.Intersect(s)
+	assert.Equal(t, 1, res.Count)
+	AssertFloat(t, 0.5, res.U[0])
+	assert.Equal(t, 0, s.Intersect(Segment2f{Vec2f{5, 1}, Vec2f{5, 1}}).Count)
+}
+
+func TestSegment2f_ClipRectf(t *testing.T) {
+	rect := Rectf{Vec2f{0, 0}, Vec2f{4, 2}}
+
+	clipped, ok := Segment2f{Vec2f{-2, 1}, Vec2f{6, 1}}.ClipRectf(rect)
+	assert.True(t, ok)
+	AssertVec2f(t, Vec2f{0, 1}, clipped.A)
+	AssertVec2f(t, Vec2f{4, 1}, clipped.B)
+
+	clipped, ok = Segment2f{Vec2f{3, 3}, Vec2f{1, -1}}.ClipRectf(rect)
+	assert.True(t, ok)
+	AssertVec2f(t, Vec2f{2.5, 2}, clipped.A)
+	AssertVec2f(t, Vec2f{1.5, 0}, clipped.B)
+
+	// inside
+	clipped, ok = Segment2f{Vec2f{1, 1}, Vec2f{2, 1}}.ClipRectf(rect)
+	assert.True(t, ok)
+	assert.Equal(t, Segment2f{Vec2f{1, 1}, Vec2f{2, 1}}, clipped)
+
+	// outside
+	_, ok = Segment2f{Vec2f{-2, 3}, Vec2f{6, 3}}.ClipRectf(rect)
+	assert.False(t, ok)
+	_, ok = Segment2f{Vec2f{3, 4}, Vec2f{6, 1}}.ClipRectf(rect)
+	assert.False(t, ok)
+}
+
+func TestSegment2f_IntersectCircle(t *testing.T) {
+	c := Circle{Vec2f{0, 0}, 2}
+
+	count, points, ts := Segment2f{Vec2f{-4, 0}, Vec2f{4, 0}}.IntersectCircle(c)
+	assert.Equal(t, 2, count)
+	AssertVec2f(t, Vec2f{-2, 0}, points[0])
+	AssertVec2f(t, Vec2f{2, 0}, points[1])
+	AssertFloat(t, 0.25, ts[0])
+	AssertFloat(t, 0.75, ts[1])
+
+	// starting inside
+	count, points, ts = Segment2f{Vec2f{0, 0}, Vec2f{0, 4}}.IntersectCircle(c)
+	assert.Equal(t, 1, count)
+	AssertVec2f(t, Vec2f{0, 2}, points[0])
+	AssertFloat(t, 0.5, ts[0])
+
+	// tangent
+	count, points, _ = Segment2f{Vec2f{-4, 2}, Vec2f{4, 2}}.IntersectCircle(c)
+	assert.Equal(t, 1, count)
+	AssertVec2f(t, Vec2f{0, 2}, points[0])
+
+	// completely inside
+	count, _, _ = Segment2f{Vec2f{-1, 0}, Vec2f{1, 0}}.IntersectCircle(c)
+	assert.Equal(t, 0, count)
+	assert.True(t, Segment2f{Vec2f{-1, 0}, Vec2f{1, 0}}.IntersectsCircle(c))
+
+	// outside
+	count, _, _ = Segment2f{Vec2f{-4, 3}, Vec2f{4, 3}}.IntersectCircle(c)
+	assert.Equal(t, 0, count)
+	assert.False(t, Segment2f{Vec2f{-4, 3}, Vec2f{4, 3}}.IntersectsCircle(c))
+}
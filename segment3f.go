@@ -0,0 +1,179 @@
+package vmath
+
+import (
+	"fmt"
+
+	"github.com/maja42/vmath/math32"
+)
+
+// Segment3f represents a line segment in 3D space, going from A to B.
+type Segment3f struct {
+	A, B Vec3f
+}
+
+func (s Segment3f) String() string {
+	return fmt.Sprintf("Segment3f([%f x %f x %f]-[%f x %f x %f])",
+		s.A[0], s.A[1], s.A[2],
+		s.B[0], s.B[1], s.B[2])
+}
+
+// Dir returns the (non-normalized) direction from A to B.
+func (s Segment3f) Dir() Vec3f {
+	return s.B.Sub(s.A)
+}
+
+// Length returns the segment's length.
+func (s Segment3f) Length() float32 {
+	return s.A.Distance(s.B)
+}
+
+// SquareLength returns the segment's squared length.
+func (s Segment3f) SquareLength() float32 {
+	return s.A.SquareDistance(s.B)
+}
+
+// Center returns the segment's midpoint.
+func (s Segment3f) Center() Vec3f {
+	return s.A.Lerp(s.B, 0.5)
+}
+
+// At returns the point A + t*(B-A).
+// t=0 returns A, t=1 returns B.
+func (s Segment3f) At(t float32) Vec3f {
+	return s.A.Lerp(s.B, t)
+}
+
+// Box3f returns the smallest axis-aligned box that contains the segment.
+func (s Segment3f) Box3f() Box3f {
+	return Box3fFromCorners(s.A, s.B)
+}
+
+// ClosestPoint returns the point on the segment that is closest to the given point,
+// as well as its segment parameter t in range [0, 1].
+func (s Segment3f) ClosestPoint(point Vec3f) (Vec3f, float32) {
+	// Source: "Real-Time Collision Detection" by Christer Ericson, chapter 5.1.2
+	ab := s.Dir()
+	sqLen := ab.SquareLength()
+	if sqLen == 0 {
+		return s.A, 0
+	}
+	t := Clampf(point.Sub(s.A).Dot(ab)/sqLen, 0, 1)
+	return s.A.Add(ab.MulScalar(t)), t
+}
+
+// SquarePointDistance returns the squared distance between the segment and a point.
+func (s Segment3f) SquarePointDistance(point Vec3f) float32 {
+	closest, _ := s.ClosestPoint(point)
+	return closest.SquareDistance(point)
+}
+
+// PointDistance returns the distance between the segment and a point.
+func (s Segment3f) PointDistance(point Vec3f) float32 {
+	return math32.Sqrt(s.SquarePointDistance(point))
+}
+
+// ClosestPoints returns the closest points between two segments,
+// as well as their segment parameters in range [0, 1].
+// If the segments are parallel, one of multiple possible point pairs is returned.
+func (s Segment3f) ClosestPoints(other Segment3f) (p1, p2 Vec3f, t1, t2 float32) {
+	// Source: "Real-Time Collision Detection" by Christer Ericson, chapter 5.1.9
+	d1 := s.Dir()
+	d2 := other.Dir()
+	r := s.A.Sub(other.A)
+	a := d1.SquareLength()
+	e := d2.SquareLength()
+	f := d2.Dot(r)
+
+	switch {
+	case a == 0 && e == 0: // both segments degenerate into points
+		return s.A, other.A, 0, 0
+	case a == 0: // first segment degenerates into a point
+		t2 = Clampf(f/e, 0, 1)
+	default:
+		c := d1.Dot(r)
+		if e == 0 { // second segment degenerates into a point
+			t1 = Clampf(-c/a, 0, 1)
+		} else {
+			b := d1.Dot(d2)
+			denom := a*e - b*b
+			// if the segments are not parallel, compute the closest point on the first line
+			// and clamp it to the first segment. Otherwise pick an arbitrary t1 (here 0)
+			if denom != 0 {
+				t1 = Clampf((b*f-c*e)/denom, 0, 1)
+			}
+			// compute the point on the second line closest to p1
+			t2 = (b*t1 + f) / e
+			// if t2 is outside of the second segment, clamp it and recompute t1
+			if t2 < 0 {
+				t2 = 0
+				t1 = Clampf(-c/a, 0, 1)
+			} else if t2 > 1 {
+				t2 = 1
+				t1 = Clampf((b-c)/a, 0, 1)
+			}
+		}
+	}
+	return s.A.Add(d1.MulScalar(t1)), other.A.Add(d2.MulScalar(t2)), t1, t2
+}
+
+// SquareDistance returns the squared distance between two segments.
+func (s Segment3f) SquareDistance(other Segment3f) float32 {
+	p1, p2, _, _ := s.ClosestPoints(other)
+	return p1.SquareDistance(p2)
+}
+
+// Distance returns the distance between two segments.
+func (s Segment3f) Distance(other Segment3f) float32 {
+	return math32.Sqrt(s.SquareDistance(other))
+}
+
+// IntersectsBox3f checks if the segment intersects an axis-aligned box.
+// Segments touching the box are also considered to intersect.
+func (s Segment3f) IntersectsBox3f(box Box3f) bool {
+	// Source: "Real-Time Collision Detection" by Christer Ericson, chapter 5.3.3 (slab method)
+	dir := s.Dir()
+	tMin, tMax := float32(0), float32(1)
+	for i := 0; i < 3; i++ {
+		if dir[i] == 0 {
+			// parallel to the slab; no hit if the segment is not within the slab
+			if s.A[i] < box.Min[i] || s.A[i] > box.Max[i] {
+				return false
+			}
+			continue
+		}
+		invD := 1 / dir[i]
+		t1 := (box.Min[i] - s.A[i]) * invD
+		t2 := (box.Max[i] - s.A[i]) * invD
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		tMin = math32.Max(tMin, t1)
+		tMax = math32.Min(tMax, t2)
+		if tMin > tMax {
+			return false
+		}
+	}
+	return true
+}
+
+// SquareBox3fDistance returns the squared distance between the segment and an axis-aligned box.
+// If the segment intersects the box, 0 is returned.
+func (s Segment3f) SquareBox3fDistance(box Box3f) float32 {
+	if s.IntersectsBox3f(box) {
+		return 0
+	}
+	// If the segment does not intersect the box, the closest point on the box is either
+	// on one of the box's edges, or on a face - with a segment endpoint being closest to it.
+	dist := math32.Min(box.SquarePointDistance(s.A), box.SquarePointDistance(s.B))
+
+	c := box.Corners()
+	edges := [12][2]int{
+		{0, 1}, {1, 2}, {2, 3}, {3, 0}, // bottom
+		{4, 5}, {5, 6}, {6, 7}, {7, 4}, // top
+		{0, 4}, {1, 5}, {2, 6}, {3, 7}, // sides
+	}
+	for _, e := range edges {
+		dist = math32.Min(dist, s.SquareDistance(Segment3f{c[e[0]], c[e[1]]}))
+	}
+	return dist
+}
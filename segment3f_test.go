@@ -0,0 +1,103 @@
+package vmath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegment3f_String(t *testing.T) {
+	s := Segment3f{Vec3f{1, 2, 3}, Vec3f{4, 5, 6}}
+	assert.Equal(t, "Segment3f([1.000000 x 2.000000 x 3.000000]-[4.000000 x 5.000000 x 6.000000])", s.String())
+}
+
+func TestSegment3f_Length(t *testing.T) {
+	s := Segment3f{Vec3f{1, 2, 3}, Vec3f{3, 5, 9}}
+	AssertFloat(t, 7, s.Length())
+	AssertFloat(t, 49, s.SquareLength())
+	AssertVec3f(t, Vec3f{2, 3.5, 6}, s.Center())
+	AssertVec3f(t, Vec3f{3, 5, 9}, s.At(1))
+}
+
+func TestSegment3f_ClosestPoint(t *testing.T) {
+	s := Segment3f{Vec3f{0, 0, 0}, Vec3f{10, 0, 0}}
+
+	p, tp := s.ClosestPoint(Vec3f{4, 3, 4})
+	AssertVec3f(t, Vec3f{4, 0, 0}, p)
+	AssertFloat(t, 0.4, tp)
+	AssertFloat(t, 25, s.SquarePointDistance(Vec3f{4, 3, 4}))
+
+	p, tp = s.ClosestPoint(Vec3f{-5, 1, 0})
+	AssertVec3f(t, Vec3f{0, 0, 0}, p)
+	AssertFloat(t, 0, tp)
+
+	p, tp = s.ClosestPoint(Vec3f{15, 1, 0})
+	AssertVec3f(t, Vec3f{10, 0, 0}, p)
+	AssertFloat(t, 1, tp)
+	AssertFloat(t, 3, s.PointDistance(Vec3f{10, 0, 3}))
+
+	// degenerate
+	p, tp = Segment3f{Vec3f{1, 1, 1}, Vec3f{1, 1, 1}}.ClosestPoint(Vec3f{5, 5, 5})
+	AssertVec3f(t, Vec3f{1, 1, 1}, p)
+	AssertFloat(t, 0, tp)
+}
+
+func TestSegment3f_ClosestPoints(t *testing.T) {
+	s := Segment3f{Vec3f{0, 0, 0}, Vec3f{10, 0, 0}}
+
+	// crossing lines
+	p1, p2, t1, t2 := s.ClosestPoints(Segment3f{Vec3f{4, -5, 3}, Vec3f{4, 5, 3}})
+	AssertVec3f(t, Vec3f{4, 0, 0}, p1)
+	AssertVec3f(t, Vec3f{4, 0, 3}, p2)
+	AssertFloat(t, 0.4, t1)
+	AssertFloat(t, 0.5, t2)
+	AssertFloat(t, 3, s.Distance(Segment3f{Vec3f{4, -5, 3}, Vec3f{4, 5, 3}}))
+
+	// clamped to endpoints
+	p1, p2, t1, t2 = s.ClosestPoints(Segment3f{Vec3f{12, 2, 0}, Vec3f{12, 5, 0}})
+	AssertVec3f(t, Vec3f{10, 0, 0}, p1)
+	AssertVec3f(t, Vec3f{12, 2, 0}, p2)
+	AssertFloat(t, 1, t1)
+	AssertFloat(t, 0, t2)
+
+	// parallel
+	p1, p2, _, _ = s.ClosestPoints(Segment3f{Vec3f{5, 2, 0}, Vec3f{15, 2, 0}})
+	AssertFloat(t, 2, p1.Distance(p2))
+	AssertFloat(t, 4, s.SquareDistance(Segment3f{Vec3f{5, 2, 0}, Vec3f{15, 2, 0}}))
+
+	// intersecting
+	AssertFloat(t, 0, s.Distance(Segment3f{Vec3f{3, -1, -1}, Vec3f{3, 1, 1}}))
+
+	// degenerate
+	p1, p2, _, _ = s.ClosestPoints(Segment3f{Vec3f{3, 4, 0}, Vec3f{3, 4, 0}})
+	AssertVec3f(t, Vec3f{3, 0, 0}, p1)
+	AssertVec3f(t, Vec3f{3, 4, 0}, p2)
+	p1, p2, _, _ = Segment3f{Vec3f{3, 4, 0}, Vec3f{3, 4, 0}}.ClosestPoints(s)
+	AssertVec3f(t, Vec3f{3, 4, 0}, p1)
+	AssertVec3f(t, Vec3f{3, 0, 0}, p2)
+}
+
+func TestSegment3f_IntersectsBox3f(t *testing.T) {
+	box := Box3f{Vec3f{0, 0, 0}, Vec3f{2, 2, 2}}
+
+	assert.True(t, Segment3f{Vec3f{-1, 1, 1}, Vec3f{3, 1, 1}}.IntersectsBox3f(box))
+	assert.True(t, Segment3f{Vec3f{1, 1, 1}, Vec3f{1.5, 1, 1}}.IntersectsBox3f(box)) // inside
+	assert.True(t, Segment3f{Vec3f{-1, 2, 1}, Vec3f{3, 2, 1}}.IntersectsBox3f(box))  // touching
+	assert.False(t, Segment3f{Vec3f{-3, 1, 1}, Vec3f{-1, 1, 1}}.IntersectsBox3f(box))
+	assert.False(t, Segment3f{Vec3f{-1, 3, 1}, Vec3f{3, 3, 1}}.IntersectsBox3f(box))
+	assert.False(t, Segment3f{Vec3f{-1, 2, 0}, Vec3f{2, 5, 0}}.IntersectsBox3f(box))
+}
+
+func TestSegment3f_SquareBox3fDistance(t *testing.T) {
+	box := Box3f{Vec3f{0, 0, 0}, Vec3f{2, 2, 2}}
+
+	AssertFloat(t, 0, Segment3f{Vec3f{-1, 1, 1}, Vec3f{3, 1, 1}}.SquareBox3fDistance(box))
+	// parallel to a face
+	AssertFloat(t, 4, Segment3f{Vec3f{-1, 4, 1}, Vec3f{3, 4, 1}}.SquareBox3fDistance(box))
+	// endpoint closest to a face
+	AssertFloat(t, 9, Segment3f{Vec3f{1, 5, 1}, Vec3f{1, 9, 1}}.SquareBox3fDistance(box))
+	// crossing an edge
+	AssertFloat(t, 2, Segment3f{Vec3f{3, 3, -5}, Vec3f{3, 3, 5}}.SquareBox3fDistance(box))
+	// skewed against an edge
+	AssertFloat(t, 0.5, Segment3f{Vec3f{4, 1, 1}, Vec3f{1, 4, 1}}.SquareBox3fDistance(box))
+}
@@ -0,0 +1,213 @@
+package vmath
+
+import (
+	"container/heap"
+
+	"github.com/maja42/vmath/math32"
+)
+
+// SimplifyDouglasPeucker reduces the number of points of a polyline, using the Ramer–Douglas–Peucker algorithm.
+// The simplified polyline deviates by at most the given tolerance from the original points.
+// The first and last points are always kept.
+func SimplifyDouglasPeucker(points []Vec2f, tolerance float32) []Vec2f {
+	keep := douglasPeucker(len(points), tolerance, func(a, b, i int) float32 {
+		return PointToLineSegmentDistance2D(points[a], points[b], points[i])
+	})
+	res := make([]Vec2f, 0, len(points))
+	for i, p := range points {
+		if keep[i] {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// SimplifyDouglasPeucker3D reduces the number of points of a polyline, using the Ramer–Douglas–Peucker algorithm.
+// See SimplifyDouglasPeucker for details.
+func SimplifyDouglasPeucker3D(points []Vec3f, tolerance float32) []Vec3f {
+	keep := douglasPeucker(len(points), tolerance, func(a, b, i int) float32 {
+		return PointToLineSegmentDistance3D(points[a], points[b], points[i])
+	})
+	res := make([]Vec3f, 0, len(points))
+	for i, p := range points {
+		if keep[i] {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// douglasPeucker returns which of the n points are kept.
+// dist returns the distance of point i to the segment between the points a and b.
+func douglasPeucker(n int, tolerance float32, dist func(a, b, i int) float32) []bool {
+	// Source: "Algorithms for the reduction of the number of points required to represent a digitized line or its
+	// caricature" by D. Douglas and T. Peucker, 1973.
+	keep := make([]bool, n)
+	if n == 0 {
+		return keep
+	}
+	keep[0], keep[n-1] = true, true
+
+	// the recursion is replaced by a stack of ranges, to handle long polylines
+	stack := [][2]int{{0, n - 1}}
+	for len(stack) > 0 {
+		r := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		maxIdx := -1
+		var maxDist float32
+		for i := r[0] + 1; i < r[1]; i++ {
+			if d := dist(r[0], r[1], i); d > maxDist {
+				maxIdx, maxDist = i, d
+			}
+		}
+		if maxIdx >= 0 && maxDist > tolerance {
+			keep[maxIdx] = true
+			stack = append(stack, [2]int{r[0], maxIdx}, [2]int{maxIdx, r[1]})
+		}
+	}
+	return keep
+}
+
+// SimplifyVisvalingam reduces the number of points of a polyline, using the Visvalingam–Whyatt algorithm.
+// Points are removed in the order of the area of the triangle they form with their neighbors,
+// until all remaining points form triangles with an area of at least minArea.
+// The first and last points are always kept.
+func SimplifyVisvalingam(points []Vec2f, minArea float32) []Vec2f {
+	keep := visvalingam(len(points), minArea, func(a, b, c int) float32 {
+		return math32.Abs(points[b].Sub(points[a]).MagCross(points[c].Sub(points[a]))) / 2
+	})
+	res := make([]Vec2f, 0, len(points))
+	for i, p := range points {
+		if keep[i] {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// SimplifyVisvalingam3D reduces the number of points of a polyline, using the Visvalingam–Whyatt algorithm.
+// See SimplifyVisvalingam for details.
+func SimplifyVisvalingam3D(points []Vec3f, minArea float32) []Vec3f {
+	keep := visvalingam(len(points), minArea, func(a, b, c int) float32 {
+		return points[b].Sub(points[a]).Cross(points[c].Sub(points[a])).Length() / 2
+	})
+	res := make([]Vec3f, 0, len(points))
+	for i, p := range points {
+		if keep[i] {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// visvalingam returns which of the n points are kept.
+// area returns the area of the triangle formed by the points a, b and c.
+func visvalingam(n int, minArea float32, area func(a, b, c int) float32) []bool {
+	// Source: "Line generalisation by repeated elimination of points" by M. Visvalingam and J. Whyatt, 1993.
+	keep := make([]bool, n)
+	prev := make([]int, n)
+	next := make([]int, n)
+	areas := make([]float32, n)
+	h := make(visvalingamHeap, 0, n)
+	for i := range keep {
+		keep[i] = true
+		prev[i], next[i] = i-1, i+1
+		if i > 0 && i < n-1 {
+			areas[i] = area(i-1, i, i+1)
+			h = append(h, visvalingamItem{i, areas[i]})
+		}
+	}
+	heap.Init(&h)
+
+	for h.Len() > 0 {
+		item := heap.Pop(&h).(visvalingamItem)
+		i := item.index
+		if !keep[i] || item.area != areas[i] {
+			continue // outdated
+		}
+		if item.area >= minArea {
+			break
+		}
+		keep[i] = false
+		p, nx := prev[i], next[i]
+		next[p], prev[nx] = nx, p
+
+		// the neighbors' areas must not be smaller than the removed one, so that they are removed afterwards
+		for _, j := range [2]int{p, nx} {
+			if j > 0 && j < n-1 {
+				areas[j] = math32.Max(area(prev[j], j, next[j]), item.area)
+				heap.Push(&h, visvalingamItem{j, areas[j]})
+			}
+		}
+	}
+	return keep
+}
+
+// visvalingamItem is a point with its triangle's area.
+type visvalingamItem struct {
+	index int
+	area  float32
+}
+
+// visvalingamHeap is a min-heap of points, sorted by their area.
+type visvalingamHeap []visvalingamItem
+
+func (h visvalingamHeap) Len() int            { return len(h) }
+func (h visvalingamHeap) Less(i, j int) bool  { return h[i].area < h[j].area }
+func (h visvalingamHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *visvalingamHeap) Push(x interface{}) { *h = append(*h, x.(visvalingamItem)) }
+func (h *visvalingamHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// ResamplePolyline returns points along the polyline with a uniform arc-length spacing.
+// The first point is kept. The last point is appended if it isn't already part of the result,
+// resulting in a shorter final segment.
+// Returns a copy of the polyline if the spacing is not positive.
+func ResamplePolyline(points []Vec2f, spacing float32) []Vec2f {
+	if len(points) == 0 || spacing <= 0 {
+		return append([]Vec2f{}, points...)
+	}
+	res := []Vec2f{points[0]}
+	var pos float32 // arc-length position of the current segment's start
+	k := 1          // index of the next point
+	for i := 1; i < len(points); i++ {
+		a, b := points[i-1], points[i]
+		length := a.Distance(b)
+		for ; float32(k)*spacing <= pos+length; k++ {
+			res = append(res, a.Lerp(b, (float32(k)*spacing-pos)/length))
+		}
+		pos += length
+	}
+	if last := points[len(points)-1]; !res[len(res)-1].Equal(last) {
+		res = append(res, last)
+	}
+	return res
+}
+
+// ResamplePolyline3D returns points along the polyline with a uniform arc-length spacing.
+// See ResamplePolyline for details.
+func ResamplePolyline3D(points []Vec3f, spacing float32) []Vec3f {
+	if len(points) == 0 || spacing <= 0 {
+		return append([]Vec3f{}, points...)
+	}
+	res := []Vec3f{points[0]}
+	var pos float32 // arc-length position of the current segment's start
+	k := 1          // index of the next point
+	for i := 1; i < len(points); i++ {
+		a, b := points[i-1], points[i]
+		length := a.Distance(b)
+		for ; float32(k)*spacing <= pos+length; k++ {
+			res = append(res, a.Lerp(b, (float32(k)*spacing-pos)/length))
+		}
+		pos += length
+	}
+	if last := points[len(points)-1]; !res[len(res)-1].Equal(last) {
+		res = append(res, last)
+	}
+	return res
+}
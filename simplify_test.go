@@ -0,0 +1,97 @@
+package vmath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimplifyDouglasPeucker(t *testing.T) {
+	points := []Vec2f{{0, 0}, {1, 0.1}, {2, -0.1}, {3, 5}, {4, 6}, {5, 7}, {6, 8}, {7, 9}, {8, 9}, {9, 9}}
+	assert.Equal(t, []Vec2f{{0, 0}, {2, -0.1}, {3, 5}, {7, 9}, {9, 9}}, SimplifyDouglasPeucker(points, 0.5))
+	assert.Equal(t, []Vec2f{{0, 0}, {2, -0.1}, {3, 5}, {7, 9}, {9, 9}}, SimplifyDouglasPeucker(points, 0.2))
+	assert.Equal(t, []Vec2f{{0, 0}, {1, 0.1}, {2, -0.1}, {3, 5}, {7, 9}, {9, 9}}, SimplifyDouglasPeucker(points, 0.1))
+	assert.Equal(t, []Vec2f{{0, 0}, {2, -0.1}, {3, 5}, {9, 9}}, SimplifyDouglasPeucker(points, 1.2))
+	assert.Equal(t, []Vec2f{{0, 0}, {9, 9}}, SimplifyDouglasPeucker(points, 2))
+
+	// the result stays within the tolerance
+	for _, p := range points {
+		simplified := SimplifyDouglasPeucker(points, 0.5)
+		dist := float32(1e10)
+		for i := 1; i < len(simplified); i++ {
+			if d := PointToLineSegmentDistance2D(simplified[i-1], simplified[i], p); d < dist {
+				dist = d
+			}
+		}
+		assert.True(t, dist <= 0.5)
+	}
+
+	// closed polyline
+	loop := []Vec2f{{0, 0}, {2, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}
+	assert.Equal(t, []Vec2f{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}, SimplifyDouglasPeucker(loop, 1))
+
+	assert.Empty(t, SimplifyDouglasPeucker(nil, 1))
+	assert.Equal(t, []Vec2f{{1, 2}}, SimplifyDouglasPeucker([]Vec2f{{1, 2}}, 1))
+}
+
+func TestSimplifyDouglasPeucker3D(t *testing.T) {
+	points := []Vec3f{{0, 0, 0}, {1, 0, 0.1}, {2, 0, 0}, {2, 3, 0}, {2, 6, 0.1}, {2, 6, 6}}
+	assert.Equal(t, []Vec3f{{0, 0, 0}, {2, 0, 0}, {2, 6, 0.1}, {2, 6, 6}}, SimplifyDouglasPeucker3D(points, 0.5))
+	assert.Equal(t, points, SimplifyDouglasPeucker3D(points, 0.01))
+}
+
+func TestSimplifyVisvalingam(t *testing.T) {
+	points := []Vec2f{{0, 0}, {1, 0.1}, {2, 0}, {3, 3}, {4, 0}, {5, 0}}
+	// initial triangle areas: 0.1, 1.55, 3, 1.5
+	assert.Equal(t, points, SimplifyVisvalingam(points, 0.1))
+	assert.Equal(t, []Vec2f{{0, 0}, {2, 0}, {3, 3}, {4, 0}, {5, 0}}, SimplifyVisvalingam(points, 0.2))
+	// areas after removing the first point: 3, 3, 1.5; then: 3, 4.5
+	assert.Equal(t, []Vec2f{{0, 0}, {2, 0}, {3, 3}, {5, 0}}, SimplifyVisvalingam(points, 2))
+	assert.Equal(t, []Vec2f{{0, 0}, {3, 3}, {5, 0}}, SimplifyVisvalingam(points, 4))
+	assert.Equal(t, []Vec2f{{0, 0}, {5, 0}}, SimplifyVisvalingam(points, 100))
+
+	// collinear points are removed first
+	line := []Vec2f{{0, 0}, {1, 0}, {2, 0}, {3, 0}}
+	assert.Equal(t, []Vec2f{{0, 0}, {3, 0}}, SimplifyVisvalingam(line, Epsilon))
+
+	assert.Empty(t, SimplifyVisvalingam(nil, 1))
+	assert.Equal(t, []Vec2f{{1, 2}, {3, 4}}, SimplifyVisvalingam([]Vec2f{{1, 2}, {3, 4}}, 1))
+}
+
+func TestSimplifyVisvalingam3D(t *testing.T) {
+	points := []Vec3f{{0, 0, 0}, {1, 0, 0.1}, {2, 0, 0}, {2, 0, 3}, {2, 0, 6}}
+	assert.Equal(t, []Vec3f{{0, 0, 0}, {2, 0, 0}, {2, 0, 6}}, SimplifyVisvalingam3D(points, 0.5))
+}
+
+func TestResamplePolyline(t *testing.T) {
+	points := []Vec2f{{0, 0}, {2.5, 0}, {2.5, 2}}
+	res := ResamplePolyline(points, 1)
+	expected := []Vec2f{{0, 0}, {1, 0}, {2, 0}, {2.5, 0.5}, {2.5, 1.5}, {2.5, 2}}
+	assert.Len(t, res, len(expected))
+	for i := range expected {
+		AssertVec2f(t, expected[i], res[i])
+	}
+
+	// the last point is not repeated
+	res = ResamplePolyline(points, 1.5)
+	assert.Len(t, res, 4)
+	AssertVec2f(t, Vec2f{2.5, 2}, res[3])
+
+	// many points
+	res = ResamplePolyline([]Vec2f{{0, 0}, {1000, 0}}, 0.1)
+	assert.Len(t, res, 10001)
+	AssertVec2f(t, Vec2f{500, 0}, res[5000])
+
+	assert.Equal(t, points, ResamplePolyline(points, 0))
+	assert.Empty(t, ResamplePolyline(nil, 1))
+}
+
+func TestResamplePolyline3D(t *testing.T) {
+	points := []Vec3f{{0, 0, 0}, {0, 0, 1.5}, {0, 1.5, 1.5}}
+	res := ResamplePolyline3D(points, 1)
+	expected := []Vec3f{{0, 0, 0}, {0, 0, 1}, {0, 0.5, 1.5}, {0, 1.5, 1.5}}
+	assert.Len(t, res, len(expected))
+	for i := range expected {
+		AssertVec3f(t, expected[i], res[i])
+	}
+}
@@ -0,0 +1,349 @@
+package vmath
+
+// SpatialHash2 is a uniform grid, which accelerates neighbour queries over large numbers of moving points.
+// Points are identified by integer IDs and sorted into square cells of a configurable size.
+// Only cells that contain points are stored, so the grid is unbounded.
+// Queries can run concurrently, but not while the grid is modified.
+type SpatialHash2 struct {
+	cellSize float32
+	cells    map[Vec2i][]int
+	points   map[int]Vec2f
+}
+
+// NewSpatialHash2 creates an empty grid with the given cell size.
+// For radius queries, the cell size should be close to the typical query radius.
+// Panics if the cell size is not positive.
+func NewSpatialHash2(cellSize float32) *SpatialHash2 {
+	if !(cellSize > 0) {
+		panic("cell size must be positive")
+	}
+	return &SpatialHash2{
+		cellSize: cellSize,
+		cells:    make(map[Vec2i][]int),
+		points:   make(map[int]Vec2f),
+	}
+}
+
+// CellSize returns the size of the grid's cells.
+func (h *SpatialHash2) CellSize() float32 {
+	return h.cellSize
+}
+
+// Cell returns the cell containing the given position.
+// Points on the border between cells belong to the cell with the larger coordinates.
+func (h *SpatialHash2) Cell(pos Vec2f) Vec2i {
+	return pos.DivScalar(h.cellSize).Floor()
+}
+
+// CellRect returns the region covered by the given cell.
+func (h *SpatialHash2) CellRect(cell Vec2i) Rectf {
+	min := cell.MulScalarf(h.cellSize)
+	return Rectf{min, min.AddScalar(h.cellSize)}
+}
+
+// CellRange returns the first and last cell touched by the given rectangle.
+func (h *SpatialHash2) CellRange(rect Rectf) (Vec2i, Vec2i) {
+	return h.Cell(rect.Min), h.Cell(rect.Max)
+}
+
+// Len returns the number of points within the grid.
+func (h *SpatialHash2) Len() int {
+	return len(h.points)
+}
+
+// Position returns the position of the point with the given ID.
+// Returns false if the point is not within the grid.
+func (h *SpatialHash2) Position(id int) (Vec2f, bool) {
+	pos, ok := h.points[id]
+	return pos, ok
+}
+
+// Insert adds a point to the grid. If a point with the same ID already exists, it is moved.
+func (h *SpatialHash2) Insert(id int, pos Vec2f) {
+	if h.Move(id, pos) {
+		return
+	}
+	h.points[id] = pos
+	cell := h.Cell(pos)
+	h.cells[cell] = append(h.cells[cell], id)
+}
+
+// Remove removes the point with the given ID.
+// Returns false if the point is not within the grid.
+func (h *SpatialHash2) Remove(id int) bool {
+	pos, ok := h.points[id]
+	if !ok {
+		return false
+	}
+	delete(h.points, id)
+	h.removeFromCell(id, h.Cell(pos))
+	return true
+}
+
+// Move moves a point to a new position.
+// Returns false if the point is not within the grid.
+func (h *SpatialHash2) Move(id int, pos Vec2f) bool {
+	old, ok := h.points[id]
+	if !ok {
+		return false
+	}
+	h.points[id] = pos
+	oldCell, cell := h.Cell(old), h.Cell(pos)
+	if oldCell != cell {
+		h.removeFromCell(id, oldCell)
+		h.cells[cell] = append(h.cells[cell], id)
+	}
+	return true
+}
+
+func (h *SpatialHash2) removeFromCell(id int, cell Vec2i) {
+	ids := h.cells[cell]
+	for i, other := range ids {
+		if other == id {
+			last := len(ids) - 1
+			ids[i] = ids[last]
+			ids = ids[:last]
+			break
+		}
+	}
+	if len(ids) == 0 {
+		delete(h.cells, cell)
+	} else {
+		h.cells[cell] = ids
+	}
+}
+
+// VisitRect calls visit for all non-empty cells touched by the given rectangle, with the IDs of the points within the cell.
+// The ID slice must not be modified. Returning false from visit stops the iteration.
+func (h *SpatialHash2) VisitRect(rect Rectf, visit func(cell Vec2i, ids []int) bool) {
+	min, max := h.CellRange(rect)
+	h.visitCells(Recti{min, max}, visit)
+}
+
+// visitCells calls visit for all non-empty cells within the given range (inclusive).
+// If the range spans more cells than are stored, the stored cells are filtered instead.
+func (h *SpatialHash2) visitCells(r Recti, visit func(cell Vec2i, ids []int) bool) {
+	// computed in float64 to avoid integer overflows
+	count := (float64(r.Max[0]) - float64(r.Min[0]) + 1) * (float64(r.Max[1]) - float64(r.Min[1]) + 1)
+	if count > float64(len(h.cells)) {
+		for cell, ids := range h.cells {
+			if r.ContainsPoint(cell) && !visit(cell, ids) {
+				return
+			}
+		}
+		return
+	}
+	var cell Vec2i
+	for cell[1] = r.Min[1]; cell[1] <= r.Max[1]; cell[1]++ {
+		for cell[0] = r.Min[0]; cell[0] <= r.Max[0]; cell[0]++ {
+			if ids, ok := h.cells[cell]; ok && !visit(cell, ids) {
+				return
+			}
+		}
+	}
+}
+
+// QueryRect appends the IDs of all points within the given rectangle to dst, and returns the result.
+// Points on the rectangle's edge are included.
+func (h *SpatialHash2) QueryRect(rect Rectf, dst []int) []int {
+	h.VisitRect(rect, func(_ Vec2i, ids []int) bool {
+		for _, id := range ids {
+			if rect.ContainsPoint(h.points[id]) {
+				dst = append(dst, id)
+			}
+		}
+		return true
+	})
+	return dst
+}
+
+// QueryRadius appends the IDs of all points within the given distance of a position to dst, and returns the result.
+// Points on the boundary are included.
+func (h *SpatialHash2) QueryRadius(pos Vec2f, radius float32, dst []int) []int {
+	sqRadius := radius * radius
+	h.VisitRect(Circle{pos, radius}.Rectf(), func(_ Vec2i, ids []int) bool {
+		for _, id := range ids {
+			if h.points[id].SquareDistance(pos) <= sqRadius {
+				dst = append(dst, id)
+			}
+		}
+		return true
+	})
+	return dst
+}
+
+// SpatialHash3 is a uniform grid, which accelerates neighbour queries over large numbers of moving points.
+// Points are identified by integer IDs and sorted into cubic cells of a configurable size.
+// Only cells that contain points are stored, so the grid is unbounded.
+// Queries can run concurrently, but not while the grid is modified.
+type SpatialHash3 struct {
+	cellSize float32
+	cells    map[Vec3i][]int
+	points   map[int]Vec3f
+}
+
+// NewSpatialHash3 creates an empty grid with the given cell size.
+// For radius queries, the cell size should be close to the typical query radius.
+// Panics if the cell size is not positive.
+func NewSpatialHash3(cellSize float32) *SpatialHash3 {
+	if !(cellSize > 0) {
+		panic("cell size must be positive")
+	}
+	return &SpatialHash3{
+		cellSize: cellSize,
+		cells:    make(map[Vec3i][]int),
+		points:   make(map[int]Vec3f),
+	}
+}
+
+// CellSize returns the size of the grid's cells.
+func (h *SpatialHash3) CellSize() float32 {
+	return h.cellSize
+}
+
+// Cell returns the cell containing the given position.
+// Points on the border between cells belong to the cell with the larger coordinates.
+func (h *SpatialHash3) Cell(pos Vec3f) Vec3i {
+	return pos.DivScalar(h.cellSize).Floor()
+}
+
+// CellBox returns the region covered by the given cell.
+func (h *SpatialHash3) CellBox(cell Vec3i) Box3f {
+	min := cell.MulScalarf(h.cellSize)
+	return Box3f{min, min.AddScalar(h.cellSize)}
+}
+
+// CellRange returns the first and last cell touched by the given box.
+func (h *SpatialHash3) CellRange(box Box3f) (Vec3i, Vec3i) {
+	return h.Cell(box.Min), h.Cell(box.Max)
+}
+
+// Len returns the number of points within the grid.
+func (h *SpatialHash3) Len() int {
+	return len(h.points)
+}
+
+// Position returns the position of the point with the given ID.
+// Returns false if the point is not within the grid.
+func (h *SpatialHash3) Position(id int) (Vec3f, bool) {
+	pos, ok := h.points[id]
+	return pos, ok
+}
+
+// Insert adds a point to the grid. If a point with the same ID already exists, it is moved.
+func (h *SpatialHash3) Insert(id int, pos Vec3f) {
+	if h.Move(id, pos) {
+		return
+	}
+	h.points[id] = pos
+	cell := h.Cell(pos)
+	h.cells[cell] = append(h.cells[cell], id)
+}
+
+// Remove removes the point with the given ID.
+// Returns false if the point is not within the grid.
+func (h *SpatialHash3) Remove(id int) bool {
+	pos, ok := h.points[id]
+	if !ok {
+		return false
+	}
+	delete(h.points, id)
+	h.removeFromCell(id, h.Cell(pos))
+	return true
+}
+
+// Move moves a point to a new position.
+// Returns false if the point is not within the grid.
+func (h *SpatialHash3) Move(id int, pos Vec3f) bool {
+	old, ok := h.points[id]
+	if !ok {
+		return false
+	}
+	h.points[id] = pos
+	oldCell, cell := h.Cell(old), h.Cell(pos)
+	if oldCell != cell {
+		h.removeFromCell(id, oldCell)
+		h.cells[cell] = append(h.cells[cell], id)
+	}
+	return true
+}
+
+func (h *SpatialHash3) removeFromCell(id int, cell Vec3i) {
+	ids := h.cells[cell]
+	for i, other := range ids {
+		if other == id {
+			last := len(ids) - 1
+			ids[i] = ids[last]
+			ids = ids[:last]
+			break
+		}
+	}
+	if len(ids) == 0 {
+		delete(h.cells, cell)
+	} else {
+		h.cells[cell] = ids
+	}
+}
+
+// VisitBox calls visit for all non-empty cells touched by the given box, with the IDs of the points within the cell.
+// The ID slice must not be modified. Returning false from visit stops the iteration.
+func (h *SpatialHash3) VisitBox(box Box3f, visit func(cell Vec3i, ids []int) bool) {
+	min, max := h.CellRange(box)
+	h.visitCells(Box3i{min, max}, visit)
+}
+
+// visitCells calls visit for all non-empty cells within the given range (inclusive).
+// If the range spans more cells than are stored, the stored cells are filtered instead.
+func (h *SpatialHash3) visitCells(b Box3i, visit func(cell Vec3i, ids []int) bool) {
+	// computed in float64 to avoid integer overflows
+	count := (float64(b.Max[0]) - float64(b.Min[0]) + 1) *
+		(float64(b.Max[1]) - float64(b.Min[1]) + 1) *
+		(float64(b.Max[2]) - float64(b.Min[2]) + 1)
+	if count > float64(len(h.cells)) {
+		for cell, ids := range h.cells {
+			if b.ContainsPoint(cell) && !visit(cell, ids) {
+				return
+			}
+		}
+		return
+	}
+	var cell Vec3i
+	for cell[2] = b.Min[2]; cell[2] <= b.Max[2]; cell[2]++ {
+		for cell[1] = b.Min[1]; cell[1] <= b.Max[1]; cell[1]++ {
+			for cell[0] = b.Min[0]; cell[0] <= b.Max[0]; cell[0]++ {
+				if ids, ok := h.cells[cell]; ok && !visit(cell, ids) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// QueryBox appends the IDs of all points within the given box to dst, and returns the result.
+// Points on the box's surface are included.
+func (h *SpatialHash3) QueryBox(box Box3f, dst []int) []int {
+	h.VisitBox(box, func(_ Vec3i, ids []int) bool {
+		for _, id := range ids {
+			if box.ContainsPoint(h.points[id]) {
+				dst = append(dst, id)
+			}
+		}
+		return true
+	})
+	return dst
+}
+
+// QueryRadius appends the IDs of all points within the given distance of a position to dst, and returns the result.
+// Points on the boundary are included.
+func (h *SpatialHash3) QueryRadius(pos Vec3f, radius float32, dst []int) []int {
+	sqRadius := radius * radius
+	h.VisitBox(Sphere{pos, radius}.Box3f(), func(_ Vec3i, ids []int) bool {
+		for _, id := range ids {
+			if h.points[id].SquareDistance(pos) <= sqRadius {
+				dst = append(dst, id)
+			}
+		}
+		return true
+	})
+	return dst
+}
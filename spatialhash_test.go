@@ -0,0 +1,228 @@
+package vmath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpatialHash2_Cell(t *testing.T) {
+	h := NewSpatialHash2(2)
+	assert.Equal(t, float32(2), h.CellSize())
+	assert.Equal(t, Vec2i{0, 0}, h.Cell(Vec2f{0, 1.9}))
+	assert.Equal(t, Vec2i{1, 2}, h.Cell(Vec2f{2, 5}))
+	assert.Equal(t, Vec2i{-1, -2}, h.Cell(Vec2f{-0.1, -2.5}))
+	assert.Equal(t, Rectf{Vec2f{-2, 4}, Vec2f{0, 6}}, h.CellRect(Vec2i{-1, 2}))
+
+	min, max := h.CellRange(Rectf{Vec2f{-1, 1}, Vec2f{3, 1.5}})
+	assert.Equal(t, Vec2i{-1, 0}, min)
+	assert.Equal(t, Vec2i{1, 0}, max)
+
+	assert.Panics(t, func() { NewSpatialHash2(0) })
+	assert.Panics(t, func() { NewSpatialHash2(-1) })
+}
+
+func TestSpatialHash2_InsertRemove(t *testing.T) {
+	h := NewSpatialHash2(1.5)
+	points := randomPoints2f(500, 1)
+	for i, p := range points {
+		h.Insert(i, p)
+	}
+	assert.Equal(t, len(points), h.Len())
+
+	moved := randomPoints2f(500, 2)
+	for i := range points {
+		if i%3 == 0 {
+			assert.True(t, h.Remove(i))
+		} else if i%3 == 1 {
+			assert.True(t, h.Move(i, moved[i]))
+			points[i] = moved[i]
+		} else {
+			h.Insert(i, moved[i])
+			points[i] = moved[i]
+		}
+	}
+	assert.False(t, h.Remove(0))
+	assert.False(t, h.Move(0, Vec2f{}))
+	assert.Equal(t, 333, h.Len())
+
+	pos, ok := h.Position(1)
+	assert.True(t, ok)
+	assert.Equal(t, moved[1], pos)
+	_, ok = h.Position(0)
+	assert.False(t, ok)
+
+	count := 0
+	for cell, ids := range h.cells {
+		assert.NotEmpty(t, ids)
+		for _, id := range ids {
+			assert.NotEqual(t, 0, id%3)
+			assert.Equal(t, cell, h.Cell(points[id]))
+			count++
+		}
+	}
+	assert.Equal(t, 333, count)
+
+	for i := range points {
+		h.Remove(i)
+	}
+	assert.Empty(t, h.cells)
+}
+
+func TestSpatialHash2_Query(t *testing.T) {
+	h := NewSpatialHash2(1)
+	points := randomPoints2f(1000, 3)
+	for i, p := range points {
+		h.Insert(i, p)
+	}
+
+	for _, pos := range randomPoints2f(50, 4) {
+		var expected []int
+		for i, p := range points {
+			if p.Distance(pos) <= 2.5 {
+				expected = append(expected, i)
+			}
+		}
+		assert.Equal(t, expected, sortedIDs(h.QueryRadius(pos, 2.5, nil)))
+
+		rect := Rectf{pos, pos.Add(Vec2f{3, 2})}
+		expected = nil
+		for i, p := range points {
+			if rect.ContainsPoint(p) {
+				expected = append(expected, i)
+			}
+		}
+		assert.Equal(t, expected, sortedIDs(h.QueryRect(rect, nil)))
+
+		var visited []int
+		h.VisitRect(rect, func(cell Vec2i, ids []int) bool {
+			assert.True(t, h.CellRect(cell).Intersects(rect))
+			visited = append(visited, ids...)
+			return true
+		})
+		for _, id := range expected {
+			assert.Contains(t, visited, id)
+		}
+	}
+
+	cells := 0
+	h.VisitRect(Rectf{Vec2f{-10, -10}, Vec2f{10, 10}}, func(cell Vec2i, ids []int) bool {
+		cells++
+		return cells < 3
+	})
+	assert.Equal(t, 3, cells)
+
+	// ranges with more cells than stored in the grid
+	h = NewSpatialHash2(0.001)
+	h.Insert(0, Vec2f{-1000, 5})
+	h.Insert(1, Vec2f{2000, -3000})
+	h.Insert(2, Vec2f{5000, 5000})
+	assert.Equal(t, []int{0, 1}, sortedIDs(h.QueryRect(Rectf{Vec2f{-4000, -4000}, Vec2f{4000, 4000}}, nil)))
+	assert.Equal(t, []int{0}, h.QueryRadius(Vec2f{}, 1500, nil))
+	cells = 0
+	h.VisitRect(Rectf{Vec2f{-1e6, -1e6}, Vec2f{1e6, 1e6}}, func(cell Vec2i, ids []int) bool {
+		cells++
+		return false
+	})
+	assert.Equal(t, 1, cells)
+}
+
+func TestSpatialHash3_Cell(t *testing.T) {
+	h := NewSpatialHash3(2)
+	assert.Equal(t, float32(2), h.CellSize())
+	assert.Equal(t, Vec3i{0, 0, 0}, h.Cell(Vec3f{0, 1.9, 0}))
+	assert.Equal(t, Vec3i{1, 2, -1}, h.Cell(Vec3f{2, 5, -2}))
+	assert.Equal(t, Vec3i{-1, -2, 0}, h.Cell(Vec3f{-0.1, -2.5, 1}))
+	assert.Equal(t, Box3f{Vec3f{-2, 4, 0}, Vec3f{0, 6, 2}}, h.CellBox(Vec3i{-1, 2, 0}))
+
+	min, max := h.CellRange(Box3f{Vec3f{-1, 1, 0}, Vec3f{3, 1.5, 4}})
+	assert.Equal(t, Vec3i{-1, 0, 0}, min)
+	assert.Equal(t, Vec3i{1, 0, 2}, max)
+
+	assert.Panics(t, func() { NewSpatialHash3(0) })
+	assert.Panics(t, func() { NewSpatialHash3(-1) })
+}
+
+func TestSpatialHash3_InsertRemove(t *testing.T) {
+	h := NewSpatialHash3(1.5)
+	points := randomPoints3f(500, 5)
+	for i, p := range points {
+		h.Insert(i, p)
+	}
+	assert.Equal(t, len(points), h.Len())
+
+	moved := randomPoints3f(500, 6)
+	for i := range points {
+		if i%3 == 0 {
+			assert.True(t, h.Remove(i))
+		} else if i%3 == 1 {
+			assert.True(t, h.Move(i, moved[i]))
+			points[i] = moved[i]
+		} else {
+			h.Insert(i, moved[i])
+			points[i] = moved[i]
+		}
+	}
+	assert.False(t, h.Remove(0))
+	assert.False(t, h.Move(0, Vec3f{}))
+	assert.Equal(t, 333, h.Len())
+
+	pos, ok := h.Position(1)
+	assert.True(t, ok)
+	assert.Equal(t, moved[1], pos)
+
+	for cell, ids := range h.cells {
+		for _, id := range ids {
+			assert.Equal(t, cell, h.Cell(points[id]))
+		}
+	}
+	for i := range points {
+		h.Remove(i)
+	}
+	assert.Empty(t, h.cells)
+}
+
+func TestSpatialHash3_Query(t *testing.T) {
+	h := NewSpatialHash3(2)
+	points := randomPoints3f(1000, 7)
+	for i, p := range points {
+		h.Insert(i, p)
+	}
+
+	for _, pos := range randomPoints3f(50, 8) {
+		var expected []int
+		for i, p := range points {
+			if p.Distance(pos) <= 4 {
+				expected = append(expected, i)
+			}
+		}
+		assert.Equal(t, expected, sortedIDs(h.QueryRadius(pos, 4, nil)))
+
+		box := Box3f{pos, pos.Add(Vec3f{3, 2, 5})}
+		expected = nil
+		for i, p := range points {
+			if box.ContainsPoint(p) {
+				expected = append(expected, i)
+			}
+		}
+		assert.Equal(t, expected, sortedIDs(h.QueryBox(box, nil)))
+
+		var visited []int
+		h.VisitBox(box, func(cell Vec3i, ids []int) bool {
+			assert.True(t, h.CellBox(cell).Intersects(box))
+			visited = append(visited, ids...)
+			return true
+		})
+		for _, id := range expected {
+			assert.Contains(t, visited, id)
+		}
+	}
+
+	// ranges with more cells than stored in the grid
+	h = NewSpatialHash3(0.001)
+	h.Insert(0, Vec3f{-1000, 5, 0})
+	h.Insert(1, Vec3f{2000, -3000, 100})
+	h.Insert(2, Vec3f{5000, 5000, 5000})
+	assert.Equal(t, []int{0, 1}, sortedIDs(h.QueryBox(Box3f{Vec3f{-4000, -4000, -4000}, Vec3f{4000, 4000, 4000}}, nil)))
+	assert.Equal(t, []int{0}, h.QueryRadius(Vec3f{}, 1500, nil))
+}
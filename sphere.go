@@ -0,0 +1,298 @@
+package vmath
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/maja42/vmath/math32"
+)
+
+// Sphere represents a sphere in 3D space.
+type Sphere struct {
+	Center Vec3f
+	Radius float32
+}
+
+func (s Sphere) String() string {
+	return fmt.Sprintf("Sphere([%f x %f x %f], %f)", s.Center[0], s.Center[1], s.Center[2], s.Radius)
+}
+
+// Box3f returns the smallest axis-aligned box that contains the sphere.
+func (s Sphere) Box3f() Box3f {
+	return Box3f{
+		Min: s.Center.SubScalar(s.Radius),
+		Max: s.Center.AddScalar(s.Radius),
+	}
+}
+
+// Volume returns the sphere's volume.
+func (s Sphere) Volume() float32 {
+	return 4.0 / 3.0 * math.Pi * s.Radius * s.Radius * s.Radius
+}
+
+// ContainsPoint checks if a given point resides within the sphere.
+// Points on the surface are also considered to be contained.
+func (s Sphere) ContainsPoint(point Vec3f) bool {
+	return s.Center.SquareDistance(point) <= s.Radius*s.Radius
+}
+
+// ContainsSphere checks if this sphere completely contains another sphere.
+func (s Sphere) ContainsSphere(other Sphere) bool {
+	if other.Radius > s.Radius {
+		return false
+	}
+	d := s.Radius - other.Radius
+	return s.Center.SquareDistance(other.Center) <= d*d
+}
+
+// Intersects checks if this sphere intersects another sphere.
+// Touching spheres are also considered to intersect.
+func (s Sphere) Intersects(other Sphere) bool {
+	r := s.Radius + other.Radius
+	return s.Center.SquareDistance(other.Center) <= r*r
+}
+
+// IntersectsBox3f checks if the sphere intersects an axis-aligned box.
+// Touching volumes are also considered to intersect.
+func (s Sphere) IntersectsBox3f(box Box3f) bool {
+	return box.SquarePointDistance(s.Center) <= s.Radius*s.Radius
+}
+
+// IntersectRay calculates the first intersection of a ray with the sphere.
+// See Ray3f.IntersectSphere for details.
+func (s Sphere) IntersectRay(ray Ray3f) (RayHit, bool) {
+	return ray.IntersectSphere(s.Center, s.Radius)
+}
+
+// Merge returns the smallest sphere that contains both spheres.
+func (s Sphere) Merge(other Sphere) Sphere {
+	// Source: "Real-Time Collision Detection" by Christer Ericson, chapter 4.3.2
+	if s.ContainsSphere(other) {
+		return s
+	}
+	if other.ContainsSphere(s) {
+		return other
+	}
+	dist := s.Center.Distance(other.Center)
+	radius := (dist + s.Radius + other.Radius) / 2
+	center := s.Center
+	if dist > 0 {
+		center = center.Add(other.Center.Sub(s.Center).MulScalar((radius - s.Radius) / dist))
+	}
+	return Sphere{center, radius}
+}
+
+// ExtendPoint returns the smallest sphere that contains both, the original sphere and the given point.
+// The sphere's center is moved towards the point if necessary.
+func (s Sphere) ExtendPoint(point Vec3f) Sphere {
+	return s.Merge(Sphere{point, 0})
+}
+
+// BoundingSphere returns a sphere that contains all given points.
+// The sphere is calculated with Ritter's algorithm, which is fast,
+// but usually results in spheres that are 5-20% bigger than the minimal bounding sphere.
+// If no points are given, an empty sphere at the origin is returned.
+func BoundingSphere(points []Vec3f) Sphere {
+	// Source: "An Efficient Bounding Sphere" by Jack Ritter, Graphics Gems, 1990.
+	if len(points) == 0 {
+		return Sphere{}
+	}
+	// find two points that are far away from each other
+	farthest := func(from Vec3f) Vec3f {
+		best, bestDist := from, float32(-1)
+		for _, p := range points {
+			if d := from.SquareDistance(p); d > bestDist {
+				best, bestDist = p, d
+			}
+		}
+		return best
+	}
+	a := farthest(points[0])
+	b := farthest(a)
+	s := Sphere{
+		Center: a.Lerp(b, 0.5),
+		Radius: a.Distance(b) / 2,
+	}
+	// grow the sphere to include all points
+	for _, p := range points {
+		if !s.ContainsPoint(p) {
+			s = s.ExtendPoint(p)
+		}
+	}
+	return s.enclose(points)
+}
+
+// MinimumBoundingSphere returns the smallest sphere that contains all given points.
+// The sphere is calculated with Welzl's algorithm in expected linear time.
+// If no points are given, an empty sphere at the origin is returned.
+func MinimumBoundingSphere(points []Vec3f) Sphere {
+	// Source: "Smallest enclosing disks (balls and ellipsoids)" by Emo Welzl, 1991.
+	// Uses the iterative formulation, which avoids deep recursion.
+	if len(points) == 0 {
+		return Sphere{}
+	}
+
+	pts := make([]vec3d, len(points))
+	for i, p := range points {
+		pts[i] = vec3d{float64(p[0]), float64(p[1]), float64(p[2])}
+	}
+	// random order results in expected linear runtime; use a fixed seed for reproducible results
+	rnd := rand.New(rand.NewSource(1))
+	rnd.Shuffle(len(pts), func(i, j int) {
+		pts[i], pts[j] = pts[j], pts[i]
+	})
+
+	s := sphered{pts[0], 0}
+	for i := 1; i < len(pts); i++ {
+		if s.contains(pts[i]) {
+			continue
+		}
+		s = sphered{pts[i], 0}
+		for j := 0; j < i; j++ {
+			if s.contains(pts[j]) {
+				continue
+			}
+			s = sphereFrom2(pts[i], pts[j])
+			for k := 0; k < j; k++ {
+				if s.contains(pts[k]) {
+					continue
+				}
+				s = sphereFrom3(pts[i], pts[j], pts[k])
+				for l := 0; l < k; l++ {
+					if s.contains(pts[l]) {
+						continue
+					}
+					s = sphereFrom4(pts[i], pts[j], pts[k], pts[l])
+				}
+			}
+		}
+	}
+	return Sphere{
+		Center: Vec3f{float32(s.center[0]), float32(s.center[1]), float32(s.center[2])},
+		Radius: float32(math.Sqrt(s.sqRadius)),
+	}.enclose(points)
+}
+
+// enclose grows the sphere's radius until ContainsPoint succeeds for all given points.
+// This compensates rounding errors, so that bounding spheres are conservative.
+func (s Sphere) enclose(points []Vec3f) Sphere {
+	for _, p := range points {
+		s.Radius = math32.Max(s.Radius, s.Center.Distance(p))
+		for !s.ContainsPoint(p) {
+			s.Radius = math.Nextafter32(s.Radius, math32.Infinity)
+		}
+	}
+	return s
+}
+
+// vec3d is a float64 vector for intermediate calculations that require higher precision.
+type vec3d [3]float64
+
+func (v vec3d) add(o vec3d) vec3d {
+	return vec3d{v[0] + o[0], v[1] + o[1], v[2] + o[2]}
+}
+
+func (v vec3d) sub(o vec3d) vec3d {
+	return vec3d{v[0] - o[0], v[1] - o[1], v[2] - o[2]}
+}
+
+func (v vec3d) mul(s float64) vec3d {
+	return vec3d{v[0] * s, v[1] * s, v[2] * s}
+}
+
+func (v vec3d) dot(o vec3d) float64 {
+	return v[0]*o[0] + v[1]*o[1] + v[2]*o[2]
+}
+
+func (v vec3d) sqDist(o vec3d) float64 {
+	d := v.sub(o)
+	return d.dot(d)
+}
+
+func (v vec3d) cross(o vec3d) vec3d {
+	return vec3d{
+		v[1]*o[2] - v[2]*o[1],
+		v[2]*o[0] - v[0]*o[2],
+		v[0]*o[1] - v[1]*o[0],
+	}
+}
+
+// sphered is a float64 sphere for the minimum bounding sphere calculation.
+type sphered struct {
+	center   vec3d
+	sqRadius float64
+}
+
+func (s sphered) contains(p vec3d) bool {
+	// allow for small rounding errors; otherwise points on the boundary can cause unnecessary recalculations
+	return s.center.sqDist(p) <= s.sqRadius*(1+1e-9)+1e-12
+}
+
+// sphereFrom2 returns the smallest sphere with both points on its surface.
+func sphereFrom2(a, b vec3d) sphered {
+	center := a.add(b).mul(0.5)
+	return sphered{center, center.sqDist(a)}
+}
+
+// sphereFrom3 returns the smallest sphere with all three points on its surface.
+func sphereFrom3(a, b, c vec3d) sphered {
+	// Source: https://en.wikipedia.org/wiki/Circumscribed_circle#Higher_dimensions
+	ab := b.sub(a)
+	ac := c.sub(a)
+	n := ab.cross(ac)
+	denom := 2 * n.dot(n)
+	if denom <= 1e-12*ab.dot(ab)*ac.dot(ac) { // collinear
+		return largestSphereFrom2(a, b, c)
+	}
+	offset := n.cross(ab).mul(ac.dot(ac)).add(ac.cross(n).mul(ab.dot(ab))).mul(1 / denom)
+	return sphered{a.add(offset), offset.dot(offset)}
+}
+
+// sphereFrom4 returns the sphere with all four points on its surface.
+func sphereFrom4(a, b, c, d vec3d) sphered {
+	// Source: https://mathworld.wolfram.com/Circumsphere.html (solved relative to point a)
+	ab := b.sub(a)
+	ac := c.sub(a)
+	ad := d.sub(a)
+	det := ab.dot(ac.cross(ad))
+	if math.Abs(det) <= 1e-9*math.Sqrt(ab.dot(ab)*ac.dot(ac)*ad.dot(ad)) { // coplanar
+		// use the smallest circumscribed sphere of three points that also contains the fourth
+		best := largestSphereFrom2(a, b, c).extend(d)
+		for _, s := range [...]sphered{sphereFrom3(a, b, c), sphereFrom3(a, b, d), sphereFrom3(a, c, d), sphereFrom3(b, c, d)} {
+			if s.contains(a) && s.contains(b) && s.contains(c) && s.contains(d) && s.sqRadius < best.sqRadius {
+				best = s
+			}
+		}
+		return best
+	}
+	offset := ac.cross(ad).mul(ab.dot(ab)).
+		add(ad.cross(ab).mul(ac.dot(ac))).
+		add(ab.cross(ac).mul(ad.dot(ad))).
+		mul(1 / (2 * det))
+	return sphered{a.add(offset), offset.dot(offset)}
+}
+
+// largestSphereFrom2 returns the sphere around the two points that are farthest apart.
+func largestSphereFrom2(a, b, c vec3d) sphered {
+	s := sphereFrom2(a, b)
+	if other := sphereFrom2(a, c); other.sqRadius > s.sqRadius {
+		s = other
+	}
+	if other := sphereFrom2(b, c); other.sqRadius > s.sqRadius {
+		s = other
+	}
+	return s
+}
+
+// extend grows the sphere so that it contains the given point.
+func (s sphered) extend(p vec3d) sphered {
+	if s.contains(p) {
+		return s
+	}
+	radius := math.Sqrt(s.sqRadius)
+	dist := math.Sqrt(s.center.sqDist(p))
+	newRadius := (radius + dist) / 2
+	center := s.center.add(p.sub(s.center).mul((newRadius - radius) / dist))
+	return sphered{center, newRadius * newRadius}
+}
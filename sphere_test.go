@@ -0,0 +1,161 @@
+package vmath
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/maja42/vmath/math32"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSphere_String(t *testing.T) {
+	s := Sphere{Vec3f{1, 2, 3}, 4}
+	assert.Equal(t, "Sphere([1.000000 x 2.000000 x 3.000000], 4.000000)", s.String())
+}
+
+func TestSphere_Box3f(t *testing.T) {
+	s := Sphere{Vec3f{1, 2, 3}, 4}
+	assert.Equal(t, Box3f{Vec3f{-3, -2, -1}, Vec3f{5, 6, 7}}, s.Box3f())
+}
+
+func TestSphere_ContainsPoint(t *testing.T) {
+	s := Sphere{Vec3f{1, 2, 3}, 2}
+	assert.True(t, s.ContainsPoint(Vec3f{1, 2, 3}))
+	assert.True(t, s.ContainsPoint(Vec3f{1, 4, 3}))
+	assert.True(t, s.ContainsPoint(Vec3f{2, 3, 4}))
+	assert.False(t, s.ContainsPoint(Vec3f{1, 4.1, 3}))
+	assert.False(t, s.ContainsPoint(Vec3f{3, 4, 3}))
+}
+
+func TestSphere_ContainsSphere(t *testing.T) {
+	s := Sphere{Vec3f{0, 0, 0}, 5}
+	assert.True(t, s.ContainsSphere(s))
+	assert.True(t, s.ContainsSphere(Sphere{Vec3f{2, 0, 0}, 3}))
+	assert.False(t, s.ContainsSphere(Sphere{Vec3f{2.5, 0, 0}, 3}))
+	assert.False(t, s.ContainsSphere(Sphere{Vec3f{0, 0, 0}, 6}))
+}
+
+func TestSphere_Intersects(t *testing.T) {
+	s := Sphere{Vec3f{0, 0, 0}, 2}
+	assert.True(t, s.Intersects(Sphere{Vec3f{3, 0, 0}, 1.5}))
+	assert.True(t, s.Intersects(Sphere{Vec3f{0, 3, 0}, 1}))
+	assert.False(t, s.Intersects(Sphere{Vec3f{0, 0, 3}, 0.5}))
+}
+
+func TestSphere_IntersectsBox3f(t *testing.T) {
+	s := Sphere{Vec3f{0, 0, 0}, 2}
+	assert.True(t, s.IntersectsBox3f(Box3f{Vec3f{-1, -1, -1}, Vec3f{1, 1, 1}}))
+	assert.True(t, s.IntersectsBox3f(Box3f{Vec3f{1, 1, -1}, Vec3f{5, 5, 5}}))
+	assert.True(t, s.IntersectsBox3f(Box3f{Vec3f{-10, -10, -10}, Vec3f{10, 10, 10}}))
+	assert.False(t, s.IntersectsBox3f(Box3f{Vec3f{1.5, 1.5, -1}, Vec3f{5, 5, 5}}))
+	assert.False(t, s.IntersectsBox3f(Box3f{Vec3f{-1, -1, 2.5}, Vec3f{1, 1, 5}}))
+}
+
+func TestSphere_IntersectRay(t *testing.T) {
+	s := Sphere{Vec3f{0, 0, 0}, 2}
+	hit, ok := s.IntersectRay(Ray3f{Vec3f{0, 0, 5}, Vec3f{0, 0, -1}})
+	assert.True(t, ok)
+	AssertFloat(t, 3, hit.Distance)
+}
+
+func TestSphere_Merge(t *testing.T) {
+	a := Sphere{Vec3f{0, 0, 0}, 1}
+	b := Sphere{Vec3f{4, 0, 0}, 1}
+	m := a.Merge(b)
+	AssertVec3f(t, Vec3f{2, 0, 0}, m.Center)
+	AssertFloat(t, 3, m.Radius)
+
+	// different sizes
+	b = Sphere{Vec3f{0, 5, 0}, 3}
+	m = a.Merge(b)
+	AssertVec3f(t, Vec3f{0, 3.5, 0}, m.Center)
+	AssertFloat(t, 4.5, m.Radius)
+
+	// containment
+	b = Sphere{Vec3f{0, 0.5, 0}, 3}
+	assert.Equal(t, b, a.Merge(b))
+	assert.Equal(t, b, b.Merge(a))
+
+	m = a.ExtendPoint(Vec3f{0, 0, 3})
+	AssertVec3f(t, Vec3f{0, 0, 1}, m.Center)
+	AssertFloat(t, 2, m.Radius)
+}
+
+func TestBoundingSphere(t *testing.T) {
+	assert.Equal(t, Sphere{}, BoundingSphere(nil))
+	assert.Equal(t, Sphere{Vec3f{1, 2, 3}, 0}, BoundingSphere([]Vec3f{{1, 2, 3}}))
+
+	// the sphere is conservative despite rounding errors
+	for seed := int64(0); seed < 50; seed++ {
+		points := randomPoints3f(100, seed)
+		for i := range points {
+			points[i] = points[i].Add(Vec3f{100, -30, 7})
+		}
+		s := BoundingSphere(points)
+		for _, p := range points {
+			assert.True(t, s.ContainsPoint(p))
+		}
+	}
+}
+
+func TestMinimumBoundingSphere(t *testing.T) {
+	assert.Equal(t, Sphere{}, MinimumBoundingSphere(nil))
+	assert.Equal(t, Sphere{Vec3f{1, 2, 3}, 0}, MinimumBoundingSphere([]Vec3f{{1, 2, 3}}))
+
+	// two points
+	s := MinimumBoundingSphere([]Vec3f{{1, 0, 0}, {5, 0, 0}})
+	AssertVec3f(t, Vec3f{3, 0, 0}, s.Center)
+	AssertFloat(t, 2, s.Radius)
+
+	// cube corners
+	cube := Box3f{Vec3f{-1, -1, -1}, Vec3f{1, 1, 1}}.Add(Vec3f{3, 4, 5}).Corners()
+	s = MinimumBoundingSphere(append(cube[:], Vec3f{3, 4, 5}, Vec3f{3.5, 4, 5}))
+	AssertVec3f(t, Vec3f{3, 4, 5}, s.Center)
+	AssertFloat(t, math32.Sqrt(3), s.Radius)
+
+	// right triangle: the radius is not exactly representable
+	points := []Vec3f{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}
+	s = MinimumBoundingSphere(points)
+	AssertVec3f(t, Vec3f{0.5, 0.5, 0}, s.Center)
+	AssertFloat(t, math32.Sqrt(0.5), s.Radius)
+	for _, p := range points {
+		assert.True(t, s.ContainsPoint(p))
+	}
+
+	// obtuse triangle: the longest edge defines the sphere
+	s = MinimumBoundingSphere([]Vec3f{{-2, 0, 0}, {2, 0, 0}, {0, 0.5, 0}})
+	AssertVec3f(t, Vec3f{0, 0, 0}, s.Center)
+	AssertFloat(t, 2, s.Radius)
+
+	// regular tetrahedron
+	s = MinimumBoundingSphere([]Vec3f{{1, 1, 1}, {1, -1, -1}, {-1, 1, -1}, {-1, -1, 1}})
+	AssertVec3f(t, Vec3f{0, 0, 0}, s.Center)
+	AssertFloat(t, math32.Sqrt(3), s.Radius)
+
+	// collinear and duplicate points
+	s = MinimumBoundingSphere([]Vec3f{{0, 0, 0}, {1, 1, 1}, {2, 2, 2}, {2, 2, 2}, {-1, -1, -1}})
+	AssertVec3f(t, Vec3f{0.5, 0.5, 0.5}, s.Center)
+	AssertFloat(t, 1.5*math32.Sqrt(3), s.Radius)
+
+	// random points: contains everything and is never bigger than Ritter's approximation
+	for seed := int64(0); seed < 50; seed++ {
+		points := randomPoints3f(100, seed)
+		for i := range points {
+			points[i] = points[i].Add(Vec3f{100, -30, 7})
+		}
+		s := MinimumBoundingSphere(points)
+		for _, p := range points {
+			assert.True(t, s.ContainsPoint(p))
+		}
+		assert.True(t, s.Radius <= BoundingSphere(points).Radius*(1+eps))
+	}
+}
+
+func randomPoints3f(count int, seed int64) []Vec3f {
+	rnd := rand.New(rand.NewSource(seed))
+	points := make([]Vec3f, count)
+	for i := range points {
+		points[i] = Vec3f{rnd.Float32()*20 - 10, rnd.Float32()*20 - 10, rnd.Float32()*20 - 10}
+	}
+	return points
+}
@@ -0,0 +1,301 @@
+package vmath
+
+import (
+	"math"
+
+	"github.com/maja42/vmath/math32"
+)
+
+// StrokeOptions configures polyline stroking.
+type StrokeOptions struct {
+	// Width is the line's width.
+	Width float32
+	// Join defines how consecutive line segments are connected.
+	Join JoinType
+	// Cap defines how the ends of open lines are closed.
+	Cap CapType
+	// Closed connects the polyline's last point with the first one.
+	Closed bool
+	// MiterLimit is the maximum distance of miter joins from the original vertex, in multiples of half the line width.
+	// Sharper corners are squared off, like in OffsetOptions. Defaults to 2 if not set; values below 1 always square off corners.
+	MiterLimit float32
+	// ArcTolerance is the maximum distance between round joins and caps and the true arc.
+	// Defaults to 1% of half the line width if not set.
+	ArcTolerance float32
+	// Distances enables the calculation of each vertex' distance along the line, for example for dashing.
+	Distances bool
+}
+
+// StrokeMesh is a triangle mesh representing a stroked polyline.
+type StrokeMesh struct {
+	Vertices []Vec2f
+	// Indices contains three vertex indices per triangle. All triangles are in counter-clockwise order.
+	Indices []uint32
+	// Distances contains each vertex' distance along the line, if enabled.
+	// Vertices of square and round caps extend beyond the line's start (negative) and end.
+	Distances []float32
+}
+
+// StrokePolyline converts a polyline into triangles that cover a line with the given width.
+// The triangles don't overlap, unless the line intersects itself,
+// or the inner side of a corner is longer than half of an adjacent segment.
+// Consecutive duplicate points are ignored. Single points result in a square or circle, depending on the cap type.
+func StrokePolyline(points []Vec2f, opts StrokeOptions) StrokeMesh {
+	s := stroker{
+		opts:       opts,
+		halfWidth:  math32.Abs(opts.Width) / 2,
+		miterLimit: opts.MiterLimit,
+	}
+	if s.miterLimit <= 0 {
+		s.miterLimit = 2
+	}
+	points = removeDuplicates(points, opts.Closed)
+	if len(points) == 0 || s.halfWidth == 0 {
+		return s.mesh
+	}
+	s.arcStep = arcStep(s.halfWidth, opts.ArcTolerance)
+	if len(points) == 1 {
+		s.dot(points[0])
+		return s.mesh
+	}
+	closed := opts.Closed && len(points) >= 3
+
+	segments := len(points) - 1
+	if closed {
+		segments++
+	}
+	dirs := make([]Vec2f, segments)
+	dists := make([]float32, segments+1)
+	for i := range dirs {
+		a, b := points[i], points[(i+1)%len(points)]
+		dirs[i] = b.Sub(a).Normalize()
+		dists[i+1] = dists[i] + a.Distance(b)
+	}
+
+	// On the inner side of joints, both segments end at the intersection of their offset lines.
+	corners := make([]strokeCorner, len(points))
+	for i := range points {
+		if !closed && (i == 0 || i == len(points)-1) {
+			continue
+		}
+		prev, next := (i+segments-1)%segments, i%segments
+		maxCut := math32.Min(dists[prev+1]-dists[prev], dists[next+1]-dists[next]) / 2
+		corners[i] = s.corner(points[i], dirs[prev], dirs[next], maxCut)
+	}
+
+	for i, dir := range dirs {
+		a, b := points[i], points[(i+1)%len(points)]
+		distA, distB := dists[i], dists[i+1]
+		if !closed && opts.Cap == CapSquare {
+			ext := dir.MulScalar(s.halfWidth)
+			if i == 0 {
+				a = a.Sub(ext)
+				distA -= s.halfWidth
+			}
+			if i == segments-1 {
+				b = b.Add(ext)
+				distB += s.halfWidth
+			}
+		}
+		normal := dir.NormalVec(true).MulScalar(s.halfWidth)
+		aLeft, aRight := corners[i].sides(a, normal)
+		bLeft, bRight := corners[(i+1)%len(points)].sides(b, normal)
+		aLeftIdx := s.vertex(aLeft, distA)
+		aRightIdx := s.vertex(aRight, distA)
+		bLeftIdx := s.vertex(bLeft, distB)
+		bRightIdx := s.vertex(bRight, distB)
+		s.triangle(aRightIdx, bRightIdx, bLeftIdx)
+		s.triangle(aRightIdx, bLeftIdx, aLeftIdx)
+	}
+
+	for i := 1; i < segments; i++ {
+		s.join(points[i], corners[i], dirs[i-1], dirs[i], dists[i], dists[i])
+	}
+	if closed {
+		// the first segment starts at distance 0, the last one ends at the total length
+		s.join(points[0], corners[0], dirs[segments-1], dirs[0], dists[segments], 0)
+	} else if opts.Cap == CapRound {
+		s.roundCap(points[0], dirs[0], true, 0)
+		s.roundCap(points[len(points)-1], dirs[segments-1], false, dists[segments])
+	}
+	return s.mesh
+}
+
+// stroker creates the triangle mesh of stroked polylines.
+type stroker struct {
+	opts       StrokeOptions
+	halfWidth  float32
+	miterLimit float32
+	arcStep    float32 // maximum angle between two vertices of round joins and caps
+	mesh       StrokeMesh
+}
+
+// vertex adds a new vertex and returns its index.
+func (s *stroker) vertex(pos Vec2f, dist float32) uint32 {
+	s.mesh.Vertices = append(s.mesh.Vertices, pos)
+	if s.opts.Distances {
+		s.mesh.Distances = append(s.mesh.Distances, dist)
+	}
+	return uint32(len(s.mesh.Vertices) - 1)
+}
+
+// triangle adds a triangle, ensuring counter-clockwise order.
+func (s *stroker) triangle(a, b, c uint32) {
+	v := s.mesh.Vertices
+	if v[b].Sub(v[a]).MagCross(v[c].Sub(v[a])) < 0 {
+		b, c = c, b
+	}
+	s.mesh.Indices = append(s.mesh.Indices, a, b, c)
+}
+
+// fan adds a triangle fan around the center vertex, through the given positions.
+func (s *stroker) fan(center uint32, positions []Vec2f, dist func(Vec2f) float32) {
+	prev := s.vertex(positions[0], dist(positions[0]))
+	for _, pos := range positions[1:] {
+		cur := s.vertex(pos, dist(pos))
+		s.triangle(center, prev, cur)
+		prev = cur
+	}
+}
+
+// arc returns the points of a circular arc around the center, starting at center+from and covering the signed angle.
+func (s *stroker) arc(center, from Vec2f, angle float32) []Vec2f {
+	steps := int(math32.Ceil(math32.Abs(angle) / s.arcStep))
+	if steps < 1 {
+		steps = 1
+	}
+	points := make([]Vec2f, steps+1)
+	for i := range points {
+		points[i] = center.Add(from.Rotate(angle * float32(i) / float32(steps)))
+	}
+	return points
+}
+
+// strokeCorner is the inner side of a joint, where both adjacent segments are cut.
+type strokeCorner struct {
+	pos  Vec2f
+	left bool // the inner side is left of the line
+	cut  bool // false if the segments end at the joint itself
+}
+
+// corner returns the inner corner of a vertex p, between the segments with the directions dir1 and dir2.
+// The segments are not cut if the corner is further than maxCut away from p along the segments.
+func (s *stroker) corner(p, dir1, dir2 Vec2f, maxCut float32) strokeCorner {
+	angle := AngleDiff(dir1.FlatAngle(), dir2.FlatAngle())
+	// the inner side is on the left for left turns
+	left := angle > 0
+	// the offset lines intersect halfWidth*tan(angle/2) before the joint
+	cut := s.halfWidth * math32.Tan(math32.Abs(angle)/2)
+	if !(cut <= maxCut) {
+		return strokeCorner{}
+	}
+	pos := p.Add(dir1.NormalVec(left).MulScalar(s.halfWidth)).Sub(dir1.MulScalar(cut))
+	return strokeCorner{pos, left, true}
+}
+
+// sides returns the left and right vertex of a segment at the point p, with the given left normal.
+func (c strokeCorner) sides(p, normal Vec2f) (Vec2f, Vec2f) {
+	left, right := p.Add(normal), p.Sub(normal)
+	if c.cut {
+		if c.left {
+			left = c.pos
+		} else {
+			right = c.pos
+		}
+	}
+	return left, right
+}
+
+// join fills the gap on the outer side of a vertex p, between the segments with the directions dir1 and dir2.
+// dist1 and dist2 are the distances of the segments' vertices at p.
+// If they differ, each half of the join uses the distance of its adjacent segment.
+func (s *stroker) join(p Vec2f, corner strokeCorner, dir1, dir2 Vec2f, dist1, dist2 float32) {
+	angle := AngleDiff(dir1.FlatAngle(), dir2.FlatAngle())
+	if math32.Abs(angle) < Epsilon {
+		return
+	}
+	// the outer side is on the right for left turns
+	n1 := dir1.NormalVec(angle < 0).MulScalar(s.halfWidth)
+	n2 := dir2.NormalVec(angle < 0).MulScalar(s.halfWidth)
+
+	// the join covers the area between the segments' outer sides and the inner corner
+	centerPos := p
+	if corner.cut {
+		centerPos = corner.pos
+	}
+	var positions []Vec2f
+	switch s.opts.Join {
+	case JoinMiter:
+		// the miter's length is 1/cos(angle/2), in multiples of the half width
+		if cos := math32.Cos(angle / 2); cos*s.miterLimit >= 1 {
+			tip := p.Add(n1.Add(n2).Normalize().MulScalar(s.halfWidth / cos))
+			positions = []Vec2f{p.Add(n1), tip, p.Add(n2)}
+		} else {
+			positions = s.squareJoin(p, n1, n2, dir1, angle)
+		}
+	case JoinRound:
+		positions = s.arc(p, n1, angle)
+	case JoinSquare:
+		positions = s.squareJoin(p, n1, n2, dir1, angle)
+	default:
+		positions = []Vec2f{p.Add(n1), p.Add(n2)}
+	}
+	if dist1 == dist2 {
+		s.fan(s.vertex(centerPos, dist1), positions, func(Vec2f) float32 { return dist1 })
+		return
+	}
+	m := len(positions) / 2
+	if len(positions)%2 == 0 { // split the middle edge
+		mid := positions[m-1].Lerp(positions[m], 0.5)
+		positions = append(positions[:m], append([]Vec2f{mid}, positions[m:]...)...)
+	}
+	s.fan(s.vertex(centerPos, dist1), positions[:m+1], func(Vec2f) float32 { return dist1 })
+	s.fan(s.vertex(centerPos, dist2), positions[m:], func(Vec2f) float32 { return dist2 })
+}
+
+// squareJoin returns the outline of a squared off corner at p, between the outer offsets n1 and n2.
+func (s *stroker) squareJoin(p, n1, n2, dir1 Vec2f, angle float32) []Vec2f {
+	bisector := n1.Add(n2)
+	if bisector.SquareLength() < Epsilon { // the line reverses its direction
+		bisector = dir1
+	}
+	bisector = bisector.Normalize().MulScalar(s.halfWidth)
+	perp := bisector.NormalVec(true)
+	if perp.Dot(n1) < 0 {
+		perp = perp.Negate()
+	}
+	// the corner's edges are perpendicular to the bisector; their half width is halfWidth*tan(angle/4)
+	perp = perp.MulScalar(math32.Tan(math32.Abs(angle) / 4))
+	return []Vec2f{p.Add(n1), p.Add(bisector).Add(perp), p.Add(bisector).Sub(perp), p.Add(n2)}
+}
+
+// roundCap adds a half circle at the line's start or end point p.
+// dir is the line's direction at p.
+func (s *stroker) roundCap(p, dir Vec2f, start bool, dist float32) {
+	outward := dir
+	if start {
+		outward = dir.Negate()
+	}
+	from := outward.NormalVec(false).MulScalar(s.halfWidth)
+	center := s.vertex(p, dist)
+	s.fan(center, s.arc(p, from, math.Pi), func(pos Vec2f) float32 {
+		return dist + pos.Sub(p).Dot(dir)
+	})
+}
+
+// dot adds a square or circle around a single point.
+func (s *stroker) dot(p Vec2f) {
+	dist := func(Vec2f) float32 { return 0 }
+	switch s.opts.Cap {
+	case CapSquare:
+		hw := s.halfWidth
+		center := s.vertex(p, 0)
+		s.fan(center, []Vec2f{
+			p.Add(Vec2f{-hw, -hw}), p.Add(Vec2f{hw, -hw}), p.Add(Vec2f{hw, hw}),
+			p.Add(Vec2f{-hw, hw}), p.Add(Vec2f{-hw, -hw}),
+		}, dist)
+	case CapRound:
+		center := s.vertex(p, 0)
+		s.fan(center, s.arc(p, Vec2f{s.halfWidth, 0}, 2*math.Pi), dist)
+	}
+}
@@ -0,0 +1,134 @@
+package vmath
+
+import (
+	"math"
+	"testing"
+
+	"github.com/maja42/vmath/math32"
+	"github.com/stretchr/testify/assert"
+)
+
+// strokeArea returns the area covered by the mesh's triangles, and checks that all triangles are counter-clockwise.
+func strokeArea(t *testing.T, mesh StrokeMesh) float32 {
+	t.Helper()
+	var triangles []Polygon2f
+	for i := 0; i < len(mesh.Indices); i += 3 {
+		tri := Polygon2f{mesh.Vertices[mesh.Indices[i]], mesh.Vertices[mesh.Indices[i+1]], mesh.Vertices[mesh.Indices[i+2]]}
+		assert.True(t, tri.SignedArea() >= 0, "triangle %d is clockwise", i/3)
+		triangles = append(triangles, tri)
+	}
+	var area float32
+	for _, poly := range PolygonBoolean2f(triangles, nil, OpUnion, FillNonZero) {
+		area += poly.Area()
+	}
+	return area
+}
+
+// strokeTriangleArea returns the sum of the mesh's triangle areas, which exceeds the covered area if triangles overlap.
+func strokeTriangleArea(mesh StrokeMesh) float32 {
+	var area float32
+	for i := 0; i < len(mesh.Indices); i += 3 {
+		area += Polygon2f{mesh.Vertices[mesh.Indices[i]], mesh.Vertices[mesh.Indices[i+1]], mesh.Vertices[mesh.Indices[i+2]]}.Area()
+	}
+	return area
+}
+
+func TestStrokePolyline(t *testing.T) {
+	line := []Vec2f{{0, 0}, {4, 0}}
+
+	mesh := StrokePolyline(line, StrokeOptions{Width: 2, Distances: true})
+	assert.Equal(t, []Vec2f{{0, 1}, {0, -1}, {4, 1}, {4, -1}}, mesh.Vertices)
+	assert.Equal(t, []float32{0, 0, 4, 4}, mesh.Distances)
+	assert.Len(t, mesh.Indices, 2*3)
+	AssertFloat(t, 8, strokeArea(t, mesh))
+
+	mesh = StrokePolyline(line, StrokeOptions{Width: 2, Cap: CapSquare, Distances: true})
+	assert.Equal(t, []Vec2f{{-1, 1}, {-1, -1}, {5, 1}, {5, -1}}, mesh.Vertices)
+	assert.Equal(t, []float32{-1, -1, 5, 5}, mesh.Distances)
+	AssertFloat(t, 12, strokeArea(t, mesh))
+
+	mesh = StrokePolyline(line, StrokeOptions{Width: 2, Cap: CapRound, ArcTolerance: 0.001, Distances: true})
+	assert.InDelta(t, 8+math.Pi, strokeArea(t, mesh), 0.01)
+	assert.Len(t, mesh.Distances, len(mesh.Vertices))
+	for i, v := range mesh.Vertices {
+		AssertFloat(t, v[0], mesh.Distances[i])
+	}
+
+	// no distances
+	mesh = StrokePolyline(line, StrokeOptions{Width: 2})
+	assert.Nil(t, mesh.Distances)
+
+	// degenerate
+	assert.Empty(t, StrokePolyline(nil, StrokeOptions{Width: 2}).Vertices)
+	assert.Empty(t, StrokePolyline(line, StrokeOptions{}).Vertices)
+	assert.Empty(t, StrokePolyline([]Vec2f{{1, 1}}, StrokeOptions{Width: 2}).Vertices)
+	AssertFloat(t, 4, strokeArea(t, StrokePolyline([]Vec2f{{1, 1}, {1, 1}}, StrokeOptions{Width: 2, Cap: CapSquare})))
+	assert.InDelta(t, math.Pi, strokeArea(t, StrokePolyline([]Vec2f{{1, 1}}, StrokeOptions{Width: 2, Cap: CapRound, ArcTolerance: 0.001})), 0.01)
+}
+
+func TestStrokePolyline_Joins(t *testing.T) {
+	line := []Vec2f{{0, 0}, {4, 0}, {4, 4}}
+	// the segments are cut at the inner corner, so triangles don't overlap
+	for join, area := range map[JoinType]float32{
+		JoinMiter:  16,
+		JoinBevel:  15.5,
+		JoinSquare: 16 - (math.Sqrt2-1)*(math.Sqrt2-1),
+		JoinRound:  15 + math.Pi/4,
+	} {
+		mesh := StrokePolyline(line, StrokeOptions{Width: 2, Join: join, ArcTolerance: 0.001})
+		assert.Contains(t, mesh.Vertices, Vec2f{3, 1})
+		assert.InDelta(t, area, strokeArea(t, mesh), 0.01, join.String())
+		assert.InDelta(t, area, strokeTriangleArea(mesh), 0.01, join.String())
+	}
+
+	// zig-zag with acute angles
+	zigzag := []Vec2f{{0, 0}, {4, 0}, {1, 1}, {5, 2}, {2, 3}}
+	mesh := StrokePolyline(zigzag, StrokeOptions{Width: 0.5, Join: JoinRound, ArcTolerance: 0.001})
+	AssertFloat(t, strokeArea(t, mesh), strokeTriangleArea(mesh))
+
+	// the miter limit squares off the corner, like in OffsetPolygons
+	AssertFloat(t, 16-(math.Sqrt2-1)*(math.Sqrt2-1), strokeArea(t, StrokePolyline(line, StrokeOptions{Width: 2, Join: JoinMiter, MiterLimit: 1.2})))
+	// the default limit of 2 squares off corners below 60°
+	sharp := []Vec2f{{0, 0}, {4, 0}, {0, 1}}
+	miter := StrokePolyline(sharp, StrokeOptions{Width: 2, Join: JoinMiter})
+	square := StrokePolyline(sharp, StrokeOptions{Width: 2, Join: JoinSquare})
+	assert.Equal(t, square.Vertices, miter.Vertices)
+
+	// right turn
+	mirrored := []Vec2f{{0, 0}, {4, 0}, {4, -4}}
+	mesh = StrokePolyline(mirrored, StrokeOptions{Width: 2, Join: JoinMiter})
+	AssertFloat(t, 16, strokeArea(t, mesh))
+	AssertFloat(t, 16, strokeTriangleArea(mesh))
+
+	// reversing lines
+	back := []Vec2f{{0, 0}, {4, 0}, {2, 0}}
+	AssertFloat(t, 8, strokeArea(t, StrokePolyline(back, StrokeOptions{Width: 2, Join: JoinBevel})))
+	AssertFloat(t, 10, strokeArea(t, StrokePolyline(back, StrokeOptions{Width: 2, Join: JoinMiter}))) // squared off
+	AssertFloat(t, 10, strokeArea(t, StrokePolyline(back, StrokeOptions{Width: 2, Join: JoinSquare})))
+	assert.InDelta(t, 8+math.Pi/2, strokeArea(t, StrokePolyline(back, StrokeOptions{Width: 2, Join: JoinRound, ArcTolerance: 0.001})), 0.01)
+}
+
+func TestStrokePolyline_Closed(t *testing.T) {
+	square := []Vec2f{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}
+	mesh := StrokePolyline(square, StrokeOptions{Width: 2, Closed: true, Cap: CapRound, Distances: true})
+	AssertFloat(t, 36-4, strokeArea(t, mesh))
+	AssertFloat(t, 36-4, strokeTriangleArea(mesh))
+	assert.Contains(t, mesh.Distances, float32(16))
+
+	// the closing join is split between the last segment's end and the first segment's start
+	var cornerDists []float32
+	for i, v := range mesh.Vertices {
+		if v == (Vec2f{-1, -1}) {
+			cornerDists = append(cornerDists, mesh.Distances[i])
+		}
+	}
+	assert.ElementsMatch(t, []float32{16, 0}, cornerDists)
+	for i := 0; i < len(mesh.Indices); i += 3 {
+		a, b, c := mesh.Distances[mesh.Indices[i]], mesh.Distances[mesh.Indices[i+1]], mesh.Distances[mesh.Indices[i+2]]
+		assert.True(t, math32.Max(a, math32.Max(b, c))-math32.Min(a, math32.Min(b, c)) <= 4, "triangle %d spans the seam", i/3)
+	}
+
+	// open
+	mesh = StrokePolyline(square, StrokeOptions{Width: 2})
+	AssertFloat(t, 36-4-1, strokeArea(t, mesh))
+}
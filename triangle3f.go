@@ -0,0 +1,322 @@
+package vmath
+
+import (
+	"fmt"
+
+	"github.com/maja42/vmath/math32"
+)
+
+// Triangle3f represents a triangle in 3D space.
+type Triangle3f struct {
+	A, B, C Vec3f
+}
+
+func (t Triangle3f) String() string {
+	return fmt.Sprintf("Triangle3f([%f x %f x %f], [%f x %f x %f], [%f x %f x %f])",
+		t.A[0], t.A[1], t.A[2],
+		t.B[0], t.B[1], t.B[2],
+		t.C[0], t.C[1], t.C[2])
+}
+
+// Normal returns the triangle's normalized face normal.
+// The normal is facing towards the side from which the vertices appear in counter-clockwise order.
+// If the triangle is degenerate, a zero vector is returned.
+func (t Triangle3f) Normal() Vec3f {
+	return t.B.Sub(t.A).Cross(t.C.Sub(t.A)).Normalize()
+}
+
+// Plane returns the plane the triangle lies on.
+func (t Triangle3f) Plane() Plane {
+	return PlaneFromPoints(t.A, t.B, t.C)
+}
+
+// Area returns the triangle's area.
+func (t Triangle3f) Area() float32 {
+	return t.B.Sub(t.A).Cross(t.C.Sub(t.A)).Length() / 2
+}
+
+// Centroid returns the triangle's center of mass.
+func (t Triangle3f) Centroid() Vec3f {
+	return t.A.Add(t.B).Add(t.C).DivScalar(3)
+}
+
+// Box3f returns the smallest axis-aligned box that contains the triangle.
+func (t Triangle3f) Box3f() Box3f {
+	return Box3fFromPoints([]Vec3f{t.A, t.B, t.C})
+}
+
+// Barycentric returns the barycentric coordinates (u, v, w) of a point with respect to the triangle,
+// so that point = u*A + v*B + w*C.
+// If the point does not lie on the triangle's plane, the coordinates of its orthogonal projection are returned.
+// The triangle must not be degenerate.
+func (t Triangle3f) Barycentric(point Vec3f) Vec3f {
+	// Source: "Real-Time Collision Detection" by Christer Ericson, chapter 3.4
+	v0 := t.B.Sub(t.A)
+	v1 := t.C.Sub(t.A)
+	v2 := point.Sub(t.A)
+	d00 := v0.Dot(v0)
+	d01 := v0.Dot(v1)
+	d11 := v1.Dot(v1)
+	d20 := v2.Dot(v0)
+	d21 := v2.Dot(v1)
+	denom := d00*d11 - d01*d01
+
+	v := (d11*d20 - d01*d21) / denom
+	w := (d00*d21 - d01*d20) / denom
+	return Vec3f{1 - v - w, v, w}
+}
+
+// FromBarycentric returns the point u*A + v*B + w*C for the barycentric coordinates (u, v, w).
+func (t Triangle3f) FromBarycentric(bary Vec3f) Vec3f {
+	return t.A.MulScalar(bary[0]).
+		Add(t.B.MulScalar(bary[1])).
+		Add(t.C.MulScalar(bary[2]))
+}
+
+// ContainsPoint checks if a point that lies on the triangle's plane is within the triangle.
+// Points on the triangle's edges are also considered to be contained.
+// The point's distance to the triangle's plane is not considered.
+func (t Triangle3f) ContainsPoint(point Vec3f) bool {
+	bary := t.Barycentric(point)
+	return bary[0] >= -Epsilon && bary[1] >= -Epsilon && bary[2] >= -Epsilon
+}
+
+// ClosestPoint returns the point on the triangle that is closest to the given point.
+func (t Triangle3f) ClosestPoint(point Vec3f) Vec3f {
+	// Source: "Real-Time Collision Detection" by Christer Ericson, chapter 5.1.5
+	// Determines the voronoi region of the point and projects it onto the corresponding feature.
+	a, b, c := t.A, t.B, t.C
+	ab := b.Sub(a)
+	ac := c.Sub(a)
+
+	// vertex region outside A
+	ap := point.Sub(a)
+	d1 := ab.Dot(ap)
+	d2 := ac.Dot(ap)
+	if d1 <= 0 && d2 <= 0 {
+		return a
+	}
+	// vertex region outside B
+	bp := point.Sub(b)
+	d3 := ab.Dot(bp)
+	d4 := ac.Dot(bp)
+	if d3 >= 0 && d4 <= d3 {
+		return b
+	}
+	// edge region of AB
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		v := d1 / (d1 - d3)
+		return a.Add(ab.MulScalar(v))
+	}
+	// vertex region outside C
+	cp := point.Sub(c)
+	d5 := ab.Dot(cp)
+	d6 := ac.Dot(cp)
+	if d6 >= 0 && d5 <= d6 {
+		return c
+	}
+	// edge region of AC
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		w := d2 / (d2 - d6)
+		return a.Add(ac.MulScalar(w))
+	}
+	// edge region of BC
+	va := d3*d6 - d5*d4
+	if va <= 0 && (d4-d3) >= 0 && (d5-d6) >= 0 {
+		w := (d4 - d3) / ((d4 - d3) + (d5 - d6))
+		return b.Add(c.Sub(b).MulScalar(w))
+	}
+	// face region
+	denom := 1 / (va + vb + vc)
+	v := vb * denom
+	w := vc * denom
+	return a.Add(ab.MulScalar(v)).Add(ac.MulScalar(w))
+}
+
+// SquarePointDistance returns the squared distance between the triangle and a point.
+func (t Triangle3f) SquarePointDistance(point Vec3f) float32 {
+	return t.ClosestPoint(point).SquareDistance(point)
+}
+
+// PointDistance returns the distance between the triangle and a point.
+func (t Triangle3f) PointDistance(point Vec3f) float32 {
+	return math32.Sqrt(t.SquarePointDistance(point))
+}
+
+// IntersectRay calculates the intersection of a ray with the triangle.
+// See Ray3f.IntersectTriangle for details.
+func (t Triangle3f) IntersectRay(ray Ray3f) (RayHit, bool) {
+	return ray.IntersectTriangle(t.A, t.B, t.C)
+}
+
+// IntersectsBox3f checks if the triangle intersects an axis-aligned box, using the separating axis theorem.
+// Touching shapes are also considered to intersect.
+func (t Triangle3f) IntersectsBox3f(box Box3f) bool {
+	// Source: "Fast 3D Triangle-Box Overlap Testing" by Tomas Akenine-Möller, 2001.
+	// translate everything so that the box is centered at the origin
+	center := box.Center()
+	h := box.HalfExtents()
+	v := [3]Vec3f{t.A.Sub(center), t.B.Sub(center), t.C.Sub(center)}
+	e := [3]Vec3f{v[1].Sub(v[0]), v[2].Sub(v[1]), v[0].Sub(v[2])}
+
+	separated := func(axis Vec3f) bool {
+		p0, p1, p2 := v[0].Dot(axis), v[1].Dot(axis), v[2].Dot(axis)
+		r := h[0]*math32.Abs(axis[0]) + h[1]*math32.Abs(axis[1]) + h[2]*math32.Abs(axis[2])
+		return math32.Min(p0, math32.Min(p1, p2)) > r || math32.Max(p0, math32.Max(p1, p2)) < -r
+	}
+
+	// the box's face normals
+	for i := 0; i < 3; i++ {
+		var axis Vec3f
+		axis[i] = 1
+		if separated(axis) {
+			return false
+		}
+	}
+	// the triangle's face normal
+	if separated(e[0].Cross(e[1])) {
+		return false
+	}
+	// cross products of the box's and triangle's edges
+	for i := 0; i < 3; i++ {
+		var boxEdge Vec3f
+		boxEdge[i] = 1
+		for _, edge := range e {
+			if separated(boxEdge.Cross(edge)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IntersectsTriangle checks if this triangle intersects another triangle.
+// Touching triangles are also considered to intersect.
+func (t Triangle3f) IntersectsTriangle(other Triangle3f) bool {
+	// Two non-coplanar triangles intersect if and only if at least one edge of one triangle
+	// passes through the other triangle.
+	if !t.Box3f().Intersects(other.Box3f()) {
+		return false
+	}
+
+	n1 := t.B.Sub(t.A).Cross(t.C.Sub(t.A))
+	n2 := other.B.Sub(other.A).Cross(other.C.Sub(other.A))
+	dist1 := [3]float32{ // distances of the other triangle's vertices to this triangle's plane
+		n1.Dot(other.A.Sub(t.A)),
+		n1.Dot(other.B.Sub(t.A)),
+		n1.Dot(other.C.Sub(t.A)),
+	}
+	dist2 := [3]float32{ // distances of this triangle's vertices to the other triangle's plane
+		n2.Dot(t.A.Sub(other.A)),
+		n2.Dot(t.B.Sub(other.A)),
+		n2.Dot(t.C.Sub(other.A)),
+	}
+	scale := n1.Length() * (other.Box3f().Size().Length() + t.Box3f().Size().Length())
+	if isZero(dist1, scale) {
+		return t.intersectsCoplanarTriangle(other, n1)
+	}
+	if sameSign(dist1) || sameSign(dist2) {
+		return false
+	}
+	return t.edgesIntersect(other, dist2) || other.edgesIntersect(t, dist1)
+}
+
+// edgesIntersect checks if one of the triangle's edges passes through the other triangle.
+// dist contains the (scaled) signed distances of the triangle's vertices to the other triangle's plane.
+func (t Triangle3f) edgesIntersect(other Triangle3f, dist [3]float32) bool {
+	vertices := [3]Vec3f{t.A, t.B, t.C}
+	for i := 0; i < 3; i++ {
+		j := (i + 1) % 3
+		di, dj := dist[i], dist[j]
+		if (di > 0 && dj > 0) || (di < 0 && dj < 0) || di == dj {
+			continue // edge does not cross the plane, or lies within it
+		}
+		p := vertices[i].Lerp(vertices[j], di/(di-dj))
+		if other.ContainsPoint(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// intersectsCoplanarTriangle checks if two triangles on the same plane intersect.
+func (t Triangle3f) intersectsCoplanarTriangle(other Triangle3f, normal Vec3f) bool {
+	// project both triangles onto the axis-aligned plane where their area is maximized
+	n := normal.Abs()
+	x, y := 1, 2
+	if n[1] > n[0] && n[1] >= n[2] {
+		x, y = 0, 2
+	} else if n[2] > n[0] && n[2] > n[1] {
+		x, y = 0, 1
+	}
+	project := func(tri Triangle3f) [3]Vec2f {
+		return [3]Vec2f{
+			{tri.A[x], tri.A[y]},
+			{tri.B[x], tri.B[y]},
+			{tri.C[x], tri.C[y]},
+		}
+	}
+	a := project(t)
+	b := project(other)
+
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if segmentsIntersect2D(a[i], a[(i+1)%3], b[j], b[(j+1)%3]) {
+				return true
+			}
+		}
+	}
+	// one triangle might be completely within the other
+	return pointInTriangle2D(a, b[0]) || pointInTriangle2D(b, a[0])
+}
+
+// sameSign returns true if all values are either positive or negative.
+func sameSign(v [3]float32) bool {
+	return (v[0] > 0 && v[1] > 0 && v[2] > 0) || (v[0] < 0 && v[1] < 0 && v[2] < 0)
+}
+
+// isZero returns true if all values are zero, relative to the given scale.
+func isZero(v [3]float32, scale float32) bool {
+	eps := scale * Epsilon
+	return math32.Abs(v[0]) <= eps && math32.Abs(v[1]) <= eps && math32.Abs(v[2]) <= eps
+}
+
+// segmentsIntersect2D checks if the line segments a-b and c-d intersect or touch.
+func segmentsIntersect2D(a, b, c, d Vec2f) bool {
+	// Source: "Introduction to Algorithms" by Cormen et al., chapter 33.1
+	d1 := orientation2D(c, d, a)
+	d2 := orientation2D(c, d, b)
+	d3 := orientation2D(a, b, c)
+	d4 := orientation2D(a, b, d)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) &&
+		((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	onSegment := func(a, b, p Vec2f) bool {
+		return math32.Min(a[0], b[0]) <= p[0] && p[0] <= math32.Max(a[0], b[0]) &&
+			math32.Min(a[1], b[1]) <= p[1] && p[1] <= math32.Max(a[1], b[1])
+	}
+	return (d1 == 0 && onSegment(c, d, a)) ||
+		(d2 == 0 && onSegment(c, d, b)) ||
+		(d3 == 0 && onSegment(a, b, c)) ||
+		(d4 == 0 && onSegment(a, b, d))
+}
+
+// pointInTriangle2D checks if a point is within a 2D triangle (inclusive edges), regardless of winding order.
+func pointInTriangle2D(tri [3]Vec2f, p Vec2f) bool {
+	d1 := orientation2D(tri[0], tri[1], p)
+	d2 := orientation2D(tri[1], tri[2], p)
+	d3 := orientation2D(tri[2], tri[0], p)
+	hasNeg := d1 < 0 || d2 < 0 || d3 < 0
+	hasPos := d1 > 0 || d2 > 0 || d3 > 0
+	return !(hasNeg && hasPos)
+}
+
+// orientation2D returns the z-component of the cross product (b-a) x (p-a).
+// The result is positive if p lies to the left of the line a->b, negative if it lies to the right,
+// and zero if all points are collinear.
+func orientation2D(a, b, p Vec2f) float32 {
+	return (b[0]-a[0])*(p[1]-a[1]) - (b[1]-a[1])*(p[0]-a[0])
+}
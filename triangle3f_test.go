@@ -0,0 +1,113 @@
+package vmath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriangle3f_String(t *testing.T) {
+	tri := Triangle3f{Vec3f{1, 2, 3}, Vec3f{4, 5, 6}, Vec3f{7, 8, 9}}
+	assert.Equal(t, "Triangle3f([1.000000 x 2.000000 x 3.000000], [4.000000 x 5.000000 x 6.000000], [7.000000 x 8.000000 x 9.000000])", tri.String())
+}
+
+func TestTriangle3f_Properties(t *testing.T) {
+	tri := Triangle3f{Vec3f{0, 0, 2}, Vec3f{4, 0, 2}, Vec3f{0, 3, 2}}
+	AssertVec3f(t, Vec3f{0, 0, 1}, tri.Normal())
+	AssertFloat(t, 6, tri.Area())
+	AssertVec3f(t, Vec3f{4.0 / 3.0, 1, 2}, tri.Centroid())
+	assert.Equal(t, Box3f{Vec3f{0, 0, 2}, Vec3f{4, 3, 2}}, tri.Box3f())
+
+	p := tri.Plane()
+	AssertVec3f(t, Vec3f{0, 0, 1}, p.Normal)
+	AssertFloat(t, -2, p.D)
+
+	// clockwise
+	AssertVec3f(t, Vec3f{0, 0, -1}, Triangle3f{tri.A, tri.C, tri.B}.Normal())
+	// degenerate
+	AssertVec3f(t, Vec3f{}, Triangle3f{Vec3f{0, 0, 0}, Vec3f{1, 1, 1}, Vec3f{2, 2, 2}}.Normal())
+}
+
+func TestTriangle3f_Barycentric(t *testing.T) {
+	tri := Triangle3f{Vec3f{0, 0, 2}, Vec3f{4, 0, 2}, Vec3f{0, 4, 2}}
+
+	AssertVec3f(t, Vec3f{1, 0, 0}, tri.Barycentric(tri.A))
+	AssertVec3f(t, Vec3f{0, 1, 0}, tri.Barycentric(tri.B))
+	AssertVec3f(t, Vec3f{0, 0, 1}, tri.Barycentric(tri.C))
+	AssertVec3f(t, Vec3f{0.5, 0.25, 0.25}, tri.Barycentric(Vec3f{1, 1, 2}))
+	AssertVec3f(t, Vec3f{0.5, 0.25, 0.25}, tri.Barycentric(Vec3f{1, 1, 7})) // projected
+	AssertVec3f(t, Vec3f{-1, 1, 1}, tri.Barycentric(Vec3f{4, 4, 2}))
+
+	for _, p := range []Vec3f{{1, 1, 2}, {3, 0.5, 2}, {-2, 5, 2}} {
+		AssertVec3f(t, p, tri.FromBarycentric(tri.Barycentric(p)))
+	}
+
+	assert.True(t, tri.ContainsPoint(Vec3f{1, 1, 2}))
+	assert.True(t, tri.ContainsPoint(Vec3f{2, 2, 2}))
+	assert.False(t, tri.ContainsPoint(Vec3f{2.1, 2, 2}))
+}
+
+func TestTriangle3f_ClosestPoint(t *testing.T) {
+	tri := Triangle3f{Vec3f{0, 0, 0}, Vec3f{4, 0, 0}, Vec3f{0, 4, 0}}
+
+	AssertVec3f(t, Vec3f{1, 1, 0}, tri.ClosestPoint(Vec3f{1, 1, 5}))   // face
+	AssertVec3f(t, Vec3f{0, 0, 0}, tri.ClosestPoint(Vec3f{-1, -1, 1})) // vertex A
+	AssertVec3f(t, Vec3f{4, 0, 0}, tri.ClosestPoint(Vec3f{6, -1, 0}))  // vertex B
+	AssertVec3f(t, Vec3f{0, 4, 0}, tri.ClosestPoint(Vec3f{-1, 6, 0}))  // vertex C
+	AssertVec3f(t, Vec3f{2, 0, 0}, tri.ClosestPoint(Vec3f{2, -3, 1}))  // edge AB
+	AssertVec3f(t, Vec3f{0, 2, 0}, tri.ClosestPoint(Vec3f{-3, 2, 1}))  // edge AC
+	AssertVec3f(t, Vec3f{2, 2, 0}, tri.ClosestPoint(Vec3f{3, 3, 0}))   // edge BC
+
+	AssertFloat(t, 25, tri.SquarePointDistance(Vec3f{1, 1, 5}))
+	AssertFloat(t, 5, tri.PointDistance(Vec3f{2, -3, 4}))
+}
+
+func TestTriangle3f_IntersectRay(t *testing.T) {
+	tri := Triangle3f{Vec3f{0, 0, 0}, Vec3f{4, 0, 0}, Vec3f{0, 4, 0}}
+	hit, ok := tri.IntersectRay(Ray3f{Vec3f{1, 1, 5}, Vec3f{0, 0, -1}})
+	assert.True(t, ok)
+	AssertFloat(t, 5, hit.Distance)
+	AssertVec3f(t, Vec3f{1, 1, 0}, hit.Point)
+
+	_, ok = tri.IntersectRay(Ray3f{Vec3f{3, 3, 5}, Vec3f{0, 0, -1}})
+	assert.False(t, ok)
+}
+
+func TestTriangle3f_IntersectsBox3f(t *testing.T) {
+	box := Box3f{Vec3f{0, 0, 0}, Vec3f{2, 2, 2}}
+
+	// vertex inside
+	assert.True(t, Triangle3f{Vec3f{1, 1, 1}, Vec3f{5, 5, 5}, Vec3f{5, 0, 5}}.IntersectsBox3f(box))
+	// large triangle cutting through the box, all vertices outside
+	assert.True(t, Triangle3f{Vec3f{-10, -10, 1}, Vec3f{10, -10, 1}, Vec3f{0, 10, 1}}.IntersectsBox3f(box))
+	// touching a face
+	assert.True(t, Triangle3f{Vec3f{-10, -10, 2}, Vec3f{10, -10, 2}, Vec3f{0, 10, 2}}.IntersectsBox3f(box))
+	// above the box
+	assert.False(t, Triangle3f{Vec3f{-10, -10, 3}, Vec3f{10, -10, 3}, Vec3f{0, 10, 3}}.IntersectsBox3f(box))
+	// tilted plane passing next to a corner (separated by the triangle's normal)
+	assert.False(t, Triangle3f{Vec3f{7, 0, 0}, Vec3f{0, 7, 0}, Vec3f{0, 0, 7}}.IntersectsBox3f(box))
+	assert.True(t, Triangle3f{Vec3f{5, 0, 0}, Vec3f{0, 5, 0}, Vec3f{0, 0, 5}}.IntersectsBox3f(box))
+	// thin triangle passing diagonally next to an edge
+	assert.False(t, Triangle3f{Vec3f{3, 0, -1}, Vec3f{0, 3, 11}, Vec3f{0, 3.1, 11}}.IntersectsBox3f(box))
+}
+
+func TestTriangle3f_IntersectsTriangle(t *testing.T) {
+	tri := Triangle3f{Vec3f{0, 0, 0}, Vec3f{4, 0, 0}, Vec3f{0, 4, 0}}
+
+	// piercing
+	assert.True(t, tri.IntersectsTriangle(Triangle3f{Vec3f{1, 1, -1}, Vec3f{1, 1, 1}, Vec3f{5, 5, 1}}))
+	// other triangle's edge passing through the first triangle
+	assert.True(t, tri.IntersectsTriangle(Triangle3f{Vec3f{1, -1, -1}, Vec3f{1, 5, -1}, Vec3f{1, 2, 5}}))
+	// touching with a vertex
+	assert.True(t, tri.IntersectsTriangle(Triangle3f{Vec3f{1, 1, 0}, Vec3f{1, 1, 3}, Vec3f{3, 1, 3}}))
+	// above
+	assert.False(t, tri.IntersectsTriangle(Triangle3f{Vec3f{1, 1, 1}, Vec3f{1, 1, 3}, Vec3f{3, 1, 3}}))
+	// crossing the plane, but next to the triangle
+	assert.False(t, tri.IntersectsTriangle(Triangle3f{Vec3f{3, 3, -1}, Vec3f{3, 3, 1}, Vec3f{5, 5, 1}}))
+
+	// coplanar
+	assert.True(t, tri.IntersectsTriangle(Triangle3f{Vec3f{1, 1, 0}, Vec3f{5, 1, 0}, Vec3f{1, 5, 0}}))     // overlapping
+	assert.True(t, tri.IntersectsTriangle(Triangle3f{Vec3f{1, 1, 0}, Vec3f{1.5, 1, 0}, Vec3f{1, 1.5, 0}})) // contained
+	assert.True(t, Triangle3f{Vec3f{1, 1, 0}, Vec3f{1.5, 1, 0}, Vec3f{1, 1.5, 0}}.IntersectsTriangle(tri)) // containing
+	assert.False(t, tri.IntersectsTriangle(Triangle3f{Vec3f{3, 3, 0}, Vec3f{5, 3, 0}, Vec3f{3, 5, 0}}))    // disjoint
+}
@@ -0,0 +1,480 @@
+package vmath
+
+import (
+	"math"
+	"sort"
+)
+
+// Triangulate splits the polygon into triangles, using ear clipping.
+// See TriangulatePolygon for details.
+func (p Polygon2f) Triangulate() []uint32 {
+	return TriangulatePolygon(p)
+}
+
+// TriangulatePolygon splits a polygon with optional holes into triangles, using ear clipping.
+// Returns the vertex indices of all triangles, with three indices per triangle in counter-clockwise order.
+// Indices refer to the vertices of the outer ring followed by the vertices of all holes,
+// in the order in which they are passed. The result can directly be used as an OpenGL element buffer.
+//
+// The rings can be in any winding order. Holes must lie within the outer ring.
+// Duplicate, collinear and otherwise degenerate vertices are handled,
+// and self-intersecting rings are triangulated on a best-effort basis.
+// The runtime is O(n²) in the worst case.
+func TriangulatePolygon(outer Polygon2f, holes ...Polygon2f) []uint32 {
+	// Source: "earcut" by Mapbox (ISC license), https://github.com/mapbox/earcut
+	// Based on "Triangulation by Ear Clipping" by David Eberly, with additional steps
+	// for curing local self-intersections and splitting the polygon if no ears can be found.
+	outerNode := earcutLinkedList(outer, 0, true)
+	if outerNode == nil || outerNode.next == outerNode.prev {
+		return nil
+	}
+
+	vertexCount := len(outer)
+	for _, hole := range holes {
+		vertexCount += len(hole)
+	}
+	ec := earcut{
+		triangles: make([]uint32, 0, (vertexCount-2)*3),
+	}
+
+	if len(holes) > 0 {
+		outerNode = ec.eliminateHoles(holes, len(outer), outerNode)
+	}
+	ec.earcutLinked(outerNode, 0)
+	return ec.triangles
+}
+
+// earcutNode is a vertex within a circular doubly linked list of polygon vertices.
+type earcutNode struct {
+	i          uint32  // vertex index
+	x, y       float64 // vertex coordinates
+	prev, next *earcutNode
+	steiner    bool // indicates a steiner point (a hole that consists of a single vertex)
+}
+
+type earcut struct {
+	triangles []uint32
+}
+
+// earcutLinkedList creates a circular doubly linked list from the ring's vertices, in the specified winding order.
+// offset is the index of the ring's first vertex.
+func earcutLinkedList(ring Polygon2f, offset int, clockwise bool) *earcutNode {
+	var last *earcutNode
+	if clockwise == (ring.SignedArea() > 0) {
+		for i, v := range ring {
+			last = earcutInsertNode(uint32(offset+i), v, last)
+		}
+	} else {
+		for i := len(ring) - 1; i >= 0; i-- {
+			last = earcutInsertNode(uint32(offset+i), ring[i], last)
+		}
+	}
+	if last != nil && earcutEquals(last, last.next) {
+		earcutRemoveNode(last)
+		last = last.next
+	}
+	return last
+}
+
+// earcutLinked is the main ear slicing loop, which triangulates a polygon given as a linked list.
+func (ec *earcut) earcutLinked(ear *earcutNode, pass int) {
+	if ear == nil {
+		return
+	}
+	stop := ear
+	for ear.prev != ear.next {
+		prev := ear.prev
+		next := ear.next
+
+		if earcutIsEar(ear) {
+			// cut off the triangle
+			ec.triangles = append(ec.triangles, prev.i, ear.i, next.i)
+			earcutRemoveNode(ear)
+
+			// skipping the next vertex leads to less sliver triangles
+			ear = next.next
+			stop = next.next
+			continue
+		}
+		ear = next
+
+		// if we looped through the whole remaining polygon and can't find any more ears
+		if ear == stop {
+			switch pass {
+			case 0: // try filtering points and slicing again
+				ec.earcutLinked(earcutFilterPoints(ear, nil), 1)
+			case 1: // if this didn't work, try curing all small self-intersections locally
+				ear = ec.cureLocalIntersections(earcutFilterPoints(ear, nil))
+				ec.earcutLinked(ear, 2)
+			case 2: // as a last resort, try splitting the remaining polygon into two
+				ec.splitEarcut(ear)
+			}
+			break
+		}
+	}
+}
+
+// earcutIsEar checks whether a polygon node forms a valid ear with adjacent nodes.
+func earcutIsEar(ear *earcutNode) bool {
+	a, b, c := ear.prev, ear, ear.next
+	if earcutArea(a, b, c) >= 0 {
+		return false // reflex, can't be an ear
+	}
+	// now make sure we don't have other points inside the potential ear
+	for p := ear.next.next; p != ear.prev; p = p.next {
+		if earcutPointInTriangle(a.x, a.y, b.x, b.y, c.x, c.y, p.x, p.y) &&
+			earcutArea(p.prev, p, p.next) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// cureLocalIntersections goes through all polygon nodes and cures small local self-intersections.
+func (ec *earcut) cureLocalIntersections(start *earcutNode) *earcutNode {
+	p := start
+	for {
+		a := p.prev
+		b := p.next.next
+
+		if !earcutEquals(a, b) && earcutIntersects(a, p, p.next, b) &&
+			earcutLocallyInside(a, b) && earcutLocallyInside(b, a) {
+			ec.triangles = append(ec.triangles, a.i, p.i, b.i)
+
+			// remove two nodes involved
+			earcutRemoveNode(p)
+			earcutRemoveNode(p.next)
+			p = b
+			start = b
+		}
+		p = p.next
+		if p == start {
+			break
+		}
+	}
+	return earcutFilterPoints(p, nil)
+}
+
+// splitEarcut tries to split the polygon into two and triangulate them independently.
+func (ec *earcut) splitEarcut(start *earcutNode) {
+	// look for a valid diagonal that divides the polygon into two
+	a := start
+	for {
+		for b := a.next.next; b != a.prev; b = b.next {
+			if a.i != b.i && earcutIsValidDiagonal(a, b) {
+				// split the polygon in two by the diagonal
+				c := earcutSplitPolygon(a, b)
+
+				// filter collinear points around the cuts
+				a = earcutFilterPoints(a, a.next)
+				c = earcutFilterPoints(c, c.next)
+
+				// run earcut on each half
+				ec.earcutLinked(a, 0)
+				ec.earcutLinked(c, 0)
+				return
+			}
+		}
+		a = a.next
+		if a == start {
+			return
+		}
+	}
+}
+
+// eliminateHoles links every hole into the outer loop, producing a single-ring polygon without holes.
+// offset is the index of the first hole's first vertex.
+func (ec *earcut) eliminateHoles(holes []Polygon2f, offset int, outerNode *earcutNode) *earcutNode {
+	queue := make([]*earcutNode, 0, len(holes))
+	for _, hole := range holes {
+		list := earcutLinkedList(hole, offset, false)
+		offset += len(hole)
+		if list == nil {
+			continue
+		}
+		if list == list.next {
+			list.steiner = true
+		}
+		queue = append(queue, earcutLeftmost(list))
+	}
+	sort.SliceStable(queue, func(i, j int) bool {
+		return queue[i].x < queue[j].x
+	})
+
+	// process holes from left to right
+	for _, hole := range queue {
+		outerNode = earcutEliminateHole(hole, outerNode)
+	}
+	return outerNode
+}
+
+// earcutEliminateHole finds a bridge between the hole and the outer ring, and links them.
+func earcutEliminateHole(hole, outerNode *earcutNode) *earcutNode {
+	bridge := earcutFindHoleBridge(hole, outerNode)
+	if bridge == nil {
+		return outerNode
+	}
+	bridgeReverse := earcutSplitPolygon(bridge, hole)
+
+	// filter collinear points around the cuts
+	earcutFilterPoints(bridgeReverse, bridgeReverse.next)
+	return earcutFilterPoints(bridge, bridge.next)
+}
+
+// earcutFindHoleBridge uses David Eberly's algorithm for finding a bridge between the hole and the outer polygon.
+func earcutFindHoleBridge(hole, outerNode *earcutNode) *earcutNode {
+	p := outerNode
+	hx := hole.x
+	hy := hole.y
+	qx := math.Inf(-1)
+	var m *earcutNode
+
+	// find a segment intersected by a ray from the hole's leftmost point to the left;
+	// segment's endpoint with lesser x will be the potential connection point
+	for {
+		if hy <= p.y && hy >= p.next.y && p.next.y != p.y {
+			x := p.x + (hy-p.y)*(p.next.x-p.x)/(p.next.y-p.y)
+			if x <= hx && x > qx {
+				qx = x
+				m = p
+				if p.next.x < p.x {
+					m = p.next
+				}
+				if x == hx {
+					return m // hole touches outer segment; pick leftmost endpoint
+				}
+			}
+		}
+		p = p.next
+		if p == outerNode {
+			break
+		}
+	}
+	if m == nil {
+		return nil
+	}
+
+	// look for points inside the triangle of hole point, segment intersection and endpoint;
+	// if there are no points found, we have a valid connection;
+	// otherwise choose the point of the minimum angle with the ray as connection point
+	stop := m
+	mx := m.x
+	my := m.y
+	tanMin := math.Inf(1)
+
+	p = m
+	for {
+		ax, cx := qx, hx
+		if hy < my {
+			ax, cx = hx, qx
+		}
+		if hx >= p.x && p.x >= mx && hx != p.x &&
+			earcutPointInTriangle(ax, hy, mx, my, cx, hy, p.x, p.y) {
+
+			tan := math.Abs(hy-p.y) / (hx - p.x) // tangential
+			if earcutLocallyInside(p, hole) &&
+				(tan < tanMin || (tan == tanMin && (p.x > m.x || (p.x == m.x && earcutSectorContainsSector(m, p))))) {
+				m = p
+				tanMin = tan
+			}
+		}
+		p = p.next
+		if p == stop {
+			break
+		}
+	}
+	return m
+}
+
+// earcutSectorContainsSector checks whether sector in vertex m contains sector in vertex p in the same coordinates.
+func earcutSectorContainsSector(m, p *earcutNode) bool {
+	return earcutArea(m.prev, m, p.prev) < 0 && earcutArea(p.next, m, m.next) < 0
+}
+
+// earcutLeftmost finds the leftmost node of a polygon ring.
+func earcutLeftmost(start *earcutNode) *earcutNode {
+	p := start
+	leftmost := start
+	for {
+		if p.x < leftmost.x || (p.x == leftmost.x && p.y < leftmost.y) {
+			leftmost = p
+		}
+		p = p.next
+		if p == start {
+			return leftmost
+		}
+	}
+}
+
+// earcutFilterPoints eliminates duplicate and collinear points.
+func earcutFilterPoints(start, end *earcutNode) *earcutNode {
+	if start == nil {
+		return start
+	}
+	if end == nil {
+		end = start
+	}
+
+	p := start
+	for {
+		again := false
+		if !p.steiner && (earcutEquals(p, p.next) || earcutArea(p.prev, p, p.next) == 0) {
+			earcutRemoveNode(p)
+			p = p.prev
+			end = p
+			if p == p.next {
+				break
+			}
+			again = true
+		} else {
+			p = p.next
+		}
+		if !again && p == end {
+			break
+		}
+	}
+	return end
+}
+
+// earcutIsValidDiagonal checks if a diagonal between two polygon nodes is valid (lies in the polygon interior).
+func earcutIsValidDiagonal(a, b *earcutNode) bool {
+	return a.next.i != b.i && a.prev.i != b.i && !earcutIntersectsPolygon(a, b) && // doesn't intersect other edges
+		((earcutLocallyInside(a, b) && earcutLocallyInside(b, a) && earcutMiddleInside(a, b) && // locally visible
+			(earcutArea(a.prev, a, b.prev) != 0 || earcutArea(a, b.prev, b) != 0)) || // does not create opposite-facing sectors
+			(earcutEquals(a, b) && earcutArea(a.prev, a, a.next) > 0 && earcutArea(b.prev, b, b.next) > 0)) // special zero-length case
+}
+
+// earcutArea returns the signed area of a triangle.
+func earcutArea(p, q, r *earcutNode) float64 {
+	return (q.y-p.y)*(r.x-q.x) - (q.x-p.x)*(r.y-q.y)
+}
+
+// earcutEquals checks if two points are equal.
+func earcutEquals(p1, p2 *earcutNode) bool {
+	return p1.x == p2.x && p1.y == p2.y
+}
+
+// earcutPointInTriangle checks if a point lies within a convex triangle.
+func earcutPointInTriangle(ax, ay, bx, by, cx, cy, px, py float64) bool {
+	return (cx-px)*(ay-py) >= (ax-px)*(cy-py) &&
+		(ax-px)*(by-py) >= (bx-px)*(ay-py) &&
+		(bx-px)*(cy-py) >= (cx-px)*(by-py)
+}
+
+// earcutIntersects checks if two segments intersect.
+func earcutIntersects(p1, q1, p2, q2 *earcutNode) bool {
+	o1 := earcutSign(earcutArea(p1, q1, p2))
+	o2 := earcutSign(earcutArea(p1, q1, q2))
+	o3 := earcutSign(earcutArea(p2, q2, p1))
+	o4 := earcutSign(earcutArea(p2, q2, q1))
+
+	if o1 != o2 && o3 != o4 {
+		return true // general case
+	}
+	return (o1 == 0 && earcutOnSegment(p1, p2, q1)) || // p1, q1 and p2 are collinear and p2 lies on p1q1
+		(o2 == 0 && earcutOnSegment(p1, q2, q1)) || // p1, q1 and q2 are collinear and q2 lies on p1q1
+		(o3 == 0 && earcutOnSegment(p2, p1, q2)) || // p2, q2 and p1 are collinear and p1 lies on p2q2
+		(o4 == 0 && earcutOnSegment(p2, q1, q2)) // p2, q2 and q1 are collinear and q1 lies on p2q2
+}
+
+// earcutOnSegment checks if point q lies on segment pr, for three collinear points.
+func earcutOnSegment(p, q, r *earcutNode) bool {
+	return q.x <= math.Max(p.x, r.x) && q.x >= math.Min(p.x, r.x) &&
+		q.y <= math.Max(p.y, r.y) && q.y >= math.Min(p.y, r.y)
+}
+
+func earcutSign(v float64) int {
+	if v > 0 {
+		return 1
+	}
+	if v < 0 {
+		return -1
+	}
+	return 0
+}
+
+// earcutIntersectsPolygon checks if a polygon diagonal intersects any polygon segments.
+func earcutIntersectsPolygon(a, b *earcutNode) bool {
+	p := a
+	for {
+		if p.i != a.i && p.next.i != a.i && p.i != b.i && p.next.i != b.i &&
+			earcutIntersects(p, p.next, a, b) {
+			return true
+		}
+		p = p.next
+		if p == a {
+			return false
+		}
+	}
+}
+
+// earcutLocallyInside checks if a polygon diagonal is locally inside the polygon.
+func earcutLocallyInside(a, b *earcutNode) bool {
+	if earcutArea(a.prev, a, a.next) < 0 {
+		return earcutArea(a, b, a.next) >= 0 && earcutArea(a, a.prev, b) >= 0
+	}
+	return earcutArea(a, b, a.prev) < 0 || earcutArea(a, a.next, b) < 0
+}
+
+// earcutMiddleInside checks if the middle point of a polygon diagonal is inside the polygon.
+func earcutMiddleInside(a, b *earcutNode) bool {
+	p := a
+	inside := false
+	px := (a.x + b.x) / 2
+	py := (a.y + b.y) / 2
+	for {
+		if ((p.y > py) != (p.next.y > py)) && p.next.y != p.y &&
+			(px < (p.next.x-p.x)*(py-p.y)/(p.next.y-p.y)+p.x) {
+			inside = !inside
+		}
+		p = p.next
+		if p == a {
+			return inside
+		}
+	}
+}
+
+// earcutSplitPolygon links two polygon vertices with a bridge.
+// If the vertices belong to the same ring, it splits the polygon into two.
+// If one belongs to the outer ring and another to a hole, it merges it into a single ring.
+func earcutSplitPolygon(a, b *earcutNode) *earcutNode {
+	a2 := &earcutNode{i: a.i, x: a.x, y: a.y}
+	b2 := &earcutNode{i: b.i, x: b.x, y: b.y}
+	an := a.next
+	bp := b.prev
+
+	a.next = b
+	b.prev = a
+
+	a2.next = an
+	an.prev = a2
+
+	b2.next = a2
+	a2.prev = b2
+
+	bp.next = b2
+	b2.prev = bp
+
+	return b2
+}
+
+// earcutInsertNode creates a node and links it with the previous one (in a circular doubly linked list).
+func earcutInsertNode(i uint32, v Vec2f, last *earcutNode) *earcutNode {
+	p := &earcutNode{i: i, x: float64(v[0]), y: float64(v[1])}
+	if last == nil {
+		p.prev = p
+		p.next = p
+	} else {
+		p.next = last.next
+		p.prev = last
+		last.next.prev = p
+		last.next = p
+	}
+	return p
+}
+
+// earcutRemoveNode unlinks a node from its list.
+func earcutRemoveNode(p *earcutNode) {
+	p.next.prev = p.prev
+	p.prev.next = p.next
+}
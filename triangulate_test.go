@@ -0,0 +1,104 @@
+package vmath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// checkTriangulation verifies that the triangles are counter-clockwise and cover the expected area.
+func checkTriangulation(t *testing.T, indices []uint32, expectedArea float32, rings ...Polygon2f) {
+	t.Helper()
+	var vertices Polygon2f
+	for _, ring := range rings {
+		vertices = append(vertices, ring...)
+	}
+	assert.Equal(t, 0, len(indices)%3)
+
+	var area float32
+	for i := 0; i < len(indices); i += 3 {
+		tri := Polygon2f{vertices[indices[i]], vertices[indices[i+1]], vertices[indices[i+2]]}
+		a := tri.SignedArea()
+		assert.True(t, a >= 0, "triangle %d is clockwise", i/3)
+		area += a
+	}
+	assert.InEpsilon(t, expectedArea, area, 1e-5)
+}
+
+func TestTriangulatePolygon(t *testing.T) {
+	square := Polygon2f{{0, 0}, {4, 0}, {4, 4}, {0, 4}}
+	indices := square.Triangulate()
+	assert.Len(t, indices, 6)
+	checkTriangulation(t, indices, 16, square)
+
+	// clockwise
+	cw := square.Reverse()
+	indices = TriangulatePolygon(cw)
+	assert.Len(t, indices, 6)
+	checkTriangulation(t, indices, 16, cw)
+
+	// concave
+	u := Polygon2f{{0, 0}, {3, 0}, {3, 3}, {2, 3}, {2, 1}, {1, 1}, {1, 3}, {0, 3}}
+	indices = TriangulatePolygon(u)
+	assert.Len(t, indices, 6*3)
+	checkTriangulation(t, indices, u.Area(), u)
+
+	// pentagram-like star (simple)
+	star := Polygon2f{{0, 10}, {-2, 3}, {-9, 3}, {-3, -1}, {-6, -8}, {0, -4}, {6, -8}, {3, -1}, {9, 3}, {2, 3}}
+	indices = TriangulatePolygon(star)
+	assert.Len(t, indices, 8*3)
+	checkTriangulation(t, indices, star.Area(), star)
+}
+
+func TestTriangulatePolygon_Holes(t *testing.T) {
+	outer := Polygon2f{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+	hole1 := Polygon2f{{2, 2}, {2, 4}, {4, 4}, {4, 2}}
+	hole2 := Polygon2f{{6, 6}, {8, 6}, {8, 8}, {6, 8}} // winding order is irrelevant
+
+	indices := TriangulatePolygon(outer, hole1, hole2)
+	assert.Len(t, indices, (12+2*2-2)*3)
+	checkTriangulation(t, indices, 100-4-4, outer, hole1, hole2)
+
+	// all hole vertices are used
+	used := make(map[uint32]bool)
+	for _, i := range indices {
+		used[i] = true
+	}
+	assert.Len(t, used, 12)
+}
+
+func TestTriangulatePolygon_Degenerate(t *testing.T) {
+	assert.Empty(t, TriangulatePolygon(nil))
+	assert.Empty(t, TriangulatePolygon(Polygon2f{{0, 0}, {1, 1}}))
+	assert.Empty(t, TriangulatePolygon(Polygon2f{{0, 0}, {1, 1}, {2, 2}}))
+
+	// collinear and duplicate vertices
+	p := Polygon2f{{0, 0}, {2, 0}, {2, 0}, {4, 0}, {4, 4}, {4, 4}, {0, 4}, {0, 2}, {0, 0}}
+	indices := TriangulatePolygon(p)
+	checkTriangulation(t, indices, 16, p)
+
+	// hole touching the outer ring
+	outer := Polygon2f{{0, 0}, {4, 0}, {4, 4}, {0, 4}}
+	hole := Polygon2f{{0, 1}, {2, 1}, {2, 2}}
+	indices = TriangulatePolygon(outer, hole)
+	checkTriangulation(t, indices, 15, outer, hole)
+
+	// single-vertex hole (steiner point)
+	indices = TriangulatePolygon(outer, Polygon2f{{1, 1}})
+	assert.Len(t, indices, 4*3)
+	checkTriangulation(t, indices, 16, outer, Polygon2f{{1, 1}})
+}
+
+func TestTriangulatePolygon_Circle(t *testing.T) {
+	outer := make(Polygon2f, 64)
+	hole := make(Polygon2f, 32)
+	for i := range outer {
+		outer[i] = PolarToCartesian2D(10, 2*pi*float32(i)/float32(len(outer)))
+	}
+	for i := range hole {
+		hole[i] = PolarToCartesian2D(4, 2*pi*float32(i)/float32(len(hole))).Add(Vec2f{2, 1})
+	}
+	indices := TriangulatePolygon(outer, hole)
+	assert.Len(t, indices, (64+32)*3)
+	checkTriangulation(t, indices, outer.Area()-hole.Area(), outer, hole)
+}
@@ -184,6 +184,53 @@ func PointToLineSegmentDistance2D(a, b, point Vec2f) float32 {
 	return point.Sub(pb).Length()
 }
 
+// LineIntersection2D returns the point where the infinitely long lines a->b and c->d intersect.
+// Returns false if the lines are parallel.
+func LineIntersection2D(a, b, c, d Vec2f) (Vec2f, bool) {
+	// Source: https://stackoverflow.com/a/565282/2224996
+	r := b.Sub(a)
+	s := d.Sub(c)
+	if r.IsParallel(s) {
+		return Vec2f{}, false
+	}
+	t := c.Sub(a).MagCross(s) / r.MagCross(s)
+	return a.Add(r.MulScalar(t)), true
+}
+
+// PointToLineDistance3D returns the distance between a point and an infinitely long line passing through a and b.
+func PointToLineDistance3D(a, b, point Vec3f) float32 {
+	// Source: http://geomalgorithms.com/a02-_lines.html
+	lineVec := b.Sub(a)
+	pointVec := point.Sub(a)
+	// calc perpendicular base
+	pb := a.Add(pointVec.Project(lineVec))
+
+	return point.Sub(pb).Length()
+}
+
+// PointToLineSegmentDistance3D returns the distance between a point and a line segment between a and b.
+func PointToLineSegmentDistance3D(a, b, point Vec3f) float32 {
+	// Source: http://geomalgorithms.com/a02-_lines.html
+	// Works like `PointToLineSegmentDistance2D`
+	lineVec := b.Sub(a)
+	pointVec := point.Sub(a)
+
+	c1 := pointVec.Dot(lineVec)
+	if c1 <= 0 { // angle >= 90° --> point is before (a)
+		return point.Sub(a).Length()
+	}
+
+	c2 := lineVec.Dot(lineVec)
+	if c2 <= c1 { // point is after (b)
+		return point.Sub(b).Length()
+	}
+
+	// calc perpendicular base
+	ratio := c1 / c2
+	pb := a.Add(lineVec.MulScalar(ratio))
+	return point.Sub(pb).Length()
+}
+
 // IsPointOnLine returns true if the give point lies to the line a->b;
 // Uses the default Epsilon as relative tolerance.
 func IsPointOnLine(a, b Vec2f, point Vec2f) bool {
@@ -132,6 +132,19 @@ func TestLerp(t *testing.T) {
 	AssertFloat(t, 2.5, Lerp(-5, 5, 0.75))
 }
 
+func TestLineIntersection2D(t *testing.T) {
+	p, ok := LineIntersection2D(Vec2f{0, 0}, Vec2f{1, 1}, Vec2f{4, 0}, Vec2f{4, 1})
+	assert.True(t, ok)
+	AssertVec2f(t, Vec2f{4, 4}, p)
+
+	p, ok = LineIntersection2D(Vec2f{0, 2}, Vec2f{1, 2}, Vec2f{-3, 0}, Vec2f{-2, 1})
+	assert.True(t, ok)
+	AssertVec2f(t, Vec2f{-1, 2}, p)
+
+	_, ok = LineIntersection2D(Vec2f{0, 0}, Vec2f{1, 1}, Vec2f{0, 1}, Vec2f{2, 3})
+	assert.False(t, ok)
+}
+
 func TestNormalizeDegrees(t *testing.T) {
 	AssertFloat(t, 0, NormalizeDegrees(0))
 	AssertFloat(t, 0, NormalizeDegrees(360))
@@ -184,6 +197,28 @@ func TestPointToLineSegmentDistance2D(t *testing.T) {
 	AssertFloat(t, 2, PointToLineSegmentDistance2D(a, b, Vec2f{0, 2}))
 }
 
+func TestPointToLineDistance3D(t *testing.T) {
+	a := Vec3f{0, 0, 0}
+	b := Vec3f{1, 0, 0}
+
+	AssertFloat(t, 0, PointToLineDistance3D(a, b, Vec3f{6, 0, 0}))
+	AssertFloat(t, 0, PointToLineDistance3D(a, b, Vec3f{-8, 0, 0}))
+	AssertFloat(t, 5, PointToLineDistance3D(a, b, Vec3f{4, 3, 4}))
+	AssertFloat(t, 2, PointToLineDistance3D(a, b, Vec3f{0, 0, -2}))
+}
+
+func TestPointToLineSegmentDistance3D(t *testing.T) {
+	a := Vec3f{0, 0, 0}
+	b := Vec3f{10, 0, 0}
+
+	AssertFloat(t, 0, PointToLineSegmentDistance3D(a, b, Vec3f{6, 0, 0}))
+	AssertFloat(t, 5, PointToLineSegmentDistance3D(a, b, Vec3f{4, 3, 4}))
+
+	AssertFloat(t, 2, PointToLineSegmentDistance3D(a, b, Vec3f{12, 0, 0}))
+	AssertFloat(t, 5, PointToLineSegmentDistance3D(a, b, Vec3f{10, -3, 4}))
+	AssertFloat(t, 2, PointToLineSegmentDistance3D(a, b, Vec3f{-2, 0, 0}))
+}
+
 func TestPolarToCartesian2D(t *testing.T) {
 	AssertVec2f(t, Vec2f{0, 0}, PolarToCartesian2D(0, pi/2))
 	AssertVec2f(t, Vec2f{0, 1}, PolarToCartesian2D(1, pi/2))
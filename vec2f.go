@@ -29,6 +29,12 @@ func (v Vec2f) Round() Vec2i {
 	return Vec2i{int(math32.Round(v[0])), int(math32.Round(v[1]))}
 }
 
+// Floor returns an integer representation of the vector.
+// Decimals are rounded down, also for negative values.
+func (v Vec2f) Floor() Vec2i {
+	return Vec2i{int(math32.Floor(v[0])), int(math32.Floor(v[1]))}
+}
+
 // Vec3f creates a 3D vector.
 func (v Vec2f) Vec3f(z float32) Vec3f {
 	return Vec3f{v[0], v[1], z}
@@ -195,6 +195,12 @@ func TestVec2f_Round(t *testing.T) {
 	assert.Equal(t, Vec2i{-6, 8}, Vec2f{-5.5, 7.5}.Round())
 }
 
+func TestVec2f_Floor(t *testing.T) {
+	assert.Equal(t, Vec2i{-6, 7}, Vec2f{-6, 7}.Floor())
+	assert.Equal(t, Vec2i{-6, 7}, Vec2f{-5.2, 7.2}.Floor())
+	assert.Equal(t, Vec2i{-6, 7}, Vec2f{-5.8, 7.8}.Floor())
+}
+
 func TestVec2f_Split(t *testing.T) {
 	x, y := Vec2f{6, -9}.Split()
 	AssertFloat(t, 6, x)
@@ -33,6 +33,15 @@ func (v Vec3f) Round() Vec3i {
 		int(math32.Round(v[2]))}
 }
 
+// Floor returns an integer representation of the vector.
+// Decimals are rounded down, also for negative values.
+func (v Vec3f) Floor() Vec3i {
+	return Vec3i{
+		int(math32.Floor(v[0])),
+		int(math32.Floor(v[1])),
+		int(math32.Floor(v[2]))}
+}
+
 // Vec4f creates a 4D vector.
 func (v Vec3f) Vec4f(w float32) Vec4f {
 	return Vec4f{v[0], v[1], v[2], w}
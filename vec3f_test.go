@@ -206,6 +206,12 @@ func TestVec3f_Round(t *testing.T) {
 	assert.Equal(t, Vec3i{-6, 8, 4}, Vec3f{-5.5, 7.5, 3.5}.Round())
 }
 
+func TestVec3f_Floor(t *testing.T) {
+	assert.Equal(t, Vec3i{-6, 7, 5}, Vec3f{-6, 7, 5}.Floor())
+	assert.Equal(t, Vec3i{-6, 7, 5}, Vec3f{-5.2, 7.2, 5.2}.Floor())
+	assert.Equal(t, Vec3i{-6, 7, 9}, Vec3f{-5.8, 7.8, 9.7}.Floor())
+}
+
 func TestVec3f_Split(t *testing.T) {
 	x, y, z := Vec3f{6, -9, 12}.Split()
 	AssertFloat(t, 6, x)